@@ -1,50 +1,317 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
+
+	"echo/ds"
 
 	"github.com/labstack/echo/v4"
 )
 
-// DynamicArray 动态数组结构体
-type DynamicArray struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Elements []int  `json:"elements"`
-	Capacity int    `json:"capacity"`
-	Size     int    `json:"size"`
+// arrayHeader 动态数组中与元素类型无关的服务端状态：标识、版本、代价统计和锁
+type arrayHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	// 代价统计
+	Counters         ds.OperationCost           `json:"counters"` // 生命周期内的累计计数
+	OpStats          map[string]*OperationStats `json:"-"`
+	Credit           int                        `json:"-"` // 记账法剩余信用
+	InitialPotential int                        `json:"-"`
+	phiBefore        int
+
+	state *ds.ArrayState // 指向数组的容量状态、计数和追踪
+
+	mu      sync.Mutex // 串行化对同一数组的操作，不同数组之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// DynamicArray 动态数组结构体，数据结构操作由 ds.Array 实现，T 为创建时选定的元素类型
+type DynamicArray[T any] struct {
+	arrayHeader
+	*ds.Array[T]
+	History History[T] `json:"-"` // 撤销/重做历史
+}
+
+// arrayResource 与元素类型无关的数组接口，处理函数通过它操作任意元素类型的 DynamicArray[T]；
+// 值以 any 传递，其实际类型总是该数组的元素类型
+type arrayResource interface {
+	header() *arrayHeader
+	parseValue(s string) (any, error)
+	decodeValue(raw json.RawMessage) (any, error)
+	insertAny(index int, value any) (*ds.ResizeEvent, error)
+	removeAny(index int) (any, *ds.ResizeEvent, error)
+	setAny(index int, value any) (any, error)
+	indexOfAny(value any) int
+	sortElements(req SortRequest) (*SortResult, *ds.Recorder, error)
+	searchAny(name string, value any) (*ds.SearchResult, error)
+	numbers() ([]float64, error)
+	cloneElements() any
+	recordHistory(operation, command string, index int, value, oldValue any)
+	recordReplace(operation string, before any)
+	replayHistory(undo bool) (historyCommand, error)
+	historyView() any
+	snapshot() any
 }
 
 // ArrayRequest 数组操作请求结构体
 type ArrayRequest struct {
-	Name     string `json:"name"`
-	Capacity int    `json:"capacity"`
+	Name            string  `json:"name"`
+	ElementType     string  `json:"elementType"` // int（默认）、float、string、bool 或 object
+	Capacity        int     `json:"capacity"`
+	GrowthStrategy  string  `json:"growthStrategy"`
+	GrowthIncrement int     `json:"growthIncrement"`
+	ShrinkFactor    float64 `json:"shrinkFactor"`
 }
 
-// ElementRequest 元素操作请求结构体
+// ElementRequest 元素操作请求结构体，Value 按数组的元素类型解码
 type ElementRequest struct {
-	Value int `json:"value"`
-	Index int `json:"index"`
+	Value json.RawMessage `json:"value"`
+	Index int             `json:"index"`
 }
 
 // ArrayResponse 数组操作响应结构体
 type ArrayResponse struct {
-	Success bool         `json:"success"`
-	Message string       `json:"message"`
-	Array   *DynamicArray `json:"array,omitempty"`
-	Data    interface{}  `json:"data,omitempty"`
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Array   arrayResource     `json:"array,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Resize  *ds.ResizeEvent   `json:"resize,omitempty"`
+	Cost    *ds.OperationCost `json:"cost,omitempty"`
+	Trace   []ds.TraceStep    `json:"trace,omitempty"`
+
+	Propagated []RangeTreePropagation `json:"propagated,omitempty"` // 修改元素时同步到区间树的结果
+}
+
+// 全局数组存储，后端由 initStorage 根据配置选择
+var arrays Storage[arrayResource] = newMemoryStorage[arrayResource]("array")
+
+// 获取数组并加锁，调用方负责解锁；数组不存在或已被删除时返回 false
+func lockArray(id string) (arrayResource, bool) {
+	res, exists := arrays.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	array := res.header()
+	array.mu.Lock()
+	if array.removed {
+		array.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitArray(c echo.Context, res arrayResource) error {
+	array := res.header()
+	array.Version++
+	if err := arrays.Save(array.ID, res); err != nil {
+		return err
+	}
+	setETag(c, array.Version)
+	return nil
+}
+
+// 按请求创建元素类型为 T 的空数组，容量和扩缩容参数无效时返回错误；ID 由调用方在校验通过后分配
+func newDynamicArray[T any](req ArrayRequest, kind *ds.ElementType[T]) (*DynamicArray[T], error) {
+	core, err := ds.NewArray(ds.ArrayConfig{
+		Capacity:        req.Capacity,
+		GrowthStrategy:  req.GrowthStrategy,
+		GrowthIncrement: req.GrowthIncrement,
+		ShrinkFactor:    req.ShrinkFactor,
+	}, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	array := &DynamicArray[T]{Array: core}
+	array.Name = req.Name
+	array.ElementType = kind.Name
+	array.state = &core.ArrayState
+	array.OpStats = make(map[string]*OperationStats)
+	array.InitialPotential = array.potential()
+	return array, nil
+}
+
+// 数组的持久化快照，附带不在接口中展示的统计数据
+type arraySnapshot[T any] struct {
+	*DynamicArray[T]
+	OpStats          map[string]*OperationStats `json:"opStats"`
+	Credit           int                        `json:"credit"`
+	InitialPotential int                        `json:"initialPotential"`
+	History          History[T]                 `json:"history"`
+}
+
+// 生成数组快照
+func snapshotArray(res arrayResource) any {
+	return res.snapshot()
+}
+
+func (array *DynamicArray[T]) snapshot() any {
+	return arraySnapshot[T]{
+		DynamicArray:     array,
+		OpStats:          array.OpStats,
+		Credit:           array.Credit,
+		InitialPotential: array.InitialPotential,
+		History:          array.History,
+	}
+}
+
+// 从快照恢复数组，按快照中的元素类型分发
+func restoreArray(data []byte) (arrayResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreArray(data)
+}
+
+// 从快照恢复元素类型为 T 的数组，并按容量重新分配底层存储
+func restoreTypedArray[T any](data []byte, kind *ds.ElementType[T]) (*DynamicArray[T], error) {
+	var snapshot arraySnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	array := snapshot.DynamicArray
+	if array == nil || array.Array == nil {
+		return nil, errors.New("快照缺少数组数据")
+	}
+	array.Rebuild(kind)
+	array.ElementType = kind.Name
+	array.state = &array.ArrayState
+
+	array.OpStats = snapshot.OpStats
+	if array.OpStats == nil {
+		array.OpStats = make(map[string]*OperationStats)
+	}
+	array.Credit = snapshot.Credit
+	array.InitialPotential = snapshot.InitialPotential
+	array.History = snapshot.History
+
+	return array, nil
+}
+
+// 将接口传入的值还原为元素类型，nil 表示零值
+func valueOf[T any](value any) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return value.(T)
+}
+
+func (array *DynamicArray[T]) header() *arrayHeader {
+	return &array.arrayHeader
+}
+
+func (array *DynamicArray[T]) parseValue(s string) (any, error) {
+	return array.Kind().Parse(s)
+}
+
+func (array *DynamicArray[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return array.Kind().Decode(raw)
+}
+
+func (array *DynamicArray[T]) insertAny(index int, value any) (*ds.ResizeEvent, error) {
+	return array.Insert(index, valueOf[T](value))
+}
+
+func (array *DynamicArray[T]) removeAny(index int) (any, *ds.ResizeEvent, error) {
+	return array.RemoveAt(index)
+}
+
+func (array *DynamicArray[T]) setAny(index int, value any) (any, error) {
+	return array.Set(index, valueOf[T](value))
+}
+
+func (array *DynamicArray[T]) indexOfAny(value any) int {
+	return array.IndexOf(valueOf[T](value))
+}
+
+func (array *DynamicArray[T]) searchAny(name string, value any) (*ds.SearchResult, error) {
+	return array.Search(name, valueOf[T](value))
+}
+
+// 当前元素的数值，用于建立线段树和树状数组
+func (array *DynamicArray[T]) numbers() ([]float64, error) {
+	return array.Numbers()
+}
+
+// 复制当前元素，用于记录整体替换前的状态
+func (array *DynamicArray[T]) cloneElements() any {
+	return array.Values()
+}
+
+// 历史命令使用的插入接口
+func (array *DynamicArray[T]) insertValue(index int, value T) error {
+	_, err := array.Insert(index, value)
+	return err
+}
+
+// 历史命令使用的删除接口
+func (array *DynamicArray[T]) removeValue(index int) {
+	array.RemoveAt(index)
+}
+
+// 历史命令使用的修改接口
+func (array *DynamicArray[T]) setValue(index int, value T) {
+	array.Set(index, value)
+}
+
+// 历史命令使用的整体替换接口，元素个数保持不变
+func (array *DynamicArray[T]) replaceValues(values []T) {
+	array.Replace(values)
+}
+
+func (array *DynamicArray[T]) recordHistory(operation, command string, index int, value, oldValue any) {
+	array.History.record(operation, command, index, valueOf[T](value), valueOf[T](oldValue))
+}
+
+func (array *DynamicArray[T]) recordReplace(operation string, before any) {
+	array.History.recordReplace(operation, before.([]T), array.Values())
+}
+
+func (array *DynamicArray[T]) replayHistory(undo bool) (historyCommand, error) {
+	var entry *HistoryEntry[T]
+	var err error
+	if undo {
+		entry, err = array.History.undo(array)
+	} else {
+		entry, err = array.History.redo(array)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
 }
 
-// 全局数组存储（实际项目中应使用数据库）
-var arrays = make(map[string]*DynamicArray)
-var arrayCounter = 0
+func (array *DynamicArray[T]) historyView() any {
+	return array.History.view()
+}
 
-// 生成数组ID
-func generateArrayID() string {
-	arrayCounter++
-	return fmt.Sprintf("array_%d", arrayCounter)
+// 最近一次操作的执行追踪
+func (array *arrayHeader) trace() []ds.TraceStep {
+	return array.state.Trace()
+}
+
+// 在操作提示后附加容量变化说明
+func withResizeMessage(message string, event *ds.ResizeEvent) string {
+	if event == nil {
+		return message
+	}
+	if event.Kind == "grow" {
+		return fmt.Sprintf("%s，容量由%d扩容至%d，复制了%d个元素", message, event.OldCapacity, event.NewCapacity, event.ElementsCopied)
+	}
+	return fmt.Sprintf("%s，容量由%d缩容至%d，复制了%d个元素", message, event.OldCapacity, event.NewCapacity, event.ElementsCopied)
 }
 
 // 设置动态数组相关路由
@@ -53,33 +320,57 @@ func setupArrayRoutes(g *echo.Group) {
 
 	// 创建数组
 	arrayGroup.POST("", createArray)
-	
+
 	// 获取所有数组
 	arrayGroup.GET("", getAllArrays)
-	
+
 	// 获取指定数组
 	arrayGroup.GET("/:id", getArray)
-	
+
 	// 删除数组
 	arrayGroup.DELETE("/:id", deleteArray)
-	
+
 	// 在指定位置插入元素
 	arrayGroup.POST("/:id/insert", insertElement)
-	
+
 	// 在末尾追加元素
 	arrayGroup.POST("/:id/append", appendElement)
-	
+
 	// 按索引删除元素
 	arrayGroup.DELETE("/:id/index/:index", deleteByIndex)
-	
+
 	// 按值删除元素
 	arrayGroup.DELETE("/:id/value/:value", deleteByValue)
-	
+
 	// 查找元素
 	arrayGroup.GET("/:id/find/:value", findElement)
-	
+
+	// 使用指定算法查找元素
+	arrayGroup.GET("/:id/search/:value", searchElement)
+
 	// 修改元素
 	arrayGroup.PUT("/:id/index/:index", updateElement)
+
+	// 排序
+	arrayGroup.POST("/:id/sort", sortArray)
+
+	// 获取代价统计与摊还分析
+	arrayGroup.GET("/:id/stats", getArrayStats)
+
+	// 撤销最近一次修改
+	arrayGroup.POST("/:id/undo", undoArray)
+
+	// 重做最近一次撤销的修改
+	arrayGroup.POST("/:id/redo", redoArray)
+
+	// 获取修改历史
+	arrayGroup.GET("/:id/history", getArrayHistory)
+
+	// 以 SSE 回放操作步骤
+	arrayGroup.GET("/:id/ops/stream", streamArrayOperations)
+
+	// 控制回放（暂停、继续、单步、调速）
+	arrayGroup.POST("/:id/ops/control", controlArrayPlayback)
 }
 
 // 创建动态数组
@@ -92,33 +383,64 @@ func createArray(c echo.Context) error {
 		})
 	}
 
-	if req.Capacity <= 0 {
-		req.Capacity = 10 // 默认容量
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: err.Error(),
+		})
 	}
+	req.ElementType = elementType
 
-	id := generateArrayID()
-	array := &DynamicArray{
-		ID:       id,
-		Name:     req.Name,
-		Elements: make([]int, 0, req.Capacity),
-		Capacity: req.Capacity,
-		Size:     0,
+	res, err := factory.newArray(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: err.Error(),
+		})
 	}
 
-	arrays[id] = array
+	id, err := arrays.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组ID生成失败",
+		})
+	}
+	array := res.header()
+	array.ID = id
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	array.mu.Lock()
+	defer array.mu.Unlock()
+
+	if err := commitArray(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组保存失败",
+		})
+	}
 
 	return c.JSON(http.StatusCreated, ArrayResponse{
 		Success: true,
 		Message: "数组创建成功",
-		Array:   array,
+		Array:   res,
 	})
 }
 
 // 获取所有数组
 func getAllArrays(c echo.Context) error {
-	arrayList := make([]*DynamicArray, 0, len(arrays))
-	for _, array := range arrays {
-		arrayList = append(arrayList, array)
+	// 逐个加锁序列化，避免读到正在修改的数组
+	arrayList := make([]json.RawMessage, 0)
+	for _, res := range arrays.List() {
+		array := res.header()
+		array.mu.Lock()
+		data, err := json.Marshal(res)
+		array.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		arrayList = append(arrayList, data)
 	}
 
 	return c.JSON(http.StatusOK, ArrayResponse{
@@ -131,33 +453,45 @@ func getAllArrays(c echo.Context) error {
 // 获取指定数组
 func getArray(c echo.Context) error {
 	id := c.Param("id")
-	array, exists := arrays[id]
+	res, exists := lockArray(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, ArrayResponse{
 			Success: false,
 			Message: "数组不存在",
 		})
 	}
+	array := res.header()
+	defer array.mu.Unlock()
 
+	setETag(c, array.Version)
 	return c.JSON(http.StatusOK, ArrayResponse{
 		Success: true,
 		Message: "获取数组成功",
-		Array:   array,
+		Array:   res,
 	})
 }
 
 // 删除数组
 func deleteArray(c echo.Context) error {
 	id := c.Param("id")
-	_, exists := arrays[id]
+	res, exists := lockArray(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, ArrayResponse{
 			Success: false,
 			Message: "数组不存在",
 		})
 	}
+	array := res.header()
+	defer array.mu.Unlock()
 
-	delete(arrays, id)
+	if _, err := arrays.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组删除失败",
+		})
+	}
+	array.removed = true
+	closePlayer("arrays/" + id)
 
 	return c.JSON(http.StatusOK, ArrayResponse{
 		Success: true,
@@ -168,13 +502,24 @@ func deleteArray(c echo.Context) error {
 // 在指定位置插入元素
 func insertElement(c echo.Context) error {
 	id := c.Param("id")
-	array, exists := arrays[id]
+	res, exists := lockArray(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, ArrayResponse{
 			Success: false,
 			Message: "数组不存在",
 		})
 	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	if !ifMatchSatisfied(c, array.Version) {
+		setETag(c, array.Version)
+		return c.JSON(http.StatusPreconditionFailed, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("数组已被修改（当前版本%d），请刷新后重试", array.Version),
+			Array:   res,
+		})
+	}
 
 	var req ElementRequest
 	if err := c.Bind(&req); err != nil {
@@ -184,43 +529,68 @@ func insertElement(c echo.Context) error {
 		})
 	}
 
-	if req.Index < 0 || req.Index > array.Size {
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, ArrayResponse{
 			Success: false,
-			Message: "插入位置无效",
+			Message: err.Error(),
 		})
 	}
 
-	if array.Size >= array.Capacity {
+	// 在指定位置插入元素
+	array.beginOperation()
+	resize, err := res.insertAny(req.Index, value)
+	if err != nil {
+		message := "数组已满，无法插入"
+		if errors.Is(err, ds.ErrIndexOutOfRange) {
+			message = "插入位置无效"
+		}
 		return c.JSON(http.StatusBadRequest, ArrayResponse{
 			Success: false,
-			Message: "数组已满，无法插入",
+			Message: message,
 		})
 	}
+	cost := array.endOperation("insert", 1, 0)
+	res.recordHistory("insert", CommandInsert, req.Index, value, nil)
 
-	// 在指定位置插入元素
-	array.Elements = append(array.Elements, 0)
-	copy(array.Elements[req.Index+1:], array.Elements[req.Index:])
-	array.Elements[req.Index] = req.Value
-	array.Size++
+	if err := commitArray(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组保存失败",
+		})
+	}
 
 	return c.JSON(http.StatusOK, ArrayResponse{
 		Success: true,
-		Message: "元素插入成功",
-		Array:   array,
+		Message: withResizeMessage("元素插入成功", resize),
+		Array:   res,
+		Resize:  resize,
+		Cost:    cost,
+		Trace:   array.trace(),
 	})
 }
 
 // 在末尾追加元素
 func appendElement(c echo.Context) error {
 	id := c.Param("id")
-	array, exists := arrays[id]
+	res, exists := lockArray(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, ArrayResponse{
 			Success: false,
 			Message: "数组不存在",
 		})
 	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	if !ifMatchSatisfied(c, array.Version) {
+		setETag(c, array.Version)
+		return c.JSON(http.StatusPreconditionFailed, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("数组已被修改（当前版本%d），请刷新后重试", array.Version),
+			Array:   res,
+		})
+	}
 
 	var req ElementRequest
 	if err := c.Bind(&req); err != nil {
@@ -230,37 +600,67 @@ func appendElement(c echo.Context) error {
 		})
 	}
 
-	if array.Size >= array.Capacity {
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	array.beginOperation()
+	resize, err := res.insertAny(array.state.Size, value)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, ArrayResponse{
 			Success: false,
 			Message: "数组已满，无法追加",
 		})
 	}
+	cost := array.endOperation("append", 1, 0)
+	res.recordHistory("append", CommandInsert, array.state.Size-1, value, nil)
 
-	array.Elements = append(array.Elements, req.Value)
-	array.Size++
+	if err := commitArray(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组保存失败",
+		})
+	}
 
 	return c.JSON(http.StatusOK, ArrayResponse{
 		Success: true,
-		Message: "元素追加成功",
-		Array:   array,
+		Message: withResizeMessage("元素追加成功", resize),
+		Array:   res,
+		Resize:  resize,
+		Cost:    cost,
+		Trace:   array.trace(),
 	})
 }
 
 // 按索引删除元素
 func deleteByIndex(c echo.Context) error {
 	id := c.Param("id")
-	array, exists := arrays[id]
+	res, exists := lockArray(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, ArrayResponse{
 			Success: false,
 			Message: "数组不存在",
 		})
 	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	if !ifMatchSatisfied(c, array.Version) {
+		setETag(c, array.Version)
+		return c.JSON(http.StatusPreconditionFailed, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("数组已被修改（当前版本%d），请刷新后重试", array.Version),
+			Array:   res,
+		})
+	}
 
 	indexStr := c.Param("index")
 	index, err := strconv.Atoi(indexStr)
-	if err != nil || index < 0 || index >= array.Size {
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, ArrayResponse{
 			Success: false,
 			Message: "索引无效",
@@ -268,113 +668,172 @@ func deleteByIndex(c echo.Context) error {
 	}
 
 	// 删除指定索引的元素
-	deletedValue := array.Elements[index]
-	copy(array.Elements[index:], array.Elements[index+1:])
-	array.Elements = array.Elements[:array.Size-1]
-	array.Size--
+	array.beginOperation()
+	deletedValue, resize, err := res.removeAny(index)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: "索引无效",
+		})
+	}
+	cost := array.endOperation("delete_index", 0, 1)
+	res.recordHistory("delete_index", CommandDelete, index, deletedValue, nil)
+
+	if err := commitArray(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组保存失败",
+		})
+	}
 
 	return c.JSON(http.StatusOK, ArrayResponse{
 		Success: true,
-		Message: fmt.Sprintf("成功删除索引%d处的元素%d", index, deletedValue),
-		Array:   array,
+		Message: withResizeMessage(fmt.Sprintf("成功删除索引%d处的元素%v", index, deletedValue), resize),
+		Array:   res,
 		Data:    deletedValue,
+		Resize:  resize,
+		Cost:    cost,
+		Trace:   array.trace(),
 	})
 }
 
 // 按值删除元素
 func deleteByValue(c echo.Context) error {
 	id := c.Param("id")
-	array, exists := arrays[id]
+	res, exists := lockArray(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, ArrayResponse{
 			Success: false,
 			Message: "数组不存在",
 		})
 	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	if !ifMatchSatisfied(c, array.Version) {
+		setETag(c, array.Version)
+		return c.JSON(http.StatusPreconditionFailed, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("数组已被修改（当前版本%d），请刷新后重试", array.Version),
+			Array:   res,
+		})
+	}
 
-	valueStr := c.Param("value")
-	value, err := strconv.Atoi(valueStr)
+	value, err := res.parseValue(c.Param("value"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ArrayResponse{
 			Success: false,
-			Message: "值格式错误",
+			Message: fmt.Sprintf("值格式错误：%v", err),
 		})
 	}
 
 	// 查找并删除第一个匹配的元素
-	for i, element := range array.Elements {
-		if element == value {
-			copy(array.Elements[i:], array.Elements[i+1:])
-			array.Elements = array.Elements[:array.Size-1]
-			array.Size--
-
-			return c.JSON(http.StatusOK, ArrayResponse{
-				Success: true,
-				Message: fmt.Sprintf("成功删除值为%d的元素", value),
-				Array:   array,
-				Data:    i,
-			})
-		}
+	array.beginOperation()
+	index := res.indexOfAny(value)
+	if index < 0 {
+		cost := array.endOperation("delete_value", 0, 0)
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到值为%v的元素", value),
+			Cost:    cost,
+			Trace:   array.trace(),
+		})
+	}
+
+	deletedValue, resize, err := res.removeAny(index)
+	if err != nil {
+		return err
 	}
+	cost := array.endOperation("delete_value", 0, 1)
+	res.recordHistory("delete_value", CommandDelete, index, deletedValue, nil)
 
-	return c.JSON(http.StatusNotFound, ArrayResponse{
-		Success: false,
-		Message: fmt.Sprintf("未找到值为%d的元素", value),
+	if err := commitArray(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, ArrayResponse{
+		Success: true,
+		Message: withResizeMessage(fmt.Sprintf("成功删除值为%v的元素", value), resize),
+		Array:   res,
+		Data:    index,
+		Resize:  resize,
+		Cost:    cost,
+		Trace:   array.trace(),
 	})
 }
 
 // 查找元素
 func findElement(c echo.Context) error {
 	id := c.Param("id")
-	array, exists := arrays[id]
+	res, exists := lockArray(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, ArrayResponse{
 			Success: false,
 			Message: "数组不存在",
 		})
 	}
+	array := res.header()
+	defer array.mu.Unlock()
 
-	valueStr := c.Param("value")
-	value, err := strconv.Atoi(valueStr)
+	value, err := res.parseValue(c.Param("value"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ArrayResponse{
 			Success: false,
-			Message: "值格式错误",
+			Message: fmt.Sprintf("值格式错误：%v", err),
 		})
 	}
 
 	// 查找元素
-	for i, element := range array.Elements {
-		if element == value {
-			return c.JSON(http.StatusOK, ArrayResponse{
-				Success: true,
-				Message: fmt.Sprintf("找到值为%d的元素，位于索引%d", value, i),
-				Array:   array,
-				Data:    i,
-			})
-		}
+	array.beginOperation()
+	index := res.indexOfAny(value)
+	cost := array.endOperation("find", 0, 0)
+	if index < 0 {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到值为%v的元素", value),
+			Cost:    cost,
+			Trace:   array.trace(),
+		})
 	}
 
-	return c.JSON(http.StatusNotFound, ArrayResponse{
-		Success: false,
-		Message: fmt.Sprintf("未找到值为%d的元素", value),
+	return c.JSON(http.StatusOK, ArrayResponse{
+		Success: true,
+		Message: fmt.Sprintf("找到值为%v的元素，位于索引%d", value, index),
+		Array:   res,
+		Data:    index,
+		Cost:    cost,
+		Trace:   array.trace(),
 	})
 }
 
 // 修改元素
 func updateElement(c echo.Context) error {
 	id := c.Param("id")
-	array, exists := arrays[id]
+	res, exists := lockArray(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, ArrayResponse{
 			Success: false,
 			Message: "数组不存在",
 		})
 	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	if !ifMatchSatisfied(c, array.Version) {
+		setETag(c, array.Version)
+		return c.JSON(http.StatusPreconditionFailed, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("数组已被修改（当前版本%d），请刷新后重试", array.Version),
+			Array:   res,
+		})
+	}
 
 	indexStr := c.Param("index")
 	index, err := strconv.Atoi(indexStr)
-	if err != nil || index < 0 || index >= array.Size {
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, ArrayResponse{
 			Success: false,
 			Message: "索引无效",
@@ -389,13 +848,204 @@ func updateElement(c echo.Context) error {
 		})
 	}
 
-	oldValue := array.Elements[index]
-	array.Elements[index] = req.Value
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	array.beginOperation()
+	oldValue, err := res.setAny(index, value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: "索引无效",
+		})
+	}
+	cost := array.endOperation("update", 0, 0)
+	res.recordHistory("update", CommandUpdate, index, value, oldValue)
+
+	if err := commitArray(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组保存失败",
+		})
+	}
+
+	// ?propagate=true 时把新值同步到由该数组建立的线段树和树状数组
+	var propagated []RangeTreePropagation
+	if c.QueryParam("propagate") == "true" {
+		if values, err := res.numbers(); err == nil {
+			propagated = propagateToRangeTrees(id, array.Version-1, array.Version, index, values)
+		}
+	}
+
+	return c.JSON(http.StatusOK, ArrayResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("成功将索引%d处的元素从%v修改为%v", index, oldValue, value),
+		Array:      res,
+		Data:       oldValue,
+		Cost:       cost,
+		Trace:      array.trace(),
+		Propagated: propagated,
+	})
+}
+
+// 在数组锁内取得回放器：删除数组时在同一把锁内关闭回放器，
+// 因此这里取得（或新建）的回放器一定会在数组删除时被关闭，不会遗留
+func arrayPlayer(id string) (*opPlayer, bool) {
+	res, exists := lockArray(id)
+	if !exists {
+		return nil, false
+	}
+	defer res.header().mu.Unlock()
+
+	return playerFor("arrays/" + id), true
+}
+
+// 以 SSE 回放数组的操作步骤
+func streamArrayOperations(c echo.Context) error {
+	id := c.Param("id")
+	player, exists := arrayPlayer(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: "数组不存在",
+		})
+	}
+
+	if delayStr := c.QueryParam("delay"); delayStr != "" {
+		delay, err := strconv.Atoi(delayStr)
+		if err == nil {
+			err = player.control(PlaybackControlRequest{Action: PlaybackSpeed, Delay: delay})
+		}
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ArrayResponse{
+				Success: false,
+				Message: "回放间隔无效",
+			})
+		}
+	}
+
+	return streamOperations(c, player, id)
+}
+
+// 控制数组操作的回放
+func controlArrayPlayback(c echo.Context) error {
+	id := c.Param("id")
+	player, exists := arrayPlayer(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: "数组不存在",
+		})
+	}
+
+	var req PlaybackControlRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	if err := player.control(req); err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, ArrayResponse{
+		Success: true,
+		Message: "回放控制已生效",
+		Data:    player.state(),
+	})
+}
+
+// 撤销数组最近一次修改
+func undoArray(c echo.Context) error {
+	return replayArrayHistory(c, true)
+}
+
+// 重做数组最近一次撤销的修改
+func redoArray(c echo.Context) error {
+	return replayArrayHistory(c, false)
+}
+
+// 执行撤销或重做，本身也作为一次修改递增版本号并返回执行追踪
+func replayArrayHistory(c echo.Context, undo bool) error {
+	id := c.Param("id")
+	res, exists := lockArray(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: "数组不存在",
+		})
+	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	if !ifMatchSatisfied(c, array.Version) {
+		setETag(c, array.Version)
+		return c.JSON(http.StatusPreconditionFailed, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("数组已被修改（当前版本%d），请刷新后重试", array.Version),
+			Array:   res,
+		})
+	}
+
+	op, action := "redo", "重做"
+	if undo {
+		op, action = "undo", "撤销"
+	}
+
+	array.beginOperation()
+	entry, err := res.replayHistory(undo)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	delta := entry.sizeDelta(undo)
+	cost := array.endOperation(op, max(delta, 0), max(-delta, 0))
+
+	if err := commitArray(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, ArrayResponse{
+		Success: true,
+		Message: fmt.Sprintf("已%s%s操作", action, entry.operation()),
+		Array:   res,
+		Data:    entry,
+		Cost:    cost,
+		Trace:   array.trace(),
+	})
+}
+
+// 获取数组的修改历史
+func getArrayHistory(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockArray(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: "数组不存在",
+		})
+	}
+	defer res.header().mu.Unlock()
 
 	return c.JSON(http.StatusOK, ArrayResponse{
 		Success: true,
-		Message: fmt.Sprintf("成功将索引%d处的元素从%d修改为%d", index, oldValue, req.Value),
-		Array:   array,
-		Data:    oldValue,
+		Message: "获取修改历史成功",
+		Data:    res.historyView(),
 	})
 }
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"net/http"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 摊还分析方法
+const (
+	MethodAccounting = "accounting"
+	MethodPotential  = "potential"
+)
+
+// 记账法中每新增一个元素预存的信用：1份支付自身将来被复制的代价，1份支付一个旧元素的复制代价
+const accountingDeposit = 2
+
+// OperationStats 某类操作的累计代价
+type OperationStats struct {
+	Count         int `json:"count"`
+	ActualCost    int `json:"actualCost"`
+	MaxActualCost int `json:"maxActualCost"`
+	Charged       int `json:"charged"`       // 记账法下收取的摊还代价总和
+	PotentialCost int `json:"potentialCost"` // 势能法下摊还代价总和：Σ(实际代价 + ΔΦ)
+}
+
+// AmortizedOperation 某类操作的摊还分析结果
+type AmortizedOperation struct {
+	Count             int     `json:"count"`
+	ActualCost        int     `json:"actualCost"`
+	AverageActualCost float64 `json:"averageActualCost"`
+	MaxActualCost     int     `json:"maxActualCost"`
+	AmortizedCost     int     `json:"amortizedCost"`
+	AmortizedPerOp    float64 `json:"amortizedPerOp"`
+}
+
+// ArrayStatsReport 数组的代价统计报告
+type ArrayStatsReport struct {
+	Method         string                         `json:"method"`
+	Totals         ds.OperationCost               `json:"totals"`
+	TotalCost      int                            `json:"totalCost"`
+	Operations     map[string]*AmortizedOperation `json:"operations"`
+	InitialBalance int                            `json:"initialBalance"` // 记账法为0，势能法为初始势能Φ0
+	Balance        int                            `json:"balance"`        // 记账法为剩余信用，势能法为当前势能Φ
+}
+
+// 动态表的势函数（CLRS 17.4）：装载因子不低于1/2时 Φ = 2n - s，否则 Φ = s/2 - n
+func (array *arrayHeader) potential() int {
+	state := array.state
+	if 2*state.Size >= state.Capacity {
+		return 2*state.Size - state.Capacity
+	}
+	return state.Capacity/2 - state.Size
+}
+
+// 开始一次操作的代价统计与执行追踪
+func (array *arrayHeader) beginOperation() {
+	array.state.Begin()
+	array.phiBefore = array.potential()
+}
+
+// 结束一次操作：累计到生命周期计数和分类统计中，推送追踪供回放，返回本次操作的代价
+func (array *arrayHeader) endOperation(op string, elementsAdded, elementsRemoved int) *ds.OperationCost {
+	cost := array.state.Cost()
+	array.Counters.Add(cost)
+
+	if array.OpStats == nil {
+		array.OpStats = make(map[string]*OperationStats)
+	}
+	stats, ok := array.OpStats[op]
+	if !ok {
+		stats = &OperationStats{}
+		array.OpStats[op] = stats
+	}
+
+	actual := cost.Total()
+	// 记账法：重新分配的复制代价由存下的信用支付，新增元素存入2份信用，删除元素存入1份用于将来的缩容
+	charged := actual - cost.Copies + accountingDeposit*elementsAdded + elementsRemoved
+	array.Credit += charged - actual
+
+	stats.Count++
+	stats.ActualCost += actual
+	stats.MaxActualCost = max(stats.MaxActualCost, actual)
+	stats.Charged += charged
+	stats.PotentialCost += actual + array.potential() - array.phiBefore
+
+	publishTrace("arrays", array.ID, op, array.trace())
+
+	return &cost
+}
+
+// 生成指定分析方法下的统计报告
+func (array *arrayHeader) statsReport(method string) *ArrayStatsReport {
+	report := &ArrayStatsReport{
+		Method:     method,
+		Totals:     array.Counters,
+		TotalCost:  array.Counters.Total(),
+		Operations: make(map[string]*AmortizedOperation, len(array.OpStats)),
+	}
+
+	if method == MethodPotential {
+		report.InitialBalance = array.InitialPotential
+		report.Balance = array.potential()
+	} else {
+		report.Balance = array.Credit
+	}
+
+	for op, stats := range array.OpStats {
+		amortized := stats.Charged
+		if method == MethodPotential {
+			amortized = stats.PotentialCost
+		}
+		report.Operations[op] = &AmortizedOperation{
+			Count:             stats.Count,
+			ActualCost:        stats.ActualCost,
+			AverageActualCost: float64(stats.ActualCost) / float64(stats.Count),
+			MaxActualCost:     stats.MaxActualCost,
+			AmortizedCost:     amortized,
+			AmortizedPerOp:    float64(amortized) / float64(stats.Count),
+		}
+	}
+
+	return report
+}
+
+// 获取数组的代价统计
+func getArrayStats(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockArray(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: "数组不存在",
+		})
+	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	method := c.QueryParam("method")
+	if method == "" {
+		method = MethodAccounting
+	}
+
+	if method != MethodAccounting && method != MethodPotential {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: "分析方法必须是accounting或potential",
+		})
+	}
+
+	return c.JSON(http.StatusOK, ArrayResponse{
+		Success: true,
+		Message: "获取代价统计成功",
+		Array:   res,
+		Data:    array.statsReport(method),
+	})
+}
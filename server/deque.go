@@ -0,0 +1,731 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// dequeHeader 队列和双端队列中与元素类型无关的服务端状态：标识、版本和锁
+type dequeHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	recorder *ds.Recorder // 底层结构最近一次操作的计数和追踪
+
+	mu      sync.Mutex // 串行化对同一队列的操作，不同队列之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// Deque 队列和双端队列共用的结构体，操作由 ds.Deque 实现；同时给出抽象视图和底层存储视图
+type Deque[T any] struct {
+	dequeHeader
+	*ds.Deque[T]
+
+	// 抽象视图
+	Size       int `json:"size"`
+	Items      []T `json:"items"` // 从队首到队尾
+	FrontValue *T  `json:"front"` // 空队列为 null
+	RearValue  *T  `json:"rear"`  // 空队列为 null
+
+	Storage DequeStorage[T] `json:"storage"`
+}
+
+// DequeStorage 队列的底层存储视图，按底层结构只填写其中一项
+type DequeStorage[T any] struct {
+	Ring *StorageRing[T] `json:"ring,omitempty"`
+	List *StorageList[T] `json:"list,omitempty"` // 头节点为队首，尾节点为队尾
+}
+
+// StorageRing 作为底层存储的环形缓冲区视图，Slots 为包括空槽位在内的全部槽位
+type StorageRing[T any] struct {
+	*ds.RingState
+	Slots []*T `json:"slots"` // 空槽位为 null
+}
+
+// dequeResource 与元素类型无关的队列接口，处理函数通过它操作任意元素类型的 Deque[T]
+type dequeResource interface {
+	header() *dequeHeader
+	decodeValue(raw json.RawMessage) (any, error)
+	beginOperation()
+	pushAny(front bool, value any) (*ds.ResizeEvent, error)
+	popAny(front bool) (any, error)
+	peekAny(front bool) (any, error)
+	updateVisualizationData()
+	snapshot() any
+}
+
+// DequeRequest 创建队列的请求，backing 为 ring 时可以指定槽位数和扩容策略
+type DequeRequest struct {
+	Name            string `json:"name"`
+	ElementType     string `json:"elementType"`
+	Backing         string `json:"backing"` // ring（默认）或 list
+	Capacity        int    `json:"capacity"`
+	GrowthStrategy  string `json:"growthStrategy"`
+	GrowthIncrement int    `json:"growthIncrement"`
+}
+
+// DequeValueRequest 入队请求，Value 按队列的元素类型解码
+type DequeValueRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// DequeResponse 队列和双端队列操作响应结构体，按资源类型填写 Queue 或 Deque
+type DequeResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Queue   dequeResource     `json:"queue,omitempty"`
+	Deque   dequeResource     `json:"deque,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Resize  *ds.ResizeEvent   `json:"resize,omitempty"`
+	Cost    *ds.OperationCost `json:"cost,omitempty"`
+	Trace   []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局队列和双端队列存储，后端由 initStorage 根据配置选择
+var (
+	queues Storage[dequeResource] = newMemoryStorage[dequeResource]("queue")
+	deques Storage[dequeResource] = newMemoryStorage[dequeResource]("deque")
+)
+
+// dequeKind 区分队列和双端队列：两者实现相同，只是开放的操作和存储不同
+type dequeKind struct {
+	noun    string                  // 提示信息中的名称
+	storage *Storage[dequeResource] // 指向全局存储变量，initStorage 替换后仍然有效
+	isQueue bool
+}
+
+var (
+	queueAPI = &dequeKind{noun: "队列", storage: &queues, isQueue: true}
+	dequeAPI = &dequeKind{noun: "双端队列", storage: &deques}
+)
+
+// 在响应中按资源类型填入队列
+func (kind *dequeKind) respond(resp DequeResponse, res dequeResource) DequeResponse {
+	if kind.isQueue {
+		resp.Queue = res
+	} else {
+		resp.Deque = res
+	}
+	return resp
+}
+
+// 获取队列并加锁，调用方负责解锁；队列不存在或已被删除时返回 false
+func (kind *dequeKind) lock(id string) (dequeResource, bool) {
+	res, exists := (*kind.storage).Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	deque := res.header()
+	deque.mu.Lock()
+	if deque.removed {
+		deque.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func (kind *dequeKind) commit(c echo.Context, res dequeResource) error {
+	deque := res.header()
+	deque.Version++
+	if err := (*kind.storage).Save(deque.ID, res); err != nil {
+		return err
+	}
+	setETag(c, deque.Version)
+	return nil
+}
+
+// 底层环形缓冲区的配置
+func (req DequeRequest) ringConfig() ds.ArrayConfig {
+	return ds.ArrayConfig{
+		Capacity:        req.Capacity,
+		GrowthStrategy:  req.GrowthStrategy,
+		GrowthIncrement: req.GrowthIncrement,
+	}
+}
+
+// 按请求创建元素类型为 T 的空队列，底层结构或环形缓冲区参数无效时返回错误；ID 由调用方在校验通过后分配
+func newDeque[T any](req DequeRequest, kind *ds.ElementType[T]) (*Deque[T], error) {
+	core, err := ds.NewDeque(req.Backing, req.ringConfig(), kind)
+	if err != nil {
+		return nil, err
+	}
+	return wrapDeque("", req.Name, core), nil
+}
+
+// 为 ds.Deque 附加服务端状态
+func wrapDeque[T any](id, name string, core *ds.Deque[T]) *Deque[T] {
+	deque := &Deque[T]{Deque: core}
+	deque.ID = id
+	deque.Name = name
+	deque.ElementType = core.Kind().Name
+	deque.recorder = core.Recorder()
+	deque.updateVisualizationData()
+	return deque
+}
+
+// 队列的持久化快照，元素按从队首到队尾的顺序保存
+type dequeSnapshot[T any] struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	ElementType string         `json:"elementType"`
+	Version     int64          `json:"version"`
+	Backing     string         `json:"backing"`
+	Ring        ds.ArrayConfig `json:"ring"` // 环形缓冲区的当前槽位数和扩容参数
+	Head        int            `json:"head"` // 队首所在槽位，恢复后保持原来的回绕位置
+	Values      []T            `json:"values"`
+}
+
+// 生成队列快照
+func snapshotDeque(res dequeResource) any {
+	return res.snapshot()
+}
+
+func (deque *Deque[T]) snapshot() any {
+	snapshot := dequeSnapshot[T]{
+		ID:          deque.ID,
+		Name:        deque.Name,
+		ElementType: deque.ElementType,
+		Version:     deque.Version,
+		Backing:     deque.Backing,
+		Values:      deque.Values(),
+	}
+	if ring := deque.Ring(); ring != nil {
+		snapshot.Ring = ring.Config()
+		snapshot.Head = ring.Head
+	}
+	return snapshot
+}
+
+// 从快照恢复队列，按快照中的元素类型分发
+func restoreDeque(data []byte) (dequeResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreDeque(data)
+}
+
+// 从快照重建元素类型为 T 的队列
+func restoreTypedDeque[T any](data []byte, kind *ds.ElementType[T]) (*Deque[T], error) {
+	var snapshot dequeSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreDeque(snapshot.Backing, snapshot.Ring, snapshot.Head, snapshot.Values, kind)
+	if err != nil {
+		return nil, err
+	}
+	deque := wrapDeque(snapshot.ID, snapshot.Name, core)
+	deque.Version = snapshot.Version
+	return deque, nil
+}
+
+func (deque *Deque[T]) header() *dequeHeader {
+	return &deque.dequeHeader
+}
+
+func (deque *Deque[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return deque.Kind().Decode(raw)
+}
+
+func (deque *Deque[T]) beginOperation() {
+	deque.Begin()
+}
+
+func (deque *Deque[T]) pushAny(front bool, value any) (*ds.ResizeEvent, error) {
+	if front {
+		return deque.PushFront(valueOf[T](value))
+	}
+	return deque.PushBack(valueOf[T](value))
+}
+
+func (deque *Deque[T]) popAny(front bool) (any, error) {
+	if front {
+		return deque.PopFront()
+	}
+	return deque.PopBack()
+}
+
+func (deque *Deque[T]) peekAny(front bool) (any, error) {
+	if front {
+		return deque.Front()
+	}
+	return deque.Back()
+}
+
+// 更新队列的抽象视图和底层存储视图
+func (deque *Deque[T]) updateVisualizationData() {
+	deque.Items = deque.Values()
+	deque.Size = len(deque.Items)
+	deque.FrontValue, deque.RearValue = nil, nil
+	if deque.Size > 0 {
+		deque.FrontValue = &deque.Items[0]
+		deque.RearValue = &deque.Items[deque.Size-1]
+	}
+
+	deque.Storage = DequeStorage[T]{}
+	if ring := deque.Ring(); ring != nil {
+		deque.Storage.Ring = &StorageRing[T]{
+			RingState: &ring.RingState,
+			Slots:     ring.Slots(),
+		}
+	}
+	if list := deque.List(); list != nil {
+		deque.Storage.List = &StorageList[T]{
+			Type:  list.Type,
+			Size:  list.Size,
+			Nodes: nodeViews(deque.ID, list),
+		}
+	}
+}
+
+// 设置队列相关路由
+func setupQueueRoutes(g *echo.Group) {
+	queueGroup := g.Group("/queues")
+
+	// 创建队列
+	queueGroup.POST("", createQueue)
+
+	// 获取所有队列
+	queueGroup.GET("", getAllQueues)
+
+	// 获取指定队列
+	queueGroup.GET("/:id", getQueue)
+
+	// 删除队列
+	queueGroup.DELETE("/:id", deleteQueue)
+
+	// 入队（队尾）
+	queueGroup.POST("/:id/enqueue", enqueue)
+
+	// 出队（队首）
+	queueGroup.POST("/:id/dequeue", dequeue)
+
+	// 查看队首
+	queueGroup.GET("/:id/front", getQueueFront)
+
+	// 查看队尾
+	queueGroup.GET("/:id/rear", getQueueRear)
+}
+
+// 设置双端队列相关路由
+func setupDequeRoutes(g *echo.Group) {
+	dequeGroup := g.Group("/deques")
+
+	// 创建双端队列
+	dequeGroup.POST("", createDeque)
+
+	// 获取所有双端队列
+	dequeGroup.GET("", getAllDeques)
+
+	// 获取指定双端队列
+	dequeGroup.GET("/:id", getDeque)
+
+	// 删除双端队列
+	dequeGroup.DELETE("/:id", deleteDeque)
+
+	// 从队首加入
+	dequeGroup.POST("/:id/push_front", pushDequeFront)
+
+	// 从队尾加入
+	dequeGroup.POST("/:id/push_back", pushDequeBack)
+
+	// 从队首取出
+	dequeGroup.POST("/:id/pop_front", popDequeFront)
+
+	// 从队尾取出
+	dequeGroup.POST("/:id/pop_back", popDequeBack)
+
+	// 查看队首
+	dequeGroup.GET("/:id/front", getDequeFront)
+
+	// 查看队尾
+	dequeGroup.GET("/:id/back", getDequeBack)
+}
+
+// 创建队列
+func createQueue(c echo.Context) error {
+	return createDequeOf(c, queueAPI)
+}
+
+// 获取所有队列
+func getAllQueues(c echo.Context) error {
+	return listDequesOf(c, queueAPI)
+}
+
+// 获取指定队列
+func getQueue(c echo.Context) error {
+	return getDequeOf(c, queueAPI)
+}
+
+// 删除队列
+func deleteQueue(c echo.Context) error {
+	return deleteDequeOf(c, queueAPI)
+}
+
+// 入队：从队尾加入
+func enqueue(c echo.Context) error {
+	return pushDequeOf(c, queueAPI, false)
+}
+
+// 出队：从队首取出
+func dequeue(c echo.Context) error {
+	return popDequeOf(c, queueAPI, true)
+}
+
+// 查看队首元素
+func getQueueFront(c echo.Context) error {
+	return peekDequeOf(c, queueAPI, true)
+}
+
+// 查看队尾元素
+func getQueueRear(c echo.Context) error {
+	return peekDequeOf(c, queueAPI, false)
+}
+
+// 创建双端队列
+func createDeque(c echo.Context) error {
+	return createDequeOf(c, dequeAPI)
+}
+
+// 获取所有双端队列
+func getAllDeques(c echo.Context) error {
+	return listDequesOf(c, dequeAPI)
+}
+
+// 获取指定双端队列
+func getDeque(c echo.Context) error {
+	return getDequeOf(c, dequeAPI)
+}
+
+// 删除双端队列
+func deleteDeque(c echo.Context) error {
+	return deleteDequeOf(c, dequeAPI)
+}
+
+// 从双端队列队首加入
+func pushDequeFront(c echo.Context) error {
+	return pushDequeOf(c, dequeAPI, true)
+}
+
+// 从双端队列队尾加入
+func pushDequeBack(c echo.Context) error {
+	return pushDequeOf(c, dequeAPI, false)
+}
+
+// 从双端队列队首取出
+func popDequeFront(c echo.Context) error {
+	return popDequeOf(c, dequeAPI, true)
+}
+
+// 从双端队列队尾取出
+func popDequeBack(c echo.Context) error {
+	return popDequeOf(c, dequeAPI, false)
+}
+
+// 查看双端队列队首元素
+func getDequeFront(c echo.Context) error {
+	return peekDequeOf(c, dequeAPI, true)
+}
+
+// 查看双端队列队尾元素
+func getDequeBack(c echo.Context) error {
+	return peekDequeOf(c, dequeAPI, false)
+}
+
+// 队首或队尾的名称
+func endName(front bool) string {
+	if front {
+		return "队首"
+	}
+	return "队尾"
+}
+
+// 创建队列或双端队列
+func createDequeOf(c echo.Context, kind *dequeKind) error {
+	var req DequeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, DequeResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DequeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	req.ElementType = elementType
+
+	res, err := factory.newDeque(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DequeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	id, err := (*kind.storage).NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, DequeResponse{
+			Success: false,
+			Message: kind.noun + "ID生成失败",
+		})
+	}
+	deque := res.header()
+	deque.ID = id
+	res.updateVisualizationData()
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	deque.mu.Lock()
+	defer deque.mu.Unlock()
+
+	if err := kind.commit(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, DequeResponse{
+			Success: false,
+			Message: kind.noun + "保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, kind.respond(DequeResponse{
+		Success: true,
+		Message: kind.noun + "创建成功",
+	}, res))
+}
+
+// 获取所有队列或双端队列
+func listDequesOf(c echo.Context, kind *dequeKind) error {
+	// 逐个加锁序列化，避免读到正在修改的队列
+	dequeList := make([]json.RawMessage, 0)
+	for _, res := range (*kind.storage).List() {
+		deque := res.header()
+		deque.mu.Lock()
+		data, err := json.Marshal(res)
+		deque.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		dequeList = append(dequeList, data)
+	}
+
+	return c.JSON(http.StatusOK, DequeResponse{
+		Success: true,
+		Message: "获取" + kind.noun + "列表成功",
+		Data:    dequeList,
+	})
+}
+
+// 获取指定队列或双端队列
+func getDequeOf(c echo.Context, kind *dequeKind) error {
+	id := c.Param("id")
+	res, exists := kind.lock(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, DequeResponse{
+			Success: false,
+			Message: kind.noun + "不存在",
+		})
+	}
+	deque := res.header()
+	defer deque.mu.Unlock()
+
+	setETag(c, deque.Version)
+	return c.JSON(http.StatusOK, kind.respond(DequeResponse{
+		Success: true,
+		Message: "获取" + kind.noun + "成功",
+	}, res))
+}
+
+// 删除队列或双端队列
+func deleteDequeOf(c echo.Context, kind *dequeKind) error {
+	id := c.Param("id")
+	res, exists := kind.lock(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, DequeResponse{
+			Success: false,
+			Message: kind.noun + "不存在",
+		})
+	}
+	deque := res.header()
+	defer deque.mu.Unlock()
+
+	if _, err := (*kind.storage).Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, DequeResponse{
+			Success: false,
+			Message: kind.noun + "删除失败",
+		})
+	}
+	deque.removed = true
+
+	return c.JSON(http.StatusOK, DequeResponse{
+		Success: true,
+		Message: kind.noun + "删除成功",
+	})
+}
+
+// 从队首或队尾加入元素
+func pushDequeOf(c echo.Context, kind *dequeKind, front bool) error {
+	id := c.Param("id")
+	res, exists := kind.lock(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, DequeResponse{
+			Success: false,
+			Message: kind.noun + "不存在",
+		})
+	}
+	deque := res.header()
+	defer deque.mu.Unlock()
+
+	if !ifMatchSatisfied(c, deque.Version) {
+		setETag(c, deque.Version)
+		return c.JSON(http.StatusPreconditionFailed, kind.respond(DequeResponse{
+			Success: false,
+			Message: fmt.Sprintf("%s已被修改（当前版本%d），请刷新后重试", kind.noun, deque.Version),
+		}, res))
+	}
+
+	var req DequeValueRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, DequeResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DequeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	res.beginOperation()
+	resize, err := res.pushAny(front, value)
+	if errors.Is(err, ds.ErrFull) {
+		return c.JSON(http.StatusBadRequest, DequeResponse{
+			Success: false,
+			Message: kind.noun + "已满，无法加入元素",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := deque.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := kind.commit(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, DequeResponse{
+			Success: false,
+			Message: kind.noun + "保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, kind.respond(DequeResponse{
+		Success: true,
+		Message: withResizeMessage(fmt.Sprintf("%v已加入%s", value, endName(front)), resize),
+		Resize:  resize,
+		Cost:    &cost,
+		Trace:   deque.recorder.Trace(),
+	}, res))
+}
+
+// 从队首或队尾取出元素
+func popDequeOf(c echo.Context, kind *dequeKind, front bool) error {
+	id := c.Param("id")
+	res, exists := kind.lock(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, DequeResponse{
+			Success: false,
+			Message: kind.noun + "不存在",
+		})
+	}
+	deque := res.header()
+	defer deque.mu.Unlock()
+
+	if !ifMatchSatisfied(c, deque.Version) {
+		setETag(c, deque.Version)
+		return c.JSON(http.StatusPreconditionFailed, kind.respond(DequeResponse{
+			Success: false,
+			Message: fmt.Sprintf("%s已被修改（当前版本%d），请刷新后重试", kind.noun, deque.Version),
+		}, res))
+	}
+
+	res.beginOperation()
+	value, err := res.popAny(front)
+	if errors.Is(err, ds.ErrEmpty) {
+		return c.JSON(http.StatusBadRequest, DequeResponse{
+			Success: false,
+			Message: kind.noun + "为空，无法取出元素",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := deque.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := kind.commit(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, DequeResponse{
+			Success: false,
+			Message: kind.noun + "保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, kind.respond(DequeResponse{
+		Success: true,
+		Message: fmt.Sprintf("%s元素%v已取出", endName(front), value),
+		Data:    value,
+		Cost:    &cost,
+		Trace:   deque.recorder.Trace(),
+	}, res))
+}
+
+// 查看队首或队尾元素
+func peekDequeOf(c echo.Context, kind *dequeKind, front bool) error {
+	id := c.Param("id")
+	res, exists := kind.lock(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, DequeResponse{
+			Success: false,
+			Message: kind.noun + "不存在",
+		})
+	}
+	deque := res.header()
+	defer deque.mu.Unlock()
+
+	res.beginOperation()
+	value, err := res.peekAny(front)
+	if errors.Is(err, ds.ErrEmpty) {
+		return c.JSON(http.StatusNotFound, DequeResponse{
+			Success: false,
+			Message: kind.noun + "为空",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := deque.recorder.Cost()
+
+	return c.JSON(http.StatusOK, kind.respond(DequeResponse{
+		Success: true,
+		Message: fmt.Sprintf("%s元素为%v", endName(front), value),
+		Data:    value,
+		Cost:    &cost,
+		Trace:   deque.recorder.Trace(),
+	}, res))
+}
@@ -0,0 +1,617 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ForestNodeData 用于前端显示的森林节点，与树节点一样以节点ID表示父指针；节点ID按元素下标生成，各次操作之间不变
+type ForestNodeData[T any] struct {
+	ID       string `json:"id"`
+	Index    int    `json:"index"`
+	Value    T      `json:"value"`
+	ParentID string `json:"parentId,omitempty"` // 根节点为空
+	Rank     *int   `json:"rank,omitempty"`     // 仅按秩合并的根节点
+	Size     *int   `json:"size,omitempty"`     // 仅按大小合并的根节点
+}
+
+// DisjointSetView 某一时刻的父指针数组和森林，用于对比操作前后的变化
+type DisjointSetView[T any] struct {
+	Parent []int                `json:"parent"`
+	Rank   []int                `json:"rank"`
+	Size   []int                `json:"size"`
+	Forest []*ForestNodeData[T] `json:"forest"` // 按元素下标排列
+	Groups [][]T                `json:"groups"` // 各集合的元素
+}
+
+// disjointSetHeader 并查集中与元素类型无关的服务端状态：标识、版本和锁
+type disjointSetHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	recorder *ds.Recorder // 最近一次操作的计数和追踪
+
+	mu      sync.Mutex // 串行化对同一个并查集的操作，不同并查集之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// DisjointSet 并查集结构体，操作由 ds.DisjointSet 实现
+type DisjointSet[T any] struct {
+	disjointSetHeader
+	*ds.DisjointSet[T]
+
+	Forest []*ForestNodeData[T] `json:"forest"`
+	Groups [][]T                `json:"groups"`
+}
+
+// disjointSetResource 与元素类型无关的并查集接口，处理函数通过它操作任意元素类型的 DisjointSet[T]
+type disjointSetResource interface {
+	header() *disjointSetHeader
+	decodeValue(raw json.RawMessage) (any, error)
+	beginOperation()
+	pathCompression() bool
+	makeSetAny(value any) error
+	findAny(value any, compress bool) (any, error)
+	unionAny(a, b any) (bool, error)
+	view() any
+	updateVisualizationData()
+	snapshot() any
+}
+
+// DisjointSetRequest 创建并查集的请求
+type DisjointSetRequest struct {
+	Name            string `json:"name"`
+	ElementType     string `json:"elementType"`
+	UnionBy         string `json:"unionBy"`         // rank（默认）或 size
+	PathCompression bool   `json:"pathCompression"` // 合并时是否压缩路径，也是查找的默认值
+}
+
+// DisjointSetValueRequest 加入元素的请求，Value 按并查集的元素类型解码
+type DisjointSetValueRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// DisjointSetFindRequest 查找请求，Compress 为空时使用创建时的设置
+type DisjointSetFindRequest struct {
+	Value    json.RawMessage `json:"value"`
+	Compress *bool           `json:"compress"`
+}
+
+// DisjointSetUnionRequest 合并请求
+type DisjointSetUnionRequest struct {
+	A json.RawMessage `json:"a"`
+	B json.RawMessage `json:"b"`
+}
+
+// DisjointSetResponse 并查集操作响应结构体，Before 为操作前的父指针数组和森林，操作后的状态见 DisjointSet
+type DisjointSetResponse struct {
+	Success     bool                `json:"success"`
+	Message     string              `json:"message"`
+	DisjointSet disjointSetResource `json:"disjointSet,omitempty"`
+	Before      interface{}         `json:"before,omitempty"`
+	Data        interface{}         `json:"data,omitempty"`
+	Cost        *ds.OperationCost   `json:"cost,omitempty"`
+	Trace       []ds.TraceStep      `json:"trace,omitempty"`
+}
+
+// 全局并查集存储，后端由 initStorage 根据配置选择
+var disjointSets Storage[disjointSetResource] = newMemoryStorage[disjointSetResource]("disjointset")
+
+// 获取并查集并加锁，调用方负责解锁；并查集不存在或已被删除时返回 false
+func lockDisjointSet(id string) (disjointSetResource, bool) {
+	res, exists := disjointSets.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	sets := res.header()
+	sets.mu.Lock()
+	if sets.removed {
+		sets.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitDisjointSet(c echo.Context, res disjointSetResource) error {
+	sets := res.header()
+	sets.Version++
+	if err := disjointSets.Save(sets.ID, res); err != nil {
+		return err
+	}
+	setETag(c, sets.Version)
+	return nil
+}
+
+// 按请求创建元素类型为 T 的空并查集，参数无效时返回错误；ID 由调用方在校验通过后分配
+func newDisjointSet[T any](req DisjointSetRequest, kind *ds.ElementType[T]) (*DisjointSet[T], error) {
+	core, err := ds.NewDisjointSet(ds.DisjointSetConfig{
+		UnionBy:         req.UnionBy,
+		PathCompression: req.PathCompression,
+	}, kind)
+	if err != nil {
+		return nil, err
+	}
+	return wrapDisjointSet("", req.Name, core), nil
+}
+
+// 为 ds.DisjointSet 附加服务端状态
+func wrapDisjointSet[T any](id, name string, core *ds.DisjointSet[T]) *DisjointSet[T] {
+	sets := &DisjointSet[T]{DisjointSet: core}
+	sets.ID = id
+	sets.Name = name
+	sets.ElementType = core.Kind().Name
+	sets.recorder = &core.Recorder
+	sets.updateVisualizationData()
+	return sets
+}
+
+// 并查集的持久化快照，直接保存父指针、秩和大小，恢复后森林形状不变
+type disjointSetSnapshot[T any] struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"`
+	ds.DisjointSetConfig
+	Elements []T   `json:"elements"`
+	Parent   []int `json:"parent"`
+	Rank     []int `json:"rank"`
+	Size     []int `json:"size"`
+}
+
+// 生成并查集快照
+func snapshotDisjointSet(res disjointSetResource) any {
+	return res.snapshot()
+}
+
+func (sets *DisjointSet[T]) snapshot() any {
+	return disjointSetSnapshot[T]{
+		ID:                sets.ID,
+		Name:              sets.Name,
+		ElementType:       sets.ElementType,
+		Version:           sets.Version,
+		DisjointSetConfig: sets.Config(),
+		Elements:          sets.Elements,
+		Parent:            sets.Parent,
+		Rank:              sets.Rank,
+		Size:              sets.Size,
+	}
+}
+
+// 从快照恢复并查集，按快照中的元素类型分发
+func restoreDisjointSet(data []byte) (disjointSetResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreDisjointSet(data)
+}
+
+// 从快照重建元素类型为 T 的并查集
+func restoreTypedDisjointSet[T any](data []byte, kind *ds.ElementType[T]) (*DisjointSet[T], error) {
+	var snapshot disjointSetSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreDisjointSet(snapshot.DisjointSetConfig, snapshot.Elements, snapshot.Parent, snapshot.Rank, snapshot.Size, kind)
+	if err != nil {
+		return nil, err
+	}
+	sets := wrapDisjointSet(snapshot.ID, snapshot.Name, core)
+	sets.Version = snapshot.Version
+	return sets, nil
+}
+
+func (sets *DisjointSet[T]) header() *disjointSetHeader {
+	return &sets.disjointSetHeader
+}
+
+func (sets *DisjointSet[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return sets.Kind().Decode(raw)
+}
+
+func (sets *DisjointSet[T]) beginOperation() {
+	sets.Begin()
+}
+
+func (sets *DisjointSet[T]) pathCompression() bool {
+	return sets.PathCompression
+}
+
+func (sets *DisjointSet[T]) makeSetAny(value any) error {
+	return sets.MakeSet(valueOf[T](value))
+}
+
+func (sets *DisjointSet[T]) findAny(value any, compress bool) (any, error) {
+	return sets.Find(valueOf[T](value), compress)
+}
+
+func (sets *DisjointSet[T]) unionAny(a, b any) (bool, error) {
+	return sets.Union(valueOf[T](a), valueOf[T](b))
+}
+
+// 当前父指针数组和森林的副本，供响应中的 Before 使用
+func (sets *DisjointSet[T]) view() any {
+	return &DisjointSetView[T]{
+		Parent: slices.Clone(sets.Parent),
+		Rank:   slices.Clone(sets.Rank),
+		Size:   slices.Clone(sets.Size),
+		Forest: sets.forest(),
+		Groups: sets.DisjointSet.Groups(),
+	}
+}
+
+// 按元素下标生成森林节点，只有根节点给出当前合并策略维护的秩或大小
+func (sets *DisjointSet[T]) forest() []*ForestNodeData[T] {
+	nodes := make([]*ForestNodeData[T], len(sets.Elements))
+	for x, element := range sets.Elements {
+		node := &ForestNodeData[T]{
+			ID:    generateNodeID(sets.ID, x),
+			Index: x,
+			Value: element,
+		}
+		if p := sets.Parent[x]; p != x {
+			node.ParentID = generateNodeID(sets.ID, p)
+		} else if sets.UnionBy == ds.UnionBySize {
+			size := sets.Size[x]
+			node.Size = &size
+		} else {
+			rank := sets.Rank[x]
+			node.Rank = &rank
+		}
+		nodes[x] = node
+	}
+	return nodes
+}
+
+// 生成森林和各集合
+func (sets *DisjointSet[T]) updateVisualizationData() {
+	sets.Forest = sets.forest()
+	sets.Groups = sets.DisjointSet.Groups()
+}
+
+// 设置并查集相关路由
+func setupDisjointSetRoutes(g *echo.Group) {
+	setGroup := g.Group("/disjointsets")
+
+	// 创建并查集
+	setGroup.POST("", createDisjointSet)
+
+	// 获取所有并查集
+	setGroup.GET("", getAllDisjointSets)
+
+	// 获取指定并查集
+	setGroup.GET("/:id", getDisjointSet)
+
+	// 删除并查集
+	setGroup.DELETE("/:id", deleteDisjointSet)
+
+	// 加入元素，自成一个集合
+	setGroup.POST("/:id/make_set", makeDisjointSet)
+
+	// 合并两个元素所在的集合
+	setGroup.POST("/:id/union", unionDisjointSet)
+
+	// 查找元素所在集合的根；路径压缩会修改父指针，因此使用 POST
+	setGroup.POST("/:id/find", findDisjointSet)
+}
+
+// 创建并查集
+func createDisjointSet(c echo.Context) error {
+	var req DisjointSetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	req.ElementType = elementType
+
+	res, err := factory.newDisjointSet(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	id, err := disjointSets.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, DisjointSetResponse{
+			Success: false,
+			Message: "并查集ID生成失败",
+		})
+	}
+	sets := res.header()
+	sets.ID = id
+	res.updateVisualizationData()
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	sets.mu.Lock()
+	defer sets.mu.Unlock()
+
+	if err := commitDisjointSet(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, DisjointSetResponse{
+			Success: false,
+			Message: "并查集保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, DisjointSetResponse{
+		Success:     true,
+		Message:     "并查集创建成功",
+		DisjointSet: res,
+	})
+}
+
+// 获取所有并查集
+func getAllDisjointSets(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的并查集
+	setList := make([]json.RawMessage, 0)
+	for _, res := range disjointSets.List() {
+		sets := res.header()
+		sets.mu.Lock()
+		data, err := json.Marshal(res)
+		sets.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		setList = append(setList, data)
+	}
+
+	return c.JSON(http.StatusOK, DisjointSetResponse{
+		Success: true,
+		Message: "获取并查集列表成功",
+		Data:    setList,
+	})
+}
+
+// 获取指定并查集
+func getDisjointSet(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockDisjointSet(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, DisjointSetResponse{
+			Success: false,
+			Message: "并查集不存在",
+		})
+	}
+	sets := res.header()
+	defer sets.mu.Unlock()
+
+	setETag(c, sets.Version)
+	return c.JSON(http.StatusOK, DisjointSetResponse{
+		Success:     true,
+		Message:     "获取并查集成功",
+		DisjointSet: res,
+	})
+}
+
+// 删除并查集
+func deleteDisjointSet(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockDisjointSet(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, DisjointSetResponse{
+			Success: false,
+			Message: "并查集不存在",
+		})
+	}
+	sets := res.header()
+	defer sets.mu.Unlock()
+
+	if _, err := disjointSets.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, DisjointSetResponse{
+			Success: false,
+			Message: "并查集删除失败",
+		})
+	}
+	sets.removed = true
+
+	return c.JSON(http.StatusOK, DisjointSetResponse{
+		Success: true,
+		Message: "并查集删除成功",
+	})
+}
+
+// 获取并查集并检查 If-Match，失败时已写好响应，返回的 handled 为 true
+func lockDisjointSetForUpdate(c echo.Context) (disjointSetResource, bool, error) {
+	res, exists := lockDisjointSet(c.Param("id"))
+	if !exists {
+		return nil, true, c.JSON(http.StatusNotFound, DisjointSetResponse{
+			Success: false,
+			Message: "并查集不存在",
+		})
+	}
+
+	sets := res.header()
+	if !ifMatchSatisfied(c, sets.Version) {
+		defer sets.mu.Unlock()
+		setETag(c, sets.Version)
+		return nil, true, c.JSON(http.StatusPreconditionFailed, DisjointSetResponse{
+			Success:     false,
+			Message:     fmt.Sprintf("并查集已被修改（当前版本%d），请刷新后重试", sets.Version),
+			DisjointSet: res,
+		})
+	}
+	return res, false, nil
+}
+
+// 返回操作前后的状态和本次操作的追踪；父指针有改动时提交新版本
+func respondDisjointSet(c echo.Context, res disjointSetResource, before any, message string, data any) error {
+	sets := res.header()
+	cost := sets.recorder.Cost()
+	res.updateVisualizationData()
+
+	if cost.Writes > 0 {
+		if err := commitDisjointSet(c, res); err != nil {
+			return c.JSON(http.StatusInternalServerError, DisjointSetResponse{
+				Success: false,
+				Message: "并查集保存失败",
+			})
+		}
+	} else {
+		setETag(c, sets.Version)
+	}
+
+	return c.JSON(http.StatusOK, DisjointSetResponse{
+		Success:     true,
+		Message:     message,
+		DisjointSet: res,
+		Before:      before,
+		Data:        data,
+		Cost:        &cost,
+		Trace:       sets.recorder.Trace(),
+	})
+}
+
+// 加入元素
+func makeDisjointSet(c echo.Context) error {
+	res, handled, err := lockDisjointSetForUpdate(c)
+	if handled {
+		return err
+	}
+	defer res.header().mu.Unlock()
+
+	var req DisjointSetValueRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	before := res.view()
+	res.beginOperation()
+	if err := res.makeSetAny(value); err != nil {
+		if errors.Is(err, ds.ErrDuplicate) {
+			return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+				Success: false,
+				Message: fmt.Sprintf("元素%v已存在", value),
+			})
+		}
+		return err
+	}
+
+	return respondDisjointSet(c, res, before, fmt.Sprintf("元素%v已加入，自成一个集合", value), value)
+}
+
+// 合并两个元素所在的集合
+func unionDisjointSet(c echo.Context) error {
+	res, handled, err := lockDisjointSetForUpdate(c)
+	if handled {
+		return err
+	}
+	defer res.header().mu.Unlock()
+
+	var req DisjointSetUnionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+	a, err := res.decodeValue(req.A)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	b, err := res.decodeValue(req.B)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	before := res.view()
+	res.beginOperation()
+	merged, err := res.unionAny(a, b)
+	if errors.Is(err, ds.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, DisjointSetResponse{
+			Success: false,
+			Message: fmt.Sprintf("元素%v或%v不存在，请先make_set", a, b),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%v和%v所在的集合已合并", a, b)
+	if !merged {
+		message = fmt.Sprintf("%v和%v已在同一集合", a, b)
+	}
+	return respondDisjointSet(c, res, before, message, merged)
+}
+
+// 查找元素所在集合的根
+func findDisjointSet(c echo.Context) error {
+	res, handled, err := lockDisjointSetForUpdate(c)
+	if handled {
+		return err
+	}
+	defer res.header().mu.Unlock()
+
+	var req DisjointSetFindRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, DisjointSetResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	compress := res.pathCompression()
+	if req.Compress != nil {
+		compress = *req.Compress
+	}
+
+	before := res.view()
+	res.beginOperation()
+	root, err := res.findAny(value, compress)
+	if errors.Is(err, ds.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, DisjointSetResponse{
+			Success: false,
+			Message: fmt.Sprintf("元素%v不存在，请先make_set", value),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return respondDisjointSet(c, res, before, fmt.Sprintf("%v所在集合的根为%v", value, root), root)
+}
@@ -0,0 +1,340 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// 未指定容量时的默认容量
+const DefaultCapacity = 10
+
+// ArrayConfig 创建动态数组的参数，零值字段使用默认值
+type ArrayConfig struct {
+	Capacity        int     `json:"capacity"`
+	GrowthStrategy  string  `json:"growthStrategy"`
+	GrowthIncrement int     `json:"growthIncrement,omitempty"`
+	ShrinkFactor    float64 `json:"shrinkFactor"` // 装载因子低于该值时缩容，0 表示不缩容
+}
+
+// ArrayState 动态数组中与元素类型无关的容量状态
+type ArrayState struct {
+	Capacity        int     `json:"capacity"`
+	Size            int     `json:"size"`
+	GrowthStrategy  string  `json:"growthStrategy"`
+	GrowthIncrement int     `json:"growthIncrement,omitempty"`
+	ShrinkFactor    float64 `json:"shrinkFactor"` // 装载因子低于该值时缩容，0 表示不缩容
+
+	Recorder `json:"-"`
+}
+
+// Array 动态数组，T 为元素类型；容量用尽时按扩容策略重新分配并逐个复制元素
+type Array[T any] struct {
+	ArrayState
+	Elements []T `json:"elements"`
+
+	kind *ElementType[T]
+}
+
+// ResizeEvent 一次扩容或缩容的记录
+type ResizeEvent struct {
+	Kind           string `json:"kind"` // "grow" 或 "shrink"
+	OldCapacity    int    `json:"oldCapacity"`
+	NewCapacity    int    `json:"newCapacity"`
+	ElementsCopied int    `json:"elementsCopied"`
+}
+
+// NewArray 按配置创建空数组，配置无效时返回错误
+func NewArray[T any](config ArrayConfig, kind *ElementType[T]) (*Array[T], error) {
+	if config.Capacity <= 0 {
+		config.Capacity = DefaultCapacity
+	}
+	if config.GrowthStrategy == "" {
+		config.GrowthStrategy = DefaultGrowthStrategy
+	}
+	if _, err := NewGrowthStrategy(config.GrowthStrategy, config.GrowthIncrement); err != nil {
+		return nil, err
+	}
+
+	// 缩容阈值需小于0.5，否则扩容后立即满足缩容条件，会在边界处反复扩缩
+	if config.ShrinkFactor < 0 || config.ShrinkFactor >= 0.5 {
+		return nil, errors.New("缩容装载因子必须在0到0.5之间（0表示不缩容）")
+	}
+
+	if config.GrowthStrategy == GrowthFixedIncrement && config.GrowthIncrement <= 0 {
+		config.GrowthIncrement = DefaultGrowthIncrement
+	}
+
+	array := &Array[T]{
+		Elements: make([]T, 0, config.Capacity),
+		kind:     kind,
+	}
+	array.Capacity = config.Capacity
+	array.GrowthStrategy = config.GrowthStrategy
+	array.GrowthIncrement = config.GrowthIncrement
+	array.ShrinkFactor = config.ShrinkFactor
+	return array, nil
+}
+
+// Rebuild 从 JSON 解码后恢复元素类型，并按容量重新分配底层存储
+func (array *Array[T]) Rebuild(kind *ElementType[T]) {
+	array.kind = kind
+	array.Size = len(array.Elements)
+	array.Capacity = max(array.Capacity, array.Size)
+	elements := make([]T, array.Size, array.Capacity)
+	copy(elements, array.Elements)
+	array.Elements = elements
+}
+
+// Kind 数组的元素类型
+func (array *Array[T]) Kind() *ElementType[T] {
+	return array.kind
+}
+
+// Clone 复制出只含元素和容量的数组，不带计数和追踪，用于在副本上试运行
+func (array *Array[T]) Clone() *Array[T] {
+	clone := &Array[T]{
+		Elements: slices.Clone(array.Elements),
+		kind:     array.kind,
+	}
+	clone.Capacity = array.Capacity
+	clone.Size = array.Size
+	clone.GrowthStrategy = array.GrowthStrategy
+	clone.GrowthIncrement = array.GrowthIncrement
+	clone.ShrinkFactor = array.ShrinkFactor
+	return clone
+}
+
+// Config 按当前容量和扩缩容参数生成的配置，用于重建同样设置的数组
+func (array *Array[T]) Config() ArrayConfig {
+	return ArrayConfig{
+		Capacity:        array.Capacity,
+		GrowthStrategy:  array.GrowthStrategy,
+		GrowthIncrement: array.GrowthIncrement,
+		ShrinkFactor:    array.ShrinkFactor,
+	}
+}
+
+// Values 返回当前元素的副本
+func (array *Array[T]) Values() []T {
+	return slices.Clone(array.Elements[:array.Size])
+}
+
+// Numbers 以浮点数返回当前元素，供线段树等按数值建立的结构使用；非数值类型返回错误
+func (array *Array[T]) Numbers() ([]float64, error) {
+	if array.kind.Number == nil {
+		return nil, fmt.Errorf("只有%s或%s类型的数组可以转换为数值", ElementInt, ElementFloat)
+	}
+	numbers := make([]float64, array.Size)
+	for i, value := range array.Elements[:array.Size] {
+		numbers[i] = array.kind.Number(value)
+	}
+	return numbers, nil
+}
+
+// 确保数组至少能容纳 minCap 个元素，容量不足时按扩容策略重新分配
+func (array *Array[T]) ensureCapacity(minCap int) (*ResizeEvent, error) {
+	if minCap <= array.Capacity {
+		return nil, nil
+	}
+
+	strategy, err := NewGrowthStrategy(array.GrowthStrategy, array.GrowthIncrement)
+	if err != nil {
+		return nil, err
+	}
+
+	newCap := strategy.NewCapacity(array.Capacity, minCap)
+	if newCap < minCap {
+		return nil, ErrFull
+	}
+
+	return array.reallocate(newCap, "grow"), nil
+}
+
+// 删除元素后检查装载因子，低于缩容阈值时将容量减半
+func (array *Array[T]) shrinkIfNeeded() *ResizeEvent {
+	if array.ShrinkFactor <= 0 || array.Capacity <= 1 {
+		return nil
+	}
+
+	if float64(array.Size) > float64(array.Capacity)*array.ShrinkFactor {
+		return nil
+	}
+
+	return array.reallocate(max(array.Capacity/2, array.Size, 1), "shrink")
+}
+
+// 分配新的底层存储并逐个复制现有元素
+func (array *Array[T]) reallocate(newCap int, kind string) *ResizeEvent {
+	event := &ResizeEvent{
+		Kind:           kind,
+		OldCapacity:    array.Capacity,
+		NewCapacity:    newCap,
+		ElementsCopied: array.Size,
+	}
+
+	array.record(TraceStep{
+		Action: StepAllocate,
+		Detail: fmt.Sprintf("分配容量为%d的新存储（原容量%d）", newCap, array.Capacity),
+	})
+
+	elements := make([]T, array.Size, newCap)
+	for i := 0; i < array.Size; i++ {
+		elements[i] = array.Elements[i]
+		array.cost.Copies++
+		array.record(TraceStep{
+			Action: StepCopy,
+			From:   intRef(i),
+			To:     intRef(i),
+			Value:  elements[i],
+			Detail: fmt.Sprintf("复制元素%v到新存储的索引%d", elements[i], i),
+		})
+	}
+	array.Elements = elements
+	array.Capacity = newCap
+	array.cost.Reallocations++
+
+	return event
+}
+
+// 读取索引处的元素
+func (array *Array[T]) read(index int) T {
+	value := array.Elements[index]
+	array.record(TraceStep{
+		Action: StepRead,
+		Index:  intRef(index),
+		Value:  value,
+		Detail: fmt.Sprintf("读取索引%d处的元素%v", index, value),
+	})
+	return value
+}
+
+// 按元素类型的比较规则判断索引处的元素是否等于目标值
+func (array *Array[T]) compare(index int, value T) bool {
+	element := array.Elements[index]
+	array.cost.Comparisons++
+	array.record(TraceStep{
+		Action: StepCompare,
+		Index:  intRef(index),
+		Value:  element,
+		Detail: fmt.Sprintf("比较索引%d处的元素%v与%v", index, element, value),
+	})
+	return array.kind.Equal(element, value)
+}
+
+// 向索引处写入元素
+func (array *Array[T]) write(index int, value T) {
+	array.Elements[index] = value
+	array.cost.Writes++
+	array.record(TraceStep{
+		Action: StepWrite,
+		Index:  intRef(index),
+		Value:  value,
+		Detail: fmt.Sprintf("将%v写入索引%d", value, index),
+	})
+}
+
+// 将元素从 from 移动到 to
+func (array *Array[T]) shift(from, to int) {
+	value := array.Elements[from]
+	array.Elements[to] = value
+	array.cost.Writes++
+	array.record(TraceStep{
+		Action: StepShift,
+		From:   intRef(from),
+		To:     intRef(to),
+		Value:  value,
+		Detail: fmt.Sprintf("将元素%v从索引%d移动到索引%d", value, from, to),
+	})
+}
+
+// Get 读取索引处的元素
+func (array *Array[T]) Get(index int) (T, error) {
+	if index < 0 || index >= array.Size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	return array.read(index), nil
+}
+
+// Insert 在指定位置插入元素，其后的元素依次后移；index 可以等于 Size，即追加
+func (array *Array[T]) Insert(index int, value T) (*ResizeEvent, error) {
+	if index < 0 || index > array.Size {
+		return nil, ErrIndexOutOfRange
+	}
+
+	resize, err := array.ensureCapacity(array.Size + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	array.Elements = array.Elements[:array.Size+1]
+	for i := array.Size; i > index; i-- {
+		array.shift(i-1, i)
+	}
+	array.write(index, value)
+	array.Size++
+
+	return resize, nil
+}
+
+// Append 在末尾追加元素
+func (array *Array[T]) Append(value T) (*ResizeEvent, error) {
+	return array.Insert(array.Size, value)
+}
+
+// RemoveAt 删除指定位置的元素，其后的元素依次前移，返回被删除的元素
+func (array *Array[T]) RemoveAt(index int) (T, *ResizeEvent, error) {
+	if index < 0 || index >= array.Size {
+		var zero T
+		return zero, nil, ErrIndexOutOfRange
+	}
+
+	deleted := array.read(index)
+	for i := index; i < array.Size-1; i++ {
+		array.shift(i+1, i)
+	}
+	array.Elements = array.Elements[:array.Size-1]
+	array.Size--
+
+	return deleted, array.shrinkIfNeeded(), nil
+}
+
+// Set 修改指定位置的元素，返回旧值
+func (array *Array[T]) Set(index int, value T) (T, error) {
+	if index < 0 || index >= array.Size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+
+	oldValue := array.read(index)
+	array.write(index, value)
+	return oldValue, nil
+}
+
+// IndexOf 线性查找第一个等于 value 的元素，未找到返回 -1
+func (array *Array[T]) IndexOf(value T) int {
+	for i := 0; i < array.Size; i++ {
+		if array.compare(i, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Clear 删除全部元素，保留当前容量
+func (array *Array[T]) Clear() {
+	array.Elements = make([]T, 0, array.Capacity)
+	array.Size = 0
+}
+
+// Replace 依次写入全部元素，元素个数必须与 Size 相同
+func (array *Array[T]) Replace(values []T) error {
+	if len(values) != array.Size {
+		return ErrIndexOutOfRange
+	}
+	for i, value := range values {
+		array.write(i, value)
+	}
+	return nil
+}
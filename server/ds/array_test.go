@@ -0,0 +1,124 @@
+package ds
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestArrayGrowShrink(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ArrayConfig
+		appends int
+		full    bool  // 最后一次追加因容量用尽失败
+		grows   []int // 追加过程中每次扩容后的容量
+		shrinks []int // 从尾部逐个删除时每次缩容后的容量
+	}{
+		{"doubling", ArrayConfig{Capacity: 2, GrowthStrategy: GrowthDoubling}, 5, false, []int{4, 8}, []int{4, 2, 1}},
+		{"one_and_half", ArrayConfig{Capacity: 2, GrowthStrategy: GrowthOneAndHalf}, 5, false, []int{3, 4, 6}, []int{3, 1}},
+		{"fixed_increment", ArrayConfig{Capacity: 2, GrowthStrategy: GrowthFixedIncrement, GrowthIncrement: 3}, 5, false, []int{5}, []int{2, 1}},
+		{"go_append", ArrayConfig{Capacity: 2, GrowthStrategy: GrowthGoAppend}, 5, false, []int{4, 8}, []int{4, 2, 1}},
+		{"none", ArrayConfig{Capacity: 5, GrowthStrategy: GrowthNone}, 6, true, nil, []int{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ShrinkFactor = 0.25
+			array, err := NewArray(tt.config, Ints)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var grows []int
+			for i := range tt.appends {
+				event, err := array.Append(i)
+				if tt.full && i == tt.appends-1 {
+					if !errors.Is(err, ErrFull) {
+						t.Fatalf("容量用尽时追加返回 %v，期望 ErrFull", err)
+					}
+					break
+				}
+				if err != nil {
+					t.Fatalf("追加第%d个元素失败: %v", i, err)
+				}
+				if event != nil {
+					if event.Kind != "grow" || event.ElementsCopied != i {
+						t.Fatalf("扩容记录 %+v 不正确", *event)
+					}
+					grows = append(grows, event.NewCapacity)
+				}
+			}
+			if !slices.Equal(grows, tt.grows) {
+				t.Fatalf("扩容后的容量依次为 %v，期望 %v", grows, tt.grows)
+			}
+			if array.Size > array.Capacity {
+				t.Fatalf("元素个数%d超过容量%d", array.Size, array.Capacity)
+			}
+
+			var shrinks []int
+			for array.Size > 0 {
+				want := array.Size - 1
+				value, event, err := array.RemoveAt(array.Size - 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if value != want {
+					t.Fatalf("删除的元素为%d，期望%d", value, want)
+				}
+				if event != nil {
+					if event.Kind != "shrink" || event.NewCapacity < array.Size {
+						t.Fatalf("缩容记录 %+v 不正确", *event)
+					}
+					shrinks = append(shrinks, event.NewCapacity)
+				}
+			}
+			if !slices.Equal(shrinks, tt.shrinks) {
+				t.Fatalf("缩容后的容量依次为 %v，期望 %v", shrinks, tt.shrinks)
+			}
+		})
+	}
+}
+
+func TestArrayInsertRemoveKeepsOrder(t *testing.T) {
+	array, err := NewArray(ArrayConfig{Capacity: 1}, Ints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []int{1, 3} {
+		if _, err := array.Append(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := array.Insert(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := array.Insert(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := array.Values(); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("插入后为 %v，期望 [0 1 2 3]", got)
+	}
+
+	if _, _, err := array.RemoveAt(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := array.Values(); !slices.Equal(got, []int{0, 2, 3}) {
+		t.Fatalf("删除后为 %v，期望 [0 2 3]", got)
+	}
+	if _, err := array.Insert(5, 9); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("越界插入返回 %v，期望 ErrIndexOutOfRange", err)
+	}
+}
+
+func TestNewArrayRejectsInvalidConfig(t *testing.T) {
+	for _, config := range []ArrayConfig{
+		{GrowthStrategy: "triple"},
+		{ShrinkFactor: 0.5},
+		{ShrinkFactor: -0.1},
+	} {
+		if _, err := NewArray(config, Ints); err == nil {
+			t.Fatalf("配置 %+v 应被拒绝", config)
+		}
+	}
+}
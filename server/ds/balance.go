@@ -0,0 +1,252 @@
+package ds
+
+import "fmt"
+
+// 颜色的中文名称，用于追踪说明
+func colorName(color string) string {
+	if color == ColorRed {
+		return "红色"
+	}
+	return "黑色"
+}
+
+// 修改红黑树节点的颜色，颜色不变时不记录
+func (tree *Tree[T]) recolor(node *TreeNode[T], color string) {
+	if node.Color == color {
+		return
+	}
+	node.Color = color
+	tree.record(TraceStep{
+		Action: StepRecolor,
+		Value:  color,
+		Node:   tree.label(node),
+		Detail: fmt.Sprintf("%s染为%s", tree.label(node), colorName(color)),
+	})
+}
+
+// 按子节点重新计算 AVL 节点的高度，高度变化时记录新的高度和平衡因子
+func (tree *Tree[T]) updateHeight(node *TreeNode[T]) {
+	newHeight := 1 + max(height(node.Left), height(node.Right))
+	if newHeight == node.Height {
+		return
+	}
+	node.Height = newHeight
+	tree.record(TraceStep{
+		Action: StepHeight,
+		Value:  newHeight,
+		Node:   tree.label(node),
+		Detail: fmt.Sprintf("%s的高度更新为%d，平衡因子为%d", tree.label(node), newHeight, node.BalanceFactor()),
+	})
+}
+
+// 以 node 为轴左旋：右子节点上升为子树的根，node 成为它的左子节点；返回新的子树根
+func (tree *Tree[T]) rotateLeft(node *TreeNode[T]) *TreeNode[T] {
+	pivot := node.Right
+	tree.record(TraceStep{
+		Action: StepRotateLeft,
+		Node:   tree.label(node),
+		Target: tree.label(pivot),
+		Detail: fmt.Sprintf("以%s为轴左旋，%s上升", tree.label(node), tree.label(pivot)),
+	})
+	tree.setRight(node, pivot.Left)
+	tree.transplant(node, pivot)
+	tree.setLeft(pivot, node)
+	if tree.Type == TreeAVL {
+		tree.updateHeight(node)
+		tree.updateHeight(pivot)
+	}
+	return pivot
+}
+
+// 以 node 为轴右旋：左子节点上升为子树的根，node 成为它的右子节点；返回新的子树根
+func (tree *Tree[T]) rotateRight(node *TreeNode[T]) *TreeNode[T] {
+	pivot := node.Left
+	tree.record(TraceStep{
+		Action: StepRotateRight,
+		Node:   tree.label(node),
+		Target: tree.label(pivot),
+		Detail: fmt.Sprintf("以%s为轴右旋，%s上升", tree.label(node), tree.label(pivot)),
+	})
+	tree.setLeft(node, pivot.Right)
+	tree.transplant(node, pivot)
+	tree.setRight(pivot, node)
+	if tree.Type == TreeAVL {
+		tree.updateHeight(node)
+		tree.updateHeight(pivot)
+	}
+	return pivot
+}
+
+// 先左旋 node 的左子节点，再右旋 node（LR 型）
+func (tree *Tree[T]) rotateLeftRight(node *TreeNode[T]) *TreeNode[T] {
+	tree.record(TraceStep{
+		Action: StepRotateLeftRight,
+		Node:   tree.label(node),
+		Target: tree.label(node.Left.Right),
+		Detail: fmt.Sprintf("对%s做左右双旋：先左旋%s，再右旋%s", tree.label(node), tree.label(node.Left), tree.label(node)),
+	})
+	tree.rotateLeft(node.Left)
+	return tree.rotateRight(node)
+}
+
+// 先右旋 node 的右子节点，再左旋 node（RL 型）
+func (tree *Tree[T]) rotateRightLeft(node *TreeNode[T]) *TreeNode[T] {
+	tree.record(TraceStep{
+		Action: StepRotateRightLeft,
+		Node:   tree.label(node),
+		Target: tree.label(node.Right.Left),
+		Detail: fmt.Sprintf("对%s做右左双旋：先右旋%s，再左旋%s", tree.label(node), tree.label(node.Right), tree.label(node)),
+	})
+	tree.rotateRight(node.Right)
+	return tree.rotateLeft(node)
+}
+
+// AVL 树自 node 向上逐个更新高度，遇到平衡因子超出 [-1, 1] 的节点按 LL、LR、RR、RL 四种情况旋转
+func (tree *Tree[T]) retrace(node *TreeNode[T]) {
+	for node != nil {
+		tree.updateHeight(node)
+		balance := node.BalanceFactor()
+		switch {
+		case balance > 1 && node.Left.BalanceFactor() >= 0:
+			tree.explain(node, fmt.Sprintf("%s的平衡因子为%d，LL型失衡", tree.label(node), balance))
+			node = tree.rotateRight(node)
+		case balance > 1:
+			tree.explain(node, fmt.Sprintf("%s的平衡因子为%d，LR型失衡", tree.label(node), balance))
+			node = tree.rotateLeftRight(node)
+		case balance < -1 && node.Right.BalanceFactor() <= 0:
+			tree.explain(node, fmt.Sprintf("%s的平衡因子为%d，RR型失衡", tree.label(node), balance))
+			node = tree.rotateLeft(node)
+		case balance < -1:
+			tree.explain(node, fmt.Sprintf("%s的平衡因子为%d，RL型失衡", tree.label(node), balance))
+			node = tree.rotateRightLeft(node)
+		}
+		node = node.Parent
+	}
+}
+
+// 红黑树插入红节点后，消除红节点的父节点也为红色的情况，最后把根染黑
+func (tree *Tree[T]) insertFixup(node *TreeNode[T]) {
+	for isRed(node.Parent) {
+		parent := node.Parent
+		grandparent := parent.Parent
+		if parent == grandparent.Left {
+			uncle := grandparent.Right
+			if isRed(uncle) {
+				tree.explain(node, fmt.Sprintf("插入情况一：叔节点%s为红色，父、叔染黑，祖父染红后从祖父继续", tree.label(uncle)))
+				tree.recolor(parent, ColorBlack)
+				tree.recolor(uncle, ColorBlack)
+				tree.recolor(grandparent, ColorRed)
+				node = grandparent
+				continue
+			}
+			if node == parent.Right {
+				tree.explain(node, fmt.Sprintf("插入情况二：叔节点为黑色且%s是右子节点，左右双旋后父节点换为%s", tree.label(node), tree.label(node)))
+				tree.recolor(node, ColorBlack)
+				tree.recolor(grandparent, ColorRed)
+				tree.rotateLeftRight(grandparent)
+				return
+			}
+			tree.explain(node, fmt.Sprintf("插入情况三：叔节点为黑色且%s是左子节点，父节点染黑、祖父染红后右旋祖父", tree.label(node)))
+			tree.recolor(parent, ColorBlack)
+			tree.recolor(grandparent, ColorRed)
+			tree.rotateRight(grandparent)
+			return
+		}
+
+		uncle := grandparent.Left
+		if isRed(uncle) {
+			tree.explain(node, fmt.Sprintf("插入情况一：叔节点%s为红色，父、叔染黑，祖父染红后从祖父继续", tree.label(uncle)))
+			tree.recolor(parent, ColorBlack)
+			tree.recolor(uncle, ColorBlack)
+			tree.recolor(grandparent, ColorRed)
+			node = grandparent
+			continue
+		}
+		if node == parent.Left {
+			tree.explain(node, fmt.Sprintf("插入情况二：叔节点为黑色且%s是左子节点，右左双旋后父节点换为%s", tree.label(node), tree.label(node)))
+			tree.recolor(node, ColorBlack)
+			tree.recolor(grandparent, ColorRed)
+			tree.rotateRightLeft(grandparent)
+			return
+		}
+		tree.explain(node, fmt.Sprintf("插入情况三：叔节点为黑色且%s是右子节点，父节点染黑、祖父染红后左旋祖父", tree.label(node)))
+		tree.recolor(parent, ColorBlack)
+		tree.recolor(grandparent, ColorRed)
+		tree.rotateLeft(grandparent)
+		return
+	}
+
+	if isRed(tree.Root) {
+		tree.explain(tree.Root, "根节点必须为黑色")
+		tree.recolor(tree.Root, ColorBlack)
+	}
+}
+
+// 红黑树删除黑节点后，经过 node 的路径少了一个黑节点（node 可能为空，由 parent 确定位置），
+// 通过兄弟节点借黑或把缺少的黑色上移，直到 node 为红色或到达根
+func (tree *Tree[T]) deleteFixup(node, parent *TreeNode[T]) {
+	for node != tree.Root && !isRed(node) {
+		if node == parent.Left {
+			sibling := parent.Right
+			if isRed(sibling) {
+				tree.explain(sibling, fmt.Sprintf("删除情况一：兄弟节点%s为红色，兄弟染黑、父节点染红后左旋父节点", tree.label(sibling)))
+				tree.recolor(sibling, ColorBlack)
+				tree.recolor(parent, ColorRed)
+				tree.rotateLeft(parent)
+				sibling = parent.Right
+			}
+			if !isRed(sibling.Left) && !isRed(sibling.Right) {
+				tree.explain(sibling, fmt.Sprintf("删除情况二：兄弟节点%s的两个子节点都为黑色，兄弟染红后缺少的黑色上移到父节点", tree.label(sibling)))
+				tree.recolor(sibling, ColorRed)
+				node, parent = parent, parent.Parent
+				continue
+			}
+			if !isRed(sibling.Right) {
+				tree.explain(sibling, fmt.Sprintf("删除情况三：兄弟节点%s的右子节点为黑色，左子节点染黑、兄弟染红后右旋兄弟", tree.label(sibling)))
+				tree.recolor(sibling.Left, ColorBlack)
+				tree.recolor(sibling, ColorRed)
+				tree.rotateRight(sibling)
+				sibling = parent.Right
+			}
+			tree.explain(sibling, fmt.Sprintf("删除情况四：兄弟节点%s的右子节点为红色，兄弟接管父节点颜色，父节点和兄弟的右子节点染黑后左旋父节点", tree.label(sibling)))
+			tree.recolor(sibling, parent.Color)
+			tree.recolor(parent, ColorBlack)
+			tree.recolor(sibling.Right, ColorBlack)
+			tree.rotateLeft(parent)
+			return
+		}
+
+		sibling := parent.Left
+		if isRed(sibling) {
+			tree.explain(sibling, fmt.Sprintf("删除情况一：兄弟节点%s为红色，兄弟染黑、父节点染红后右旋父节点", tree.label(sibling)))
+			tree.recolor(sibling, ColorBlack)
+			tree.recolor(parent, ColorRed)
+			tree.rotateRight(parent)
+			sibling = parent.Left
+		}
+		if !isRed(sibling.Left) && !isRed(sibling.Right) {
+			tree.explain(sibling, fmt.Sprintf("删除情况二：兄弟节点%s的两个子节点都为黑色，兄弟染红后缺少的黑色上移到父节点", tree.label(sibling)))
+			tree.recolor(sibling, ColorRed)
+			node, parent = parent, parent.Parent
+			continue
+		}
+		if !isRed(sibling.Left) {
+			tree.explain(sibling, fmt.Sprintf("删除情况三：兄弟节点%s的左子节点为黑色，右子节点染黑、兄弟染红后左旋兄弟", tree.label(sibling)))
+			tree.recolor(sibling.Right, ColorBlack)
+			tree.recolor(sibling, ColorRed)
+			tree.rotateLeft(sibling)
+			sibling = parent.Left
+		}
+		tree.explain(sibling, fmt.Sprintf("删除情况四：兄弟节点%s的左子节点为红色，兄弟接管父节点颜色，父节点和兄弟的左子节点染黑后右旋父节点", tree.label(sibling)))
+		tree.recolor(sibling, parent.Color)
+		tree.recolor(parent, ColorBlack)
+		tree.recolor(sibling.Left, ColorBlack)
+		tree.rotateRight(parent)
+		return
+	}
+
+	if isRed(node) {
+		tree.explain(node, fmt.Sprintf("%s为红色，染黑即可补上缺少的黑节点", tree.label(node)))
+		tree.recolor(node, ColorBlack)
+	}
+}
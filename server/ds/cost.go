@@ -0,0 +1,24 @@
+package ds
+
+// OperationCost 基本操作计数，单位代价为一次元素复制、比较或写入
+type OperationCost struct {
+	Copies        int `json:"copies"`        // 重新分配时复制的元素数
+	Reallocations int `json:"reallocations"` // 重新分配次数
+	Comparisons   int `json:"comparisons"`   // 元素比较次数
+	Writes        int `json:"writes"`        // 元素写入次数（含移位）
+	Swaps         int `json:"swaps"`         // 元素交换次数，每次交换另计两次写入
+}
+
+// Total 本次操作的代价总和（重新分配本身按常数处理，代价计入复制的元素）
+func (c OperationCost) Total() int {
+	return c.Copies + c.Comparisons + c.Writes
+}
+
+// Add 累加另一组计数
+func (c *OperationCost) Add(other OperationCost) {
+	c.Copies += other.Copies
+	c.Reallocations += other.Reallocations
+	c.Comparisons += other.Comparisons
+	c.Writes += other.Writes
+	c.Swaps += other.Swaps
+}
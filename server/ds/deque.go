@@ -0,0 +1,171 @@
+package ds
+
+import "errors"
+
+// 环形缓冲区底层结构
+const BackingRing = "ring"
+
+// Deque 双端队列，底层为环形缓冲区或双向链表；队列只使用其中的 PushBack、PopFront、Front、Back
+type Deque[T any] struct {
+	Backing string `json:"backing"`
+
+	ring *RingBuffer[T]
+	list *LinkedList[T]
+}
+
+// NewDeque 创建指定底层结构的空双端队列，config 只在底层为环形缓冲区时使用
+func NewDeque[T any](backing string, config ArrayConfig, kind *ElementType[T]) (*Deque[T], error) {
+	if backing == "" {
+		backing = BackingRing
+	}
+
+	deque := &Deque[T]{Backing: backing}
+	switch backing {
+	case BackingRing:
+		ring, err := NewRingBuffer(config, kind)
+		if err != nil {
+			return nil, err
+		}
+		deque.ring = ring
+	case BackingList:
+		list, err := NewLinkedList(ListDouble, kind)
+		if err != nil {
+			return nil, err
+		}
+		deque.list = list
+	default:
+		return nil, errors.New("底层结构必须是ring或list")
+	}
+	return deque, nil
+}
+
+// RestoreDeque 按从队首到队尾的值序列重建双端队列，环形缓冲区从 head 槽位开始放置
+func RestoreDeque[T any](backing string, config ArrayConfig, head int, values []T, kind *ElementType[T]) (*Deque[T], error) {
+	switch backing {
+	case BackingRing:
+		ring, err := RestoreRingBuffer(config, head, values, kind)
+		if err != nil {
+			return nil, err
+		}
+		return &Deque[T]{Backing: backing, ring: ring}, nil
+	case BackingList:
+		return &Deque[T]{Backing: backing, list: RestoreLinkedList(ListDouble, values, kind)}, nil
+	default:
+		return nil, errors.New("底层结构必须是ring或list")
+	}
+}
+
+// Ring 底层环形缓冲区，底层为链表时返回 nil
+func (deque *Deque[T]) Ring() *RingBuffer[T] {
+	return deque.ring
+}
+
+// List 底层双向链表，底层为环形缓冲区时返回 nil
+func (deque *Deque[T]) List() *LinkedList[T] {
+	return deque.list
+}
+
+// Kind 双端队列的元素类型
+func (deque *Deque[T]) Kind() *ElementType[T] {
+	if deque.ring != nil {
+		return deque.ring.Kind()
+	}
+	return deque.list.Kind()
+}
+
+// Recorder 底层结构的计数和追踪
+func (deque *Deque[T]) Recorder() *Recorder {
+	if deque.ring != nil {
+		return &deque.ring.Recorder
+	}
+	return &deque.list.Recorder
+}
+
+// Begin 开始一次新操作，清空底层结构的计数和追踪
+func (deque *Deque[T]) Begin() {
+	if deque.ring != nil {
+		deque.ring.Begin()
+	} else {
+		deque.list.Begin()
+	}
+}
+
+// Len 元素个数
+func (deque *Deque[T]) Len() int {
+	if deque.ring != nil {
+		return deque.ring.Size
+	}
+	return deque.list.Size
+}
+
+// PushFront 在队首加入元素
+func (deque *Deque[T]) PushFront(value T) (*ResizeEvent, error) {
+	if deque.ring != nil {
+		return deque.ring.PushFront(value)
+	}
+	return nil, deque.list.Prepend(value)
+}
+
+// PushBack 在队尾加入元素
+func (deque *Deque[T]) PushBack(value T) (*ResizeEvent, error) {
+	if deque.ring != nil {
+		return deque.ring.PushBack(value)
+	}
+	return nil, deque.list.Append(value)
+}
+
+// PopFront 取出队首元素，为空时返回 ErrEmpty
+func (deque *Deque[T]) PopFront() (T, error) {
+	if deque.ring != nil {
+		return deque.ring.PopFront()
+	}
+	if deque.list.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return deque.list.RemoveAt(0)
+}
+
+// PopBack 取出队尾元素，为空时返回 ErrEmpty
+func (deque *Deque[T]) PopBack() (T, error) {
+	if deque.ring != nil {
+		return deque.ring.PopBack()
+	}
+	if deque.list.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return deque.list.RemoveAt(deque.list.Size - 1)
+}
+
+// Front 读取队首元素，为空时返回 ErrEmpty
+func (deque *Deque[T]) Front() (T, error) {
+	if deque.ring != nil {
+		return deque.ring.Front()
+	}
+	if deque.list.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return deque.list.Get(0)
+}
+
+// Back 读取队尾元素，为空时返回 ErrEmpty
+func (deque *Deque[T]) Back() (T, error) {
+	if deque.ring != nil {
+		return deque.ring.Back()
+	}
+	if deque.list.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return deque.list.Get(deque.list.Size - 1)
+}
+
+// Values 从队首到队尾返回所有元素
+func (deque *Deque[T]) Values() []T {
+	if deque.ring != nil {
+		return deque.ring.Values()
+	}
+	return deque.list.Values()
+}
@@ -0,0 +1,291 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 合并策略
+const (
+	UnionByRank = "rank" // 秩（树高的上界）小的根挂到秩大的根下，秩相同时合并后秩加1
+	UnionBySize = "size" // 元素少的树的根挂到元素多的树的根下
+)
+
+// DisjointSetConfig 创建并查集的参数
+type DisjointSetConfig struct {
+	UnionBy         string `json:"unionBy"`         // 为空时按秩合并
+	PathCompression bool   `json:"pathCompression"` // 合并时内部查找是否压缩路径，也是单独查找的默认值
+}
+
+// DisjointSetState 并查集中与元素类型无关的状态
+type DisjointSetState struct {
+	UnionBy         string `json:"unionBy"`
+	PathCompression bool   `json:"pathCompression"`
+	Sets            int    `json:"sets"` // 集合（树）的个数
+
+	Recorder `json:"-"`
+}
+
+// DisjointSet 并查集，T 为元素类型；元素按加入顺序编号，Parent[i] == i 的元素是一棵树的根
+type DisjointSet[T any] struct {
+	DisjointSetState
+	Elements []T   `json:"elements"`
+	Parent   []int `json:"parent"`
+	Rank     []int `json:"rank"` // 按秩合并时维护，只有根的秩有意义
+	Size     []int `json:"size"` // 按大小合并时维护，只有根的大小有意义
+
+	kind *ElementType[T]
+}
+
+// NewDisjointSet 按配置创建空的并查集
+func NewDisjointSet[T any](config DisjointSetConfig, kind *ElementType[T]) (*DisjointSet[T], error) {
+	if config.UnionBy == "" {
+		config.UnionBy = UnionByRank
+	}
+	if config.UnionBy != UnionByRank && config.UnionBy != UnionBySize {
+		return nil, errors.New("合并策略必须是rank或size")
+	}
+
+	sets := &DisjointSet[T]{
+		Elements: make([]T, 0),
+		Parent:   make([]int, 0),
+		Rank:     make([]int, 0),
+		Size:     make([]int, 0),
+		kind:     kind,
+	}
+	sets.UnionBy = config.UnionBy
+	sets.PathCompression = config.PathCompression
+	sets.Begin()
+	return sets, nil
+}
+
+// RestoreDisjointSet 按保存的元素、父指针、秩和大小直接重建并查集，不记录追踪
+func RestoreDisjointSet[T any](config DisjointSetConfig, elements []T, parent, rank, size []int, kind *ElementType[T]) (*DisjointSet[T], error) {
+	sets, err := NewDisjointSet(config, kind)
+	if err != nil {
+		return nil, err
+	}
+	n := len(elements)
+	if len(parent) != n || len(rank) != n || len(size) != n {
+		return nil, errors.New("父指针、秩或大小数组与元素数不一致")
+	}
+	for i, p := range parent {
+		if p < 0 || p >= n {
+			return nil, fmt.Errorf("元素%d的父指针%d超出范围", i, p)
+		}
+		if p == i {
+			sets.Sets++
+		}
+	}
+
+	sets.Elements = append(sets.Elements, elements...)
+	sets.Parent = append(sets.Parent, parent...)
+	sets.Rank = append(sets.Rank, rank...)
+	sets.Size = append(sets.Size, size...)
+	return sets, nil
+}
+
+// 以 elements 中的每个元素单独成集，不记录追踪；下标与 elements 一致
+func singletons[T any](config DisjointSetConfig, elements []T, kind *ElementType[T]) (*DisjointSet[T], error) {
+	n := len(elements)
+	parent, rank, size := make([]int, n), make([]int, n), make([]int, n)
+	for i := range n {
+		parent[i] = i
+		size[i] = 1
+	}
+	return RestoreDisjointSet(config, elements, parent, rank, size, kind)
+}
+
+// Kind 并查集的元素类型
+func (sets *DisjointSet[T]) Kind() *ElementType[T] {
+	return sets.kind
+}
+
+// Config 并查集的创建参数
+func (sets *DisjointSetState) Config() DisjointSetConfig {
+	return DisjointSetConfig{UnionBy: sets.UnionBy, PathCompression: sets.PathCompression}
+}
+
+// 元素对应的下标，不存在时返回-1
+func (sets *DisjointSet[T]) index(value T) int {
+	for i, element := range sets.Elements {
+		if sets.kind.Equal(element, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// 元素在追踪中的标识
+func (sets *DisjointSet[T]) label(x int) string {
+	return keyText(sets.Elements[x])
+}
+
+// 修改父指针
+func (sets *DisjointSet[T]) setParent(x, p int, action, detail string) {
+	old := sets.Parent[x]
+	sets.Parent[x] = p
+	sets.cost.Writes++
+	sets.record(TraceStep{
+		Action: action,
+		Index:  intRef(x),
+		From:   intRef(old),
+		To:     intRef(p),
+		Node:   sets.label(x),
+		Target: sets.label(p),
+		Detail: detail,
+	})
+}
+
+// MakeSet 加入新元素，自成一个集合；元素已存在时返回 ErrDuplicate
+func (sets *DisjointSet[T]) MakeSet(value T) error {
+	if sets.index(value) >= 0 {
+		return ErrDuplicate
+	}
+
+	x := len(sets.Elements)
+	sets.Elements = append(sets.Elements, value)
+	sets.Parent = append(sets.Parent, x)
+	sets.Rank = append(sets.Rank, 0)
+	sets.Size = append(sets.Size, 1)
+	sets.Sets++
+	sets.cost.Writes++
+	sets.record(TraceStep{
+		Action: StepMakeSet,
+		Index:  intRef(x),
+		Value:  value,
+		Node:   sets.label(x),
+		Target: sets.label(x),
+		Detail: fmt.Sprintf("加入元素%s，下标%d，parent[%d] = %d，自成一个集合", sets.label(x), x, x, x),
+	})
+	return nil
+}
+
+// 沿父指针找到 x 所在树的根；compress 为 true 时再走一遍路径，把途经的节点直接挂到根下
+func (sets *DisjointSet[T]) find(x int, compress bool) int {
+	path := make([]int, 0)
+	root := x
+	for {
+		sets.cost.Comparisons++
+		if sets.Parent[root] == root {
+			break
+		}
+		path = append(path, root)
+		sets.record(TraceStep{
+			Action: StepVisit,
+			Index:  intRef(root),
+			Node:   sets.label(root),
+			Target: sets.label(sets.Parent[root]),
+			Detail: fmt.Sprintf("parent[%s] = %s，不是根，继续向上", sets.label(root), sets.label(sets.Parent[root])),
+		})
+		root = sets.Parent[root]
+	}
+	sets.record(TraceStep{
+		Action: StepVisit,
+		Index:  intRef(root),
+		Node:   sets.label(root),
+		Target: sets.label(root),
+		Detail: fmt.Sprintf("parent[%s] = %s，%s是根，查找路径长%d", sets.label(root), sets.label(root), sets.label(root), len(path)),
+	})
+
+	if !compress {
+		return root
+	}
+	for _, node := range path {
+		if sets.Parent[node] == root {
+			continue
+		}
+		sets.setParent(node, root, StepCompress,
+			fmt.Sprintf("路径压缩：%s原来挂在%s下，直接挂到根%s下", sets.label(node), sets.label(sets.Parent[node]), sets.label(root)))
+	}
+	return root
+}
+
+// 合并 a 和 b 所在的集合，已在同一集合时返回 false
+func (sets *DisjointSet[T]) union(a, b int) bool {
+	ra, rb := sets.find(a, sets.PathCompression), sets.find(b, sets.PathCompression)
+	sets.cost.Comparisons++
+	if ra == rb {
+		sets.record(TraceStep{
+			Action: StepCase,
+			Node:   sets.label(ra),
+			Detail: fmt.Sprintf("%s和%s的根都是%s，已在同一集合", sets.label(a), sets.label(b), sets.label(ra)),
+		})
+		return false
+	}
+
+	if sets.UnionBy == UnionBySize {
+		sets.cost.Comparisons++
+		if sets.Size[ra] < sets.Size[rb] {
+			ra, rb = rb, ra
+		}
+		sets.setParent(rb, ra, StepLink,
+			fmt.Sprintf("按大小合并：%s的树有%d个元素，%s的树有%d个，把%s挂到%s下", sets.label(ra), sets.Size[ra], sets.label(rb), sets.Size[rb], sets.label(rb), sets.label(ra)))
+		sets.Size[ra] += sets.Size[rb]
+		sets.cost.Writes++
+		sets.record(TraceStep{
+			Action: StepWrite,
+			Index:  intRef(ra),
+			Value:  sets.Size[ra],
+			Node:   sets.label(ra),
+			Detail: fmt.Sprintf("size[%s] = %d", sets.label(ra), sets.Size[ra]),
+		})
+	} else {
+		sets.cost.Comparisons++
+		if sets.Rank[ra] < sets.Rank[rb] {
+			ra, rb = rb, ra
+		}
+		sets.setParent(rb, ra, StepLink,
+			fmt.Sprintf("按秩合并：rank[%s] = %d，rank[%s] = %d，把%s挂到%s下", sets.label(ra), sets.Rank[ra], sets.label(rb), sets.Rank[rb], sets.label(rb), sets.label(ra)))
+		if sets.Rank[ra] == sets.Rank[rb] {
+			sets.Rank[ra]++
+			sets.cost.Writes++
+			sets.record(TraceStep{
+				Action: StepWrite,
+				Index:  intRef(ra),
+				Value:  sets.Rank[ra],
+				Node:   sets.label(ra),
+				Detail: fmt.Sprintf("两棵树的秩相同，rank[%s]增加到%d", sets.label(ra), sets.Rank[ra]),
+			})
+		}
+	}
+	sets.Sets--
+	return true
+}
+
+// Find 返回元素所在集合的根（代表元）；compress 为 true 时压缩查找路径。元素不存在时返回 ErrNotFound
+func (sets *DisjointSet[T]) Find(value T, compress bool) (T, error) {
+	x := sets.index(value)
+	if x < 0 {
+		var zero T
+		return zero, ErrNotFound
+	}
+	return sets.Elements[sets.find(x, compress)], nil
+}
+
+// Union 合并两个元素所在的集合，返回是否发生了合并；内部查找按 PathCompression 决定是否压缩路径
+func (sets *DisjointSet[T]) Union(a, b T) (bool, error) {
+	x, y := sets.index(a), sets.index(b)
+	if x < 0 || y < 0 {
+		return false, ErrNotFound
+	}
+	return sets.union(x, y), nil
+}
+
+// Groups 按根分组的各集合，集合按其中最小的元素下标排列，集合内按元素下标排列
+func (sets *DisjointSet[T]) Groups() [][]T {
+	groups := make([][]T, 0, sets.Sets)
+	position := make(map[int]int)
+	for x := range sets.Elements {
+		root := x
+		for sets.Parent[root] != root {
+			root = sets.Parent[root]
+		}
+		if _, ok := position[root]; !ok {
+			position[root] = len(groups)
+			groups = append(groups, nil)
+		}
+		groups[position[root]] = append(groups[position[root]], sets.Elements[x])
+	}
+	return groups
+}
@@ -0,0 +1,145 @@
+package ds
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 元素类型
+const (
+	ElementInt    = "int"
+	ElementFloat  = "float"
+	ElementString = "string"
+	ElementBool   = "bool"
+	ElementObject = "object"
+)
+
+// object 类型元素用于排序和比较的字段名
+const recordKey = "key"
+
+// Record object 类型的元素：必须带字符串 key 字段的 JSON 对象，比较和排序只看 key，其余字段原样保存
+type Record map[string]any
+
+// 记录的排序键
+func (r Record) Key() string {
+	key, _ := r[recordKey].(string)
+	return key
+}
+
+// 以紧凑的 JSON 形式显示，用于追踪和提示信息
+func (r Record) String() string {
+	data, err := json.Marshal(map[string]any(r))
+	if err != nil {
+		return fmt.Sprintf("{key: %s}", r.Key())
+	}
+	return string(data)
+}
+
+// ElementType 元素类型描述，在创建数据结构时选定，决定元素的解析、校验和比较方式
+type ElementType[T any] struct {
+	Name    string
+	Compare func(a, b T) int          // a 在 b 之前返回负数，相等返回0
+	Parse   func(s string) (T, error) // 解析路径参数中的值，object 类型解析为只有 key 的记录
+	Check   func(v T) error           // 校验从 JSON 解码的值，可为 nil
+	Integer func(v T) int             // 仅整数类型提供，供计数排序和基数排序使用
+	Number  func(v T) float64         // 仅数值类型提供，供插值查找使用
+}
+
+// 从请求体的 JSON 值解码元素
+func (t *ElementType[T]) Decode(raw json.RawMessage) (T, error) {
+	var value T
+	if len(raw) == 0 || string(raw) == "null" {
+		return value, ErrMissingValue
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return value, fmt.Errorf("元素值必须是%s类型", t.Name)
+	}
+	if t.Check != nil {
+		if err := t.Check(value); err != nil {
+			return value, err
+		}
+	}
+	return value, nil
+}
+
+// 两个元素是否相等（按类型的比较规则，object 类型只比较 key）
+func (t *ElementType[T]) Equal(a, b T) bool {
+	return t.Compare(a, b) == 0
+}
+
+// Ints 整数元素
+var Ints = &ElementType[int]{
+	Name:    ElementInt,
+	Compare: cmp.Compare[int],
+	Parse: func(s string) (int, error) {
+		value, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, errors.New("值必须是整数")
+		}
+		return value, nil
+	},
+	Integer: func(v int) int { return v },
+	Number:  func(v int) float64 { return float64(v) },
+}
+
+// Floats 有限浮点数元素
+var Floats = &ElementType[float64]{
+	Name:    ElementFloat,
+	Compare: cmp.Compare[float64],
+	Parse: func(s string) (float64, error) {
+		value, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return 0, errors.New("值必须是有限的浮点数")
+		}
+		return value, nil
+	},
+	Number: func(v float64) float64 { return v },
+}
+
+// Strings 字符串元素，按字典序比较
+var Strings = &ElementType[string]{
+	Name:    ElementString,
+	Compare: strings.Compare,
+	Parse:   func(s string) (string, error) { return s, nil },
+}
+
+// Bools 布尔元素
+var Bools = &ElementType[bool]{
+	Name: ElementBool,
+	// false 排在 true 之前
+	Compare: func(a, b bool) int {
+		switch {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		default:
+			return 1
+		}
+	},
+	Parse: func(s string) (bool, error) {
+		value, err := strconv.ParseBool(s)
+		if err != nil {
+			return false, errors.New("值必须是true或false")
+		}
+		return value, nil
+	},
+}
+
+// Objects 带 key 字段的对象元素
+var Objects = &ElementType[Record]{
+	Name:    ElementObject,
+	Compare: func(a, b Record) int { return strings.Compare(a.Key(), b.Key()) },
+	Parse:   func(s string) (Record, error) { return Record{recordKey: s}, nil },
+	Check: func(v Record) error {
+		if _, ok := v[recordKey].(string); !ok {
+			return fmt.Errorf("object类型的元素必须包含字符串类型的%s字段", recordKey)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,24 @@
+// Package ds 提供可视化演示所用的数据结构实现：动态数组、链表、环形缓冲区、基于它们的栈和队列、二叉堆、二叉搜索树和平衡树、哈希表、图及其遍历和最短路径算法、字典树、并查集、线段树和树状数组、跳表，以及数组上的排序和查找算法。
+// 每个操作在修改结构的同时记录基本操作计数和执行追踪，不依赖 HTTP 层，可供服务端、命令行工具和自动评测共用。
+package ds
+
+import "errors"
+
+var (
+	// ErrIndexOutOfRange 索引或插入位置超出范围
+	ErrIndexOutOfRange = errors.New("索引超出范围")
+	// ErrFull 容量用尽且扩容策略不允许扩容
+	ErrFull = errors.New("容量已满")
+	// ErrMissingValue 请求中缺少元素值
+	ErrMissingValue = errors.New("缺少元素值")
+	// ErrEmpty 对空结构执行出栈、出队等需要元素的操作
+	ErrEmpty = errors.New("数据结构为空")
+	// ErrNotFound 要删除或查询的元素不存在
+	ErrNotFound = errors.New("元素不存在")
+	// ErrDuplicate 向不允许重复的结构插入已有的元素
+	ErrDuplicate = errors.New("元素已存在")
+	// ErrCycle 有向图中存在环，无法拓扑排序
+	ErrCycle = errors.New("图中存在环")
+	// ErrNegativeCycle 从源点可达负权环，最短路径不存在
+	ErrNegativeCycle = errors.New("存在从源点可达的负权环")
+)
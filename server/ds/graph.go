@@ -0,0 +1,551 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// 图的存储方式
+const (
+	GraphList   = "list"   // 邻接表：每个顶点一条出边链表，沿用链表节点
+	GraphMatrix = "matrix" // 邻接矩阵：Matrix[u][v] 为 u→v 的权重，无边为 nil
+)
+
+// GraphConfig 创建图的参数
+type GraphConfig struct {
+	Directed       bool   `json:"directed"`
+	Weighted       bool   `json:"weighted"`
+	Representation string `json:"representation"` // 为空时使用邻接表
+}
+
+// GraphArc 邻接表中的一条出边，To 为终点的顶点下标；无权图的权重恒为1
+type GraphArc struct {
+	To     int     `json:"to"`
+	Weight float64 `json:"weight"`
+}
+
+// GraphEdge 以顶点值表示的一条边，用于返回结果
+type GraphEdge[T any] struct {
+	From   T       `json:"from"`
+	To     T       `json:"to"`
+	Weight float64 `json:"weight"`
+}
+
+// GraphState 图中与顶点类型无关的状态
+type GraphState struct {
+	Directed       bool   `json:"directed"`
+	Weighted       bool   `json:"weighted"`
+	Representation string `json:"representation"`
+	EdgeCount      int    `json:"edgeCount"` // 无向边只计一次
+
+	Recorder `json:"-"`
+}
+
+// Graph 图，T 为顶点的元素类型；顶点按加入顺序编号，边可以存放在邻接表或邻接矩阵中，随时切换
+type Graph[T any] struct {
+	GraphState
+	Vertices  []T               `json:"vertices"`
+	Adjacency []*Node[GraphArc] `json:"-"` // 仅邻接表：各顶点出边链表的头节点
+	Matrix    [][]*float64      `json:"-"` // 仅邻接矩阵
+
+	kind *ElementType[T]
+}
+
+// NewGraph 按配置创建空图
+func NewGraph[T any](config GraphConfig, kind *ElementType[T]) (*Graph[T], error) {
+	if config.Representation == "" {
+		config.Representation = GraphList
+	}
+	if config.Representation != GraphList && config.Representation != GraphMatrix {
+		return nil, errors.New("存储方式必须是list或matrix")
+	}
+
+	graph := &Graph[T]{Vertices: make([]T, 0), kind: kind}
+	graph.Directed = config.Directed
+	graph.Weighted = config.Weighted
+	graph.Representation = config.Representation
+	graph.Begin()
+	return graph, nil
+}
+
+// RestoreGraph 按顶点和各顶点的出边（邻接表中的顺序）直接重建图，不记录追踪
+func RestoreGraph[T any](config GraphConfig, vertices []T, arcs [][]GraphArc, kind *ElementType[T]) (*Graph[T], error) {
+	graph, err := NewGraph(config, kind)
+	if err != nil {
+		return nil, err
+	}
+	if len(arcs) != len(vertices) {
+		return nil, errors.New("出边列表与顶点数不一致")
+	}
+
+	for _, vertex := range vertices {
+		graph.allocateVertex(vertex)
+	}
+	for u, list := range arcs {
+		for _, arc := range list {
+			if arc.To < 0 || arc.To >= len(vertices) {
+				return nil, fmt.Errorf("边的终点下标%d超出顶点数%d", arc.To, len(vertices))
+			}
+			graph.placeArc(u, arc, false)
+			if graph.Directed || u <= arc.To {
+				graph.EdgeCount++
+			}
+		}
+	}
+
+	graph.Begin()
+	return graph, nil
+}
+
+// Kind 顶点的元素类型
+func (graph *Graph[T]) Kind() *ElementType[T] {
+	return graph.kind
+}
+
+// Config 当前的方向、权重和存储方式
+func (graph *GraphState) Config() GraphConfig {
+	return GraphConfig{
+		Directed:       graph.Directed,
+		Weighted:       graph.Weighted,
+		Representation: graph.Representation,
+	}
+}
+
+// Saved 按顶点顺序返回各顶点的出边，邻接表保持链中顺序，供持久化后用 RestoreGraph 重建
+func (graph *Graph[T]) Saved() [][]GraphArc {
+	arcs := make([][]GraphArc, len(graph.Vertices))
+	for u := range graph.Vertices {
+		arcs[u] = make([]GraphArc, 0)
+		for arc := range graph.arcs(u) {
+			arcs[u] = append(arcs[u], arc)
+		}
+	}
+	return arcs
+}
+
+// Edges 所有边：有向图按起点顺序列出每条弧，无向图每条边只列出一次（起点下标不大于终点）
+func (graph *Graph[T]) Edges() []GraphEdge[T] {
+	edges := make([]GraphEdge[T], 0, graph.EdgeCount)
+	for u := range graph.Vertices {
+		for arc := range graph.arcs(u) {
+			if graph.Directed || u <= arc.To {
+				edges = append(edges, GraphEdge[T]{From: graph.Vertices[u], To: graph.Vertices[arc.To], Weight: arc.Weight})
+			}
+		}
+	}
+	return edges
+}
+
+// 顶点值对应的下标，不存在时返回-1
+func (graph *Graph[T]) index(value T) int {
+	for i, vertex := range graph.Vertices {
+		if graph.kind.Equal(vertex, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// 顶点在追踪中的标识
+func (graph *Graph[T]) vertexLabel(u int) string {
+	return keyText(graph.Vertices[u])
+}
+
+// 一组顶点的标识，用于记录边界状态
+func (graph *Graph[T]) vertexLabels(vertices []int) []string {
+	labels := make([]string, len(vertices))
+	for i, u := range vertices {
+		labels[i] = graph.vertexLabel(u)
+	}
+	return labels
+}
+
+// 邻接表中顶点 u 的第 i 个出边节点的标识
+func (graph *Graph[T]) arcLabel(u, i int) string {
+	return fmt.Sprintf("adj[%s].node[%d]", graph.vertexLabel(u), i)
+}
+
+// 边的文字描述，无权图省略权重
+func (graph *Graph[T]) edgeText(u, v int, weight float64) string {
+	arrow := "—"
+	if graph.Directed {
+		arrow = "→"
+	}
+	text := graph.vertexLabel(u) + arrow + graph.vertexLabel(v)
+	if graph.Weighted {
+		text += fmt.Sprintf("（权重%g）", weight)
+	}
+	return text
+}
+
+// 不记录追踪地遍历顶点 u 的出边
+func (graph *Graph[T]) arcs(u int) iter.Seq[GraphArc] {
+	return func(yield func(GraphArc) bool) {
+		if graph.Representation == GraphList {
+			for node := graph.Adjacency[u]; node != nil; node = node.Next {
+				if !yield(node.Value) {
+					return
+				}
+			}
+			return
+		}
+		for v, weight := range graph.Matrix[u] {
+			if weight != nil && !yield(GraphArc{To: v, Weight: *weight}) {
+				return
+			}
+		}
+	}
+}
+
+// 遍历顶点 u 的出边并记录追踪：邻接表沿链表逐个访问出边节点，邻接矩阵逐格读取第 u 行（包括没有边的格子）
+func (graph *Graph[T]) neighbors(u int) iter.Seq[GraphArc] {
+	return func(yield func(GraphArc) bool) {
+		if graph.Representation == GraphList {
+			i := 0
+			for node := graph.Adjacency[u]; node != nil; node = node.Next {
+				graph.record(TraceStep{
+					Action: StepEdge,
+					Value:  node.Value.Weight,
+					Node:   graph.vertexLabel(u),
+					Target: graph.vertexLabel(node.Value.To),
+					Detail: fmt.Sprintf("访问%s，检查边%s", graph.arcLabel(u, i), graph.edgeText(u, node.Value.To, node.Value.Weight)),
+				})
+				if !yield(node.Value) {
+					return
+				}
+				i++
+			}
+			return
+		}
+
+		for v, weight := range graph.Matrix[u] {
+			if weight == nil {
+				graph.record(TraceStep{
+					Action: StepRead,
+					From:   intRef(u),
+					To:     intRef(v),
+					Node:   graph.vertexLabel(u),
+					Target: graph.vertexLabel(v),
+					Detail: fmt.Sprintf("读取矩阵[%s][%s]：无边", graph.vertexLabel(u), graph.vertexLabel(v)),
+				})
+				continue
+			}
+			graph.record(TraceStep{
+				Action: StepEdge,
+				From:   intRef(u),
+				To:     intRef(v),
+				Value:  *weight,
+				Node:   graph.vertexLabel(u),
+				Target: graph.vertexLabel(v),
+				Detail: fmt.Sprintf("读取矩阵[%s][%s]，检查边%s", graph.vertexLabel(u), graph.vertexLabel(v), graph.edgeText(u, v, *weight)),
+			})
+			if !yield(GraphArc{To: v, Weight: *weight}) {
+				return
+			}
+		}
+	}
+}
+
+// 为新顶点分配空的出边链表或矩阵的一行一列
+func (graph *Graph[T]) allocateVertex(value T) {
+	graph.Vertices = append(graph.Vertices, value)
+	if graph.Representation == GraphList {
+		graph.Adjacency = append(graph.Adjacency, nil)
+		return
+	}
+	for u := range graph.Matrix {
+		graph.Matrix[u] = append(graph.Matrix[u], nil)
+	}
+	graph.Matrix = append(graph.Matrix, make([]*float64, len(graph.Vertices)))
+}
+
+// 把出边放入邻接表的链尾或矩阵的对应格子，record 为 true 时记录追踪
+func (graph *Graph[T]) placeArc(u int, arc GraphArc, record bool) {
+	if graph.Representation == GraphMatrix {
+		weight := arc.Weight
+		graph.Matrix[u][arc.To] = &weight
+		if record {
+			graph.cost.Writes++
+			graph.record(TraceStep{
+				Action: StepWrite,
+				From:   intRef(u),
+				To:     intRef(arc.To),
+				Value:  weight,
+				Node:   graph.vertexLabel(u),
+				Target: graph.vertexLabel(arc.To),
+				Detail: fmt.Sprintf("矩阵[%s][%s] = %g", graph.vertexLabel(u), graph.vertexLabel(arc.To), weight),
+			})
+		}
+		return
+	}
+
+	node := &Node[GraphArc]{Value: arc}
+	var tail *Node[GraphArc]
+	position := 0
+	for current := graph.Adjacency[u]; current != nil; current = current.Next {
+		tail = current
+		position++
+	}
+	if tail == nil {
+		graph.Adjacency[u] = node
+	} else {
+		tail.Next = node
+	}
+	if !record {
+		return
+	}
+
+	graph.cost.Writes++
+	graph.record(TraceStep{
+		Action: StepCreate,
+		Value:  arc.Weight,
+		Node:   "new",
+		Target: graph.vertexLabel(arc.To),
+		Detail: fmt.Sprintf("为边%s创建出边节点", graph.edgeText(u, arc.To, arc.Weight)),
+	})
+	if tail == nil {
+		graph.record(TraceStep{
+			Action: StepSetHead,
+			Node:   graph.vertexLabel(u),
+			Target: graph.arcLabel(u, position),
+			Detail: fmt.Sprintf("adj[%s] = %s", graph.vertexLabel(u), graph.arcLabel(u, position)),
+		})
+		return
+	}
+	graph.record(TraceStep{
+		Action: StepSetNext,
+		Node:   graph.arcLabel(u, position-1),
+		Target: graph.arcLabel(u, position),
+		Detail: fmt.Sprintf("%s.Next = %s", graph.arcLabel(u, position-1), graph.arcLabel(u, position)),
+	})
+}
+
+// 查找 u→v 的出边并记录沿途访问，返回权重和是否存在
+func (graph *Graph[T]) findArc(u, v int) (float64, bool) {
+	for arc := range graph.neighbors(u) {
+		if arc.To == v {
+			return arc.Weight, true
+		}
+	}
+	return 0, false
+}
+
+// 删除 u→v 的出边，不存在时返回 false
+func (graph *Graph[T]) removeArc(u, v int) bool {
+	if graph.Representation == GraphMatrix {
+		if graph.Matrix[u][v] == nil {
+			return false
+		}
+		graph.Matrix[u][v] = nil
+		graph.cost.Writes++
+		graph.record(TraceStep{
+			Action: StepWrite,
+			From:   intRef(u),
+			To:     intRef(v),
+			Node:   graph.vertexLabel(u),
+			Target: graph.vertexLabel(v),
+			Detail: fmt.Sprintf("矩阵[%s][%s] = 无边", graph.vertexLabel(u), graph.vertexLabel(v)),
+		})
+		return true
+	}
+
+	var prev *Node[GraphArc]
+	i := 0
+	for node := graph.Adjacency[u]; node != nil; node = node.Next {
+		if node.Value.To != v {
+			prev = node
+			i++
+			continue
+		}
+		next := graph.arcLabel(u, i+1)
+		if node.Next == nil {
+			next = "nil"
+		}
+		if prev == nil {
+			graph.Adjacency[u] = node.Next
+			graph.record(TraceStep{
+				Action: StepSetHead,
+				Node:   graph.vertexLabel(u),
+				Target: next,
+				Detail: fmt.Sprintf("adj[%s] = %s", graph.vertexLabel(u), next),
+			})
+		} else {
+			prev.Next = node.Next
+			graph.record(TraceStep{
+				Action: StepSetNext,
+				Node:   graph.arcLabel(u, i-1),
+				Target: next,
+				Detail: fmt.Sprintf("%s.Next = %s", graph.arcLabel(u, i-1), next),
+			})
+		}
+		graph.record(TraceStep{
+			Action: StepFree,
+			Node:   graph.arcLabel(u, i),
+			Target: graph.vertexLabel(v),
+			Detail: fmt.Sprintf("出边节点%s脱离邻接表", graph.arcLabel(u, i)),
+		})
+		return true
+	}
+	return false
+}
+
+// AddVertex 加入顶点，顶点已存在时返回 ErrDuplicate
+func (graph *Graph[T]) AddVertex(value T) error {
+	if graph.index(value) >= 0 {
+		return ErrDuplicate
+	}
+	graph.allocateVertex(value)
+	u := len(graph.Vertices) - 1
+
+	detail := fmt.Sprintf("加入顶点%s，下标%d，出边链表为空", graph.vertexLabel(u), u)
+	if graph.Representation == GraphMatrix {
+		detail = fmt.Sprintf("加入顶点%s，下标%d，矩阵扩展为%d×%d", graph.vertexLabel(u), u, len(graph.Vertices), len(graph.Vertices))
+	}
+	graph.record(TraceStep{
+		Action: StepCreate,
+		Index:  intRef(u),
+		Value:  value,
+		Node:   graph.vertexLabel(u),
+		Detail: detail,
+	})
+	return nil
+}
+
+// RemoveVertex 删除顶点及与它相连的所有边，后面顶点的下标依次减1；顶点不存在时返回 ErrNotFound
+func (graph *Graph[T]) RemoveVertex(value T) error {
+	x := graph.index(value)
+	if x < 0 {
+		return ErrNotFound
+	}
+
+	// 先删除指向 x 的边，再删除 x 的出边
+	removed := 0
+	for u := range graph.Vertices {
+		if u != x && graph.removeArc(u, x) && graph.Directed {
+			removed++
+		}
+	}
+	for arc := range graph.arcs(x) {
+		if graph.removeArc(x, arc.To) {
+			removed++
+		}
+	}
+	graph.EdgeCount -= removed
+
+	graph.record(TraceStep{
+		Action: StepFree,
+		Index:  intRef(x),
+		Value:  value,
+		Node:   graph.vertexLabel(x),
+		Detail: fmt.Sprintf("删除顶点%s，其后顶点的下标依次减1", graph.vertexLabel(x)),
+	})
+	graph.Vertices = append(graph.Vertices[:x], graph.Vertices[x+1:]...)
+	if graph.Representation == GraphList {
+		graph.Adjacency = append(graph.Adjacency[:x], graph.Adjacency[x+1:]...)
+		for _, head := range graph.Adjacency {
+			for node := head; node != nil; node = node.Next {
+				if node.Value.To > x {
+					node.Value.To--
+				}
+			}
+		}
+		return nil
+	}
+	graph.Matrix = append(graph.Matrix[:x], graph.Matrix[x+1:]...)
+	for u := range graph.Matrix {
+		graph.Matrix[u] = append(graph.Matrix[u][:x], graph.Matrix[u][x+1:]...)
+	}
+	return nil
+}
+
+// 按顶点值查找两个端点的下标
+func (graph *Graph[T]) endpoints(from, to T) (int, int, error) {
+	u, v := graph.index(from), graph.index(to)
+	if u < 0 {
+		return -1, -1, fmt.Errorf("顶点%s不存在", keyText(from))
+	}
+	if v < 0 {
+		return -1, -1, fmt.Errorf("顶点%s不存在", keyText(to))
+	}
+	return u, v, nil
+}
+
+// AddEdge 加入一条边，无权图忽略 weight 按1处理；无向图同时加入两个方向的出边。
+// 边已存在时返回 ErrDuplicate，无向图不允许自环
+func (graph *Graph[T]) AddEdge(from, to T, weight float64) error {
+	u, v, err := graph.endpoints(from, to)
+	if err != nil {
+		return err
+	}
+	if !graph.Directed && u == v {
+		return errors.New("无向图不支持自环")
+	}
+	if !graph.Weighted {
+		weight = 1
+	}
+
+	if _, exists := graph.findArc(u, v); exists {
+		return ErrDuplicate
+	}
+	graph.placeArc(u, GraphArc{To: v, Weight: weight}, true)
+	if !graph.Directed {
+		graph.placeArc(v, GraphArc{To: u, Weight: weight}, true)
+	}
+	graph.EdgeCount++
+	return nil
+}
+
+// RemoveEdge 删除一条边，无向图同时删除两个方向的出边；边不存在时返回 ErrNotFound
+func (graph *Graph[T]) RemoveEdge(from, to T) error {
+	u, v, err := graph.endpoints(from, to)
+	if err != nil {
+		return err
+	}
+	if !graph.removeArc(u, v) {
+		return ErrNotFound
+	}
+	if !graph.Directed {
+		graph.removeArc(v, u)
+	}
+	graph.EdgeCount--
+	return nil
+}
+
+// SetRepresentation 把所有边搬到另一种存储方式中，邻接表中的出边按原顺序（矩阵按列顺序）排列
+func (graph *Graph[T]) SetRepresentation(representation string) error {
+	if representation != GraphList && representation != GraphMatrix {
+		return errors.New("存储方式必须是list或matrix")
+	}
+	if representation == graph.Representation {
+		return nil
+	}
+
+	arcs := graph.Saved()
+	vertices := graph.Vertices
+	graph.Representation = representation
+	graph.Vertices = make([]T, 0, len(vertices))
+	graph.Adjacency = nil
+	graph.Matrix = nil
+	for _, vertex := range vertices {
+		graph.allocateVertex(vertex)
+	}
+
+	n := len(vertices)
+	detail := fmt.Sprintf("分配%d条空的出边链表", n)
+	if representation == GraphMatrix {
+		detail = fmt.Sprintf("分配%d×%d的邻接矩阵", n, n)
+	}
+	graph.cost.Reallocations++
+	graph.record(TraceStep{
+		Action: StepAllocate,
+		Detail: detail,
+	})
+	for u, list := range arcs {
+		for _, arc := range list {
+			graph.cost.Copies++
+			graph.placeArc(u, arc, true)
+		}
+	}
+	return nil
+}
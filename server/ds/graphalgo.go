@@ -0,0 +1,642 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// 图算法
+const (
+	GraphBFS         = "bfs"
+	GraphDFS         = "dfs"
+	GraphTopological = "topological"
+	GraphDijkstra    = "dijkstra"
+	GraphBellmanFord = "bellman_ford"
+	GraphPrim        = "prim"
+	GraphKruskal     = "kruskal"
+	GraphComponents  = "components"
+)
+
+// ShortestPath 从源点到一个顶点的最短路径
+type ShortestPath[T any] struct {
+	Vertex   T        `json:"vertex"`
+	Distance *float64 `json:"distance"`       // 不可达时为 null
+	Path     []T      `json:"path,omitempty"` // 从源点出发依次经过的顶点
+}
+
+// GraphResult 图算法的结果，只填写与算法相关的字段
+type GraphResult[T any] struct {
+	Order       []T               `json:"order,omitempty"`       // 遍历的访问顺序、拓扑序或顶点加入生成树的顺序
+	Edges       []GraphEdge[T]    `json:"edges,omitempty"`       // 遍历树或最小生成树的边，按加入顺序
+	Paths       []ShortestPath[T] `json:"paths,omitempty"`       // 按顶点顺序的最短路径
+	TotalWeight *float64          `json:"totalWeight,omitempty"` // 最小生成树（森林）的总权重
+	Components  [][]T             `json:"components,omitempty"`  // 连通分量（有向图为强连通分量）
+}
+
+// 记录开始处理顶点
+func (graph *Graph[T]) visitVertex(u int, frontier []string, detail string) {
+	graph.record(TraceStep{
+		Action:   StepVisit,
+		Index:    intRef(u),
+		Value:    graph.Vertices[u],
+		Node:     graph.vertexLabel(u),
+		Frontier: frontier,
+		Detail:   detail,
+	})
+}
+
+// 记录顶点被发现
+func (graph *Graph[T]) discover(u int, frontier []string, detail string) {
+	graph.record(TraceStep{
+		Action:   StepDiscover,
+		Index:    intRef(u),
+		Value:    graph.Vertices[u],
+		Node:     graph.vertexLabel(u),
+		Frontier: frontier,
+		Detail:   detail,
+	})
+}
+
+// 记录边加入遍历树或生成树
+func (graph *Graph[T]) treeEdge(u int, arc GraphArc) GraphEdge[T] {
+	graph.record(TraceStep{
+		Action: StepTreeEdge,
+		Value:  arc.Weight,
+		Node:   graph.vertexLabel(u),
+		Target: graph.vertexLabel(arc.To),
+		Detail: fmt.Sprintf("边%s加入树", graph.edgeText(u, arc.To, arc.Weight)),
+	})
+	return GraphEdge[T]{From: graph.Vertices[u], To: graph.Vertices[arc.To], Weight: arc.Weight}
+}
+
+// 说明进入的情况分支
+func (graph *Graph[T]) explain(frontier []string, detail string) {
+	graph.record(TraceStep{
+		Action:   StepCase,
+		Frontier: frontier,
+		Detail:   detail,
+	})
+}
+
+// 起点的下标，不存在时返回错误
+func (graph *Graph[T]) source(start T) (int, error) {
+	s := graph.index(start)
+	if s < 0 {
+		return -1, fmt.Errorf("顶点%s不存在", keyText(start))
+	}
+	return s, nil
+}
+
+// BFS 从 start 出发广度优先遍历，边界为 FIFO 队列；顶点在入队时标记为已发现，保证每个顶点只入队一次
+func (graph *Graph[T]) BFS(start T) (*GraphResult[T], error) {
+	s, err := graph.source(start)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GraphResult[T]{Order: make([]T, 0, len(graph.Vertices))}
+	discovered := make([]bool, len(graph.Vertices))
+	queue := []int{s}
+	discovered[s] = true
+	graph.discover(s, graph.vertexLabels(queue), fmt.Sprintf("起点%s入队", graph.vertexLabel(s)))
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		graph.visitVertex(u, graph.vertexLabels(queue), fmt.Sprintf("%s出队", graph.vertexLabel(u)))
+		result.Order = append(result.Order, graph.Vertices[u])
+
+		for arc := range graph.neighbors(u) {
+			if discovered[arc.To] {
+				continue
+			}
+			discovered[arc.To] = true
+			queue = append(queue, arc.To)
+			result.Edges = append(result.Edges, graph.treeEdge(u, arc))
+			graph.discover(arc.To, graph.vertexLabels(queue), fmt.Sprintf("%s首次被发现，入队", graph.vertexLabel(arc.To)))
+		}
+	}
+	return result, nil
+}
+
+// DFS 从 start 出发深度优先遍历，边界为递归栈（当前路径）
+func (graph *Graph[T]) DFS(start T) (*GraphResult[T], error) {
+	s, err := graph.source(start)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GraphResult[T]{Order: make([]T, 0, len(graph.Vertices))}
+	visited := make([]bool, len(graph.Vertices))
+	path := make([]int, 0)
+
+	var dfs func(u int)
+	dfs = func(u int) {
+		visited[u] = true
+		path = append(path, u)
+		result.Order = append(result.Order, graph.Vertices[u])
+		graph.record(TraceStep{
+			Action:   StepRecurse,
+			Index:    intRef(u),
+			Value:    graph.Vertices[u],
+			Node:     graph.vertexLabel(u),
+			Frontier: graph.vertexLabels(path),
+			Detail:   fmt.Sprintf("进入dfs(%s)，标记为已访问", graph.vertexLabel(u)),
+		})
+
+		for arc := range graph.neighbors(u) {
+			if visited[arc.To] {
+				continue
+			}
+			result.Edges = append(result.Edges, graph.treeEdge(u, arc))
+			dfs(arc.To)
+		}
+
+		path = path[:len(path)-1]
+		graph.record(TraceStep{
+			Action:   StepReturn,
+			Index:    intRef(u),
+			Node:     graph.vertexLabel(u),
+			Frontier: graph.vertexLabels(path),
+			Detail:   fmt.Sprintf("%s的出边检查完毕，从dfs(%s)返回", graph.vertexLabel(u), graph.vertexLabel(u)),
+		})
+	}
+	dfs(s)
+	return result, nil
+}
+
+// TopologicalSort 用 Kahn 算法求有向图的拓扑序：反复取出入度为0的顶点并删去它的出边，边界为入度为0的顶点队列；
+// 有环时返回已排好的部分和 ErrCycle
+func (graph *Graph[T]) TopologicalSort() (*GraphResult[T], error) {
+	if !graph.Directed {
+		return nil, errors.New("拓扑排序只适用于有向图")
+	}
+
+	n := len(graph.Vertices)
+	inDegree := make([]int, n)
+	for u := range n {
+		for arc := range graph.neighbors(u) {
+			inDegree[arc.To]++
+		}
+	}
+
+	result := &GraphResult[T]{Order: make([]T, 0, n)}
+	queue := make([]int, 0)
+	for u := range n {
+		if inDegree[u] == 0 {
+			queue = append(queue, u)
+			graph.discover(u, graph.vertexLabels(queue), fmt.Sprintf("%s的入度为0，入队", graph.vertexLabel(u)))
+		}
+	}
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		graph.visitVertex(u, graph.vertexLabels(queue), fmt.Sprintf("%s出队，排在拓扑序第%d位", graph.vertexLabel(u), len(result.Order)+1))
+		result.Order = append(result.Order, graph.Vertices[u])
+
+		for arc := range graph.neighbors(u) {
+			inDegree[arc.To]--
+			if inDegree[arc.To] == 0 {
+				queue = append(queue, arc.To)
+				graph.discover(arc.To, graph.vertexLabels(queue), fmt.Sprintf("%s的入度减为0，入队", graph.vertexLabel(arc.To)))
+			}
+		}
+	}
+
+	if len(result.Order) < n {
+		remaining := make([]int, 0)
+		for u := range n {
+			if inDegree[u] > 0 {
+				remaining = append(remaining, u)
+			}
+		}
+		graph.explain(graph.vertexLabels(remaining), fmt.Sprintf("队列已空但还有%d个顶点的入度不为0，它们位于环上或只能经由环到达", len(remaining)))
+		return result, ErrCycle
+	}
+	return result, nil
+}
+
+// 按前驱数组还原从源点到各顶点的最短路径
+func (graph *Graph[T]) shortestPaths(distance []float64, previous []int) []ShortestPath[T] {
+	paths := make([]ShortestPath[T], len(graph.Vertices))
+	for v := range graph.Vertices {
+		paths[v].Vertex = graph.Vertices[v]
+		if math.IsInf(distance[v], 1) {
+			continue
+		}
+		d := distance[v]
+		paths[v].Distance = &d
+		for u := v; u >= 0; u = previous[u] {
+			paths[v].Path = append(paths[v].Path, graph.Vertices[u])
+		}
+		slices.Reverse(paths[v].Path)
+	}
+	return paths
+}
+
+// 带距离的顶点标识，用于显示候选集合
+func (graph *Graph[T]) distanceLabels(vertices []int, distance []float64) []string {
+	labels := make([]string, len(vertices))
+	for i, u := range vertices {
+		labels[i] = fmt.Sprintf("%s(%s)", graph.vertexLabel(u), distanceText(distance[u]))
+	}
+	return labels
+}
+
+// 距离的文本形式，不可达显示为∞
+func distanceText(d float64) string {
+	if math.IsInf(d, 1) {
+		return "∞"
+	}
+	return fmt.Sprintf("%g", d)
+}
+
+// 尚未确定、但已有有限距离（键值）的候选顶点
+func candidates(done []bool, distance []float64) []int {
+	vertices := make([]int, 0)
+	for u := range done {
+		if !done[u] && !math.IsInf(distance[u], 1) {
+			vertices = append(vertices, u)
+		}
+	}
+	return vertices
+}
+
+// 候选顶点中距离（键值）最小的一个，比较计入计数；没有候选时返回-1
+func (graph *Graph[T]) closest(done []bool, distance []float64) int {
+	best := -1
+	for _, u := range candidates(done, distance) {
+		if best >= 0 {
+			graph.cost.Comparisons++
+		}
+		if best < 0 || distance[u] < distance[best] {
+			best = u
+		}
+	}
+	return best
+}
+
+// 初始化距离为无穷大、前驱为-1
+func (graph *Graph[T]) initDistances(s int) ([]float64, []int) {
+	n := len(graph.Vertices)
+	distance := make([]float64, n)
+	previous := make([]int, n)
+	for u := range n {
+		distance[u] = math.Inf(1)
+		previous[u] = -1
+	}
+	distance[s] = 0
+	return distance, previous
+}
+
+// Dijkstra 求单源最短路径：每轮从候选顶点中取出距离最小者确定下来并松弛它的出边（数组实现，O(V²)）；
+// 边界为已有有限距离但尚未确定的候选顶点。不支持负权边
+func (graph *Graph[T]) Dijkstra(start T) (*GraphResult[T], error) {
+	s, err := graph.source(start)
+	if err != nil {
+		return nil, err
+	}
+	for u := range graph.Vertices {
+		for arc := range graph.arcs(u) {
+			if arc.Weight < 0 {
+				return nil, fmt.Errorf("边%s的权重为负，Dijkstra不适用，请使用Bellman-Ford", graph.edgeText(u, arc.To, arc.Weight))
+			}
+		}
+	}
+
+	distance, previous := graph.initDistances(s)
+	done := make([]bool, len(graph.Vertices))
+	result := &GraphResult[T]{Order: make([]T, 0, len(graph.Vertices))}
+	graph.discover(s, graph.distanceLabels([]int{s}, distance), fmt.Sprintf("起点%s的距离为0，其余顶点为∞", graph.vertexLabel(s)))
+
+	for u := graph.closest(done, distance); u >= 0; u = graph.closest(done, distance) {
+		done[u] = true
+		result.Order = append(result.Order, graph.Vertices[u])
+		graph.visitVertex(u, graph.distanceLabels(candidates(done, distance), distance),
+			fmt.Sprintf("候选顶点中%s的距离%g最小，确定为最短距离", graph.vertexLabel(u), distance[u]))
+
+		for arc := range graph.neighbors(u) {
+			if done[arc.To] {
+				continue
+			}
+			graph.relax(u, arc, distance, previous, func() []string {
+				return graph.distanceLabels(candidates(done, distance), distance)
+			})
+		}
+	}
+
+	result.Paths = graph.shortestPaths(distance, previous)
+	return result, nil
+}
+
+// 尝试经由边 u→arc.To 缩短距离，成功时记录新距离和边界，返回是否更新
+func (graph *Graph[T]) relax(u int, arc GraphArc, distance []float64, previous []int, frontier func() []string) bool {
+	v := arc.To
+	graph.cost.Comparisons++
+	if distance[u]+arc.Weight >= distance[v] {
+		return false
+	}
+	old := distance[v]
+	distance[v] = distance[u] + arc.Weight
+	previous[v] = u
+	graph.record(TraceStep{
+		Action:   StepRelax,
+		Value:    distance[v],
+		Node:     graph.vertexLabel(u),
+		Target:   graph.vertexLabel(v),
+		Frontier: frontier(),
+		Detail:   fmt.Sprintf("dist[%s] + %g = %g < %s，更新dist[%s]，前驱为%s", graph.vertexLabel(u), arc.Weight, distance[v], distanceText(old), graph.vertexLabel(v), graph.vertexLabel(u)),
+	})
+	return true
+}
+
+// BellmanFord 求单源最短路径，允许负权边：最多 V-1 轮，每轮按顶点顺序松弛所有边，某轮没有更新时提前结束；
+// 边界为本轮距离被更新的顶点。第 V 轮仍能松弛说明存在从源点可达的负权环，返回 ErrNegativeCycle
+func (graph *Graph[T]) BellmanFord(start T) (*GraphResult[T], error) {
+	s, err := graph.source(start)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(graph.Vertices)
+	distance, previous := graph.initDistances(s)
+	graph.discover(s, []string{graph.vertexLabel(s)}, fmt.Sprintf("起点%s的距离为0，其余顶点为∞", graph.vertexLabel(s)))
+
+	for round := 1; round <= n; round++ {
+		updated := make([]int, 0)
+		if round < n {
+			graph.explain(nil, fmt.Sprintf("第%d轮：松弛所有边", round))
+		} else {
+			graph.explain(nil, fmt.Sprintf("第%d轮：检查是否仍有边可以松弛", round))
+		}
+		for u := range n {
+			if math.IsInf(distance[u], 1) {
+				continue
+			}
+			for arc := range graph.neighbors(u) {
+				if graph.relax(u, arc, distance, previous, func() []string {
+					if !slices.Contains(updated, arc.To) {
+						updated = append(updated, arc.To)
+					}
+					return graph.distanceLabels(updated, distance)
+				}) && round == n {
+					graph.explain(graph.distanceLabels(updated, distance), fmt.Sprintf("第%d轮仍能松弛边%s，存在负权环", n, graph.edgeText(u, arc.To, arc.Weight)))
+					return nil, ErrNegativeCycle
+				}
+			}
+		}
+		if len(updated) == 0 {
+			graph.explain(nil, fmt.Sprintf("第%d轮没有距离被更新，提前结束", round))
+			break
+		}
+	}
+
+	result := &GraphResult[T]{Paths: graph.shortestPaths(distance, previous)}
+	return result, nil
+}
+
+// Prim 从 start 出发求最小生成树：每轮把键值（连到树的最轻边权重）最小的候选顶点加入树（数组实现，O(V²)）；
+// 边界为与树相邻的候选顶点及其键值。只适用于无向图，图不连通时得到 start 所在分量的生成树
+func (graph *Graph[T]) Prim(start T) (*GraphResult[T], error) {
+	if graph.Directed {
+		return nil, errors.New("最小生成树只适用于无向图")
+	}
+	s, err := graph.source(start)
+	if err != nil {
+		return nil, err
+	}
+
+	key, parent := graph.initDistances(s)
+	inTree := make([]bool, len(graph.Vertices))
+	result := &GraphResult[T]{Order: make([]T, 0, len(graph.Vertices))}
+	total := 0.0
+	graph.discover(s, graph.distanceLabels([]int{s}, key), fmt.Sprintf("从%s开始，它的键值为0", graph.vertexLabel(s)))
+
+	for u := graph.closest(inTree, key); u >= 0; u = graph.closest(inTree, key) {
+		inTree[u] = true
+		result.Order = append(result.Order, graph.Vertices[u])
+		graph.visitVertex(u, graph.distanceLabels(candidates(inTree, key), key),
+			fmt.Sprintf("候选顶点中%s的键值%g最小，加入树", graph.vertexLabel(u), key[u]))
+		if parent[u] >= 0 {
+			result.Edges = append(result.Edges, graph.treeEdge(parent[u], GraphArc{To: u, Weight: key[u]}))
+			total += key[u]
+		}
+
+		for arc := range graph.neighbors(u) {
+			graph.cost.Comparisons++
+			if inTree[arc.To] || arc.Weight >= key[arc.To] {
+				continue
+			}
+			key[arc.To] = arc.Weight
+			parent[arc.To] = u
+			graph.discover(arc.To, graph.distanceLabels(candidates(inTree, key), key),
+				fmt.Sprintf("%s经边%s连到树更近，键值更新为%g", graph.vertexLabel(arc.To), graph.edgeText(u, arc.To, arc.Weight), arc.Weight))
+		}
+	}
+
+	if len(result.Order) < len(graph.Vertices) {
+		graph.explain(nil, fmt.Sprintf("还有%d个顶点不与%s连通，结果只是%s所在分量的生成树", len(graph.Vertices)-len(result.Order), graph.vertexLabel(s), graph.vertexLabel(s)))
+	}
+	result.TotalWeight = &total
+	return result, nil
+}
+
+// Kruskal 求最小生成森林：按权重从小到大考虑每条边，两端不在同一棵树中时加入，用按秩合并、压缩路径的并查集判断；
+// 边界为尚未考虑的边。只适用于无向图
+func (graph *Graph[T]) Kruskal() (*GraphResult[T], error) {
+	if graph.Directed {
+		return nil, errors.New("最小生成树只适用于无向图")
+	}
+
+	type edge struct {
+		u int
+		GraphArc
+	}
+	edges := make([]edge, 0, graph.EdgeCount)
+	for u := range graph.Vertices {
+		for arc := range graph.arcs(u) {
+			if u < arc.To {
+				edges = append(edges, edge{u, arc})
+			}
+		}
+	}
+	slices.SortStableFunc(edges, func(a, b edge) int {
+		graph.cost.Comparisons++
+		return cmpFloat(a.Weight, b.Weight)
+	})
+	remaining := func(i int) []string {
+		labels := make([]string, 0, len(edges)-i)
+		for _, e := range edges[i:] {
+			labels = append(labels, graph.edgeText(e.u, e.To, e.Weight))
+		}
+		return labels
+	}
+	graph.explain(remaining(0), fmt.Sprintf("把%d条边按权重从小到大排序", len(edges)))
+
+	// 并查集的下标与顶点下标一致，每次合并的查找和挂接步骤并入图的追踪
+	sets, err := singletons(DisjointSetConfig{UnionBy: UnionByRank, PathCompression: true}, graph.Vertices, graph.kind)
+	if err != nil {
+		return nil, err
+	}
+	result := &GraphResult[T]{}
+	total := 0.0
+	for i, e := range edges {
+		graph.record(TraceStep{
+			Action:   StepEdge,
+			Value:    e.Weight,
+			Node:     graph.vertexLabel(e.u),
+			Target:   graph.vertexLabel(e.To),
+			Frontier: remaining(i + 1),
+			Detail:   fmt.Sprintf("考虑边%s", graph.edgeText(e.u, e.To, e.Weight)),
+		})
+		sets.Begin()
+		merged := sets.union(e.u, e.To)
+		graph.trace = append(graph.trace, sets.trace...)
+		graph.cost.Comparisons += sets.cost.Comparisons
+		if !merged {
+			graph.explain(remaining(i+1), fmt.Sprintf("%s和%s已在同一棵树中，加入会形成环，跳过", graph.vertexLabel(e.u), graph.vertexLabel(e.To)))
+			continue
+		}
+		result.Edges = append(result.Edges, graph.treeEdge(e.u, e.GraphArc))
+		total += e.Weight
+		if len(result.Edges) == len(graph.Vertices)-1 {
+			graph.explain(remaining(i+1), fmt.Sprintf("已选%d条边，生成树完成", len(result.Edges)))
+			break
+		}
+	}
+
+	result.TotalWeight = &total
+	return result, nil
+}
+
+// 浮点数比较，用于排序
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Components 求连通分量：无向图从每个未访问的顶点出发做一次 BFS，边界为队列；
+// 有向图用 Kosaraju 算法求强连通分量，第一遍 DFS 按完成顺序入栈，第二遍在反向图上按出栈顺序 DFS
+func (graph *Graph[T]) Components() (*GraphResult[T], error) {
+	if graph.Directed {
+		return graph.stronglyConnected(), nil
+	}
+
+	visited := make([]bool, len(graph.Vertices))
+	result := &GraphResult[T]{Components: make([][]T, 0)}
+	for s := range graph.Vertices {
+		if visited[s] {
+			continue
+		}
+		component := make([]T, 0)
+		queue := []int{s}
+		visited[s] = true
+		graph.discover(s, graph.vertexLabels(queue), fmt.Sprintf("%s未被访问，从它开始第%d个连通分量", graph.vertexLabel(s), len(result.Components)+1))
+
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			graph.visitVertex(u, graph.vertexLabels(queue), fmt.Sprintf("%s出队，属于第%d个连通分量", graph.vertexLabel(u), len(result.Components)+1))
+			component = append(component, graph.Vertices[u])
+			for arc := range graph.neighbors(u) {
+				if visited[arc.To] {
+					continue
+				}
+				visited[arc.To] = true
+				queue = append(queue, arc.To)
+				graph.discover(arc.To, graph.vertexLabels(queue), fmt.Sprintf("%s首次被发现，入队", graph.vertexLabel(arc.To)))
+			}
+		}
+		result.Components = append(result.Components, component)
+	}
+	return result, nil
+}
+
+// Kosaraju 算法求强连通分量
+func (graph *Graph[T]) stronglyConnected() *GraphResult[T] {
+	n := len(graph.Vertices)
+	visited := make([]bool, n)
+	finished := make([]int, 0, n)
+	path := make([]int, 0)
+
+	var forward func(u int)
+	forward = func(u int) {
+		visited[u] = true
+		path = append(path, u)
+		graph.record(TraceStep{
+			Action:   StepRecurse,
+			Index:    intRef(u),
+			Node:     graph.vertexLabel(u),
+			Frontier: graph.vertexLabels(path),
+			Detail:   fmt.Sprintf("第一遍：进入dfs(%s)", graph.vertexLabel(u)),
+		})
+		for arc := range graph.neighbors(u) {
+			if !visited[arc.To] {
+				forward(arc.To)
+			}
+		}
+		path = path[:len(path)-1]
+		finished = append(finished, u)
+		graph.record(TraceStep{
+			Action:   StepReturn,
+			Index:    intRef(u),
+			Node:     graph.vertexLabel(u),
+			Frontier: graph.vertexLabels(path),
+			Detail:   fmt.Sprintf("第一遍：%s完成，压入完成栈（第%d个）", graph.vertexLabel(u), len(finished)),
+		})
+	}
+	for u := range n {
+		if !visited[u] {
+			forward(u)
+		}
+	}
+
+	// 反向图：只用于第二遍，直接由出边构造
+	reverse := make([][]GraphArc, n)
+	for u := range n {
+		for arc := range graph.arcs(u) {
+			reverse[arc.To] = append(reverse[arc.To], GraphArc{To: u, Weight: arc.Weight})
+		}
+	}
+	graph.explain(graph.vertexLabels(finished), "第一遍结束，把所有边反向，按完成时间从晚到早在反向图上做第二遍DFS")
+
+	clear(visited)
+	result := &GraphResult[T]{Components: make([][]T, 0)}
+	var backward func(u int, component *[]T)
+	backward = func(u int, component *[]T) {
+		visited[u] = true
+		*component = append(*component, graph.Vertices[u])
+		graph.visitVertex(u, nil, fmt.Sprintf("第二遍：%s属于第%d个强连通分量", graph.vertexLabel(u), len(result.Components)+1))
+		for _, arc := range reverse[u] {
+			graph.record(TraceStep{
+				Action: StepEdge,
+				Value:  arc.Weight,
+				Node:   graph.vertexLabel(u),
+				Target: graph.vertexLabel(arc.To),
+				Detail: fmt.Sprintf("检查反向边%s→%s", graph.vertexLabel(u), graph.vertexLabel(arc.To)),
+			})
+			if !visited[arc.To] {
+				backward(arc.To, component)
+			}
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		u := finished[i]
+		if visited[u] {
+			continue
+		}
+		component := make([]T, 0)
+		backward(u, &component)
+		result.Components = append(result.Components, component)
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package ds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 扩容策略名称
+const (
+	GrowthDoubling       = "doubling"
+	GrowthOneAndHalf     = "one_and_half"
+	GrowthFixedIncrement = "fixed_increment"
+	GrowthGoAppend       = "go_append"
+	GrowthNone           = "none"
+)
+
+// 默认扩容策略与固定增量
+const (
+	DefaultGrowthStrategy  = GrowthDoubling
+	DefaultGrowthIncrement = 10
+)
+
+// GrowthStrategy 扩容策略接口，根据当前容量和所需最小容量计算新容量
+type GrowthStrategy interface {
+	NewCapacity(oldCap, minCap int) int
+}
+
+// 倍增策略：容量翻倍
+type doublingGrowth struct{}
+
+func (doublingGrowth) NewCapacity(oldCap, minCap int) int {
+	newCap := max(oldCap, 1)
+	for newCap < minCap {
+		newCap *= 2
+	}
+	return newCap
+}
+
+// 1.5倍策略：容量增长为原来的1.5倍（至少增加1）
+type oneAndHalfGrowth struct{}
+
+func (oneAndHalfGrowth) NewCapacity(oldCap, minCap int) int {
+	newCap := max(oldCap, 1)
+	for newCap < minCap {
+		newCap += max(newCap/2, 1)
+	}
+	return newCap
+}
+
+// 固定增量策略：每次增加固定数量的槽位
+type fixedIncrementGrowth struct {
+	increment int
+}
+
+func (g fixedIncrementGrowth) NewCapacity(oldCap, minCap int) int {
+	newCap := oldCap
+	for newCap < minCap {
+		newCap += g.increment
+	}
+	return newCap
+}
+
+// Go append 策略：直接借助运行时的 append 计算新容量，结果与切片扩容（含内存规格取整）一致
+type goAppendGrowth struct{}
+
+func (goAppendGrowth) NewCapacity(oldCap, minCap int) int {
+	return cap(append(make([]int, oldCap), make([]int, minCap-oldCap)...))
+}
+
+// 不扩容策略：保持静态数组语义，容量用尽即拒绝写入
+type noGrowth struct{}
+
+func (noGrowth) NewCapacity(oldCap, minCap int) int {
+	return oldCap
+}
+
+// 已注册的扩容策略，新增策略只需在此登记构造函数
+var growthStrategies = map[string]func(increment int) GrowthStrategy{
+	GrowthDoubling:       func(int) GrowthStrategy { return doublingGrowth{} },
+	GrowthOneAndHalf:     func(int) GrowthStrategy { return oneAndHalfGrowth{} },
+	GrowthFixedIncrement: func(increment int) GrowthStrategy { return fixedIncrementGrowth{increment: increment} },
+	GrowthGoAppend:       func(int) GrowthStrategy { return goAppendGrowth{} },
+	GrowthNone:           func(int) GrowthStrategy { return noGrowth{} },
+}
+
+// NewGrowthStrategy 根据名称创建扩容策略
+func NewGrowthStrategy(name string, increment int) (GrowthStrategy, error) {
+	factory, ok := growthStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("扩容策略必须是%s之一", strings.Join(growthStrategyNames(), "、"))
+	}
+	if increment <= 0 {
+		increment = DefaultGrowthIncrement
+	}
+	return factory(increment), nil
+}
+
+// 按字母顺序返回所有已注册的扩容策略名称
+func growthStrategyNames() []string {
+	names := make([]string, 0, len(growthStrategies))
+	for name := range growthStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
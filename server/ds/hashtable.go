@@ -0,0 +1,762 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+)
+
+// 冲突解决策略
+const (
+	HashChaining  = "chaining"  // 拉链法：每个桶是一条单链表
+	HashLinear    = "linear"    // 线性探测：h, h+1, h+2, ...
+	HashQuadratic = "quadratic" // 平方探测：h, h+1², h+2², ...
+	HashDouble    = "double"    // 双重哈希：h, h+h2, h+2·h2, ...，h2 = 1 + code mod (m-1)
+)
+
+// 哈希函数
+const (
+	HashDivision       = "division"       // 除法：code mod m
+	HashMultiplication = "multiplication" // 乘法：floor(m · frac(code · A))，A = (√5-1)/2
+	HashFNV            = "fnv1a"          // FNV-1a：对键的文本形式计算32位 FNV-1a 后取模
+)
+
+// 开放寻址的槽位状态
+const (
+	SlotEmpty     = "empty"
+	SlotOccupied  = "occupied"
+	SlotTombstone = "tombstone" // 删除后留下的标记：查找时跳过继续探查，插入时可以复用
+)
+
+// 默认参数
+const (
+	DefaultHashCapacity        = 7
+	DefaultChainingLoadFactor  = 1.0
+	DefaultOpenAddressingLoad  = 0.5 // 平方探测在素数容量下只有装载因子不超过0.5时保证找到空槽
+	maxChainingLoadFactor      = 8.0
+	multiplicativeHashConstant = 2654435769 // floor(A · 2^32)
+)
+
+// HashConfig 创建哈希表的参数，零值字段使用默认值
+type HashConfig struct {
+	Strategy      string  `json:"strategy"`
+	HashFunction  string  `json:"hashFunction"`
+	Capacity      int     `json:"capacity"`      // 向上取为素数
+	MaxLoadFactor float64 `json:"maxLoadFactor"` // 插入新键后装载因子超过该值时先扩容再插入
+}
+
+// HashTableState 哈希表中与键值类型无关的状态
+type HashTableState struct {
+	Strategy      string  `json:"strategy"`
+	HashFunction  string  `json:"hashFunction"`
+	Capacity      int     `json:"capacity"`
+	Size          int     `json:"size"`
+	Tombstones    int     `json:"tombstones"` // 仅开放寻址：墓碑数量
+	MaxLoadFactor float64 `json:"maxLoadFactor"`
+
+	Recorder `json:"-"`
+}
+
+// HashEntry 键值对
+type HashEntry[K any, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// HashSlot 开放寻址的一个槽位
+type HashSlot[K any, V any] struct {
+	State string
+	Entry HashEntry[K, V]
+}
+
+// SavedHashEntry 持久化的键值对，按桶（槽位）下标及链中顺序排列；墓碑只保存位置
+type SavedHashEntry[K any, V any] struct {
+	Bucket    int  `json:"bucket"`
+	Key       K    `json:"key"`
+	Value     V    `json:"value"`
+	Tombstone bool `json:"tombstone,omitempty"`
+}
+
+// HashTable 哈希表，K 为键的元素类型，V 为值类型；拉链法的桶沿用链表节点，开放寻址直接存放在槽位数组中
+type HashTable[K any, V any] struct {
+	HashTableState
+	Buckets []*Node[HashEntry[K, V]] `json:"-"` // 仅拉链法：各桶链表的头节点
+	Slots   []HashSlot[K, V]         `json:"-"` // 仅开放寻址
+
+	probes []int // 最近一次操作中被操作的键依次探查的桶（槽位）下标
+	kind   *ElementType[K]
+}
+
+// NewHashTable 按配置创建空哈希表，配置无效时返回错误
+func NewHashTable[K any, V any](config HashConfig, kind *ElementType[K]) (*HashTable[K, V], error) {
+	if config.Strategy == "" {
+		config.Strategy = HashChaining
+	}
+	if config.HashFunction == "" {
+		config.HashFunction = HashDivision
+	}
+	switch config.Strategy {
+	case HashChaining, HashLinear, HashQuadratic, HashDouble:
+	default:
+		return nil, errors.New("冲突解决策略必须是chaining、linear、quadratic或double")
+	}
+	switch config.HashFunction {
+	case HashDivision, HashMultiplication, HashFNV:
+	default:
+		return nil, errors.New("哈希函数必须是division、multiplication或fnv1a")
+	}
+
+	if config.Capacity < 0 {
+		return nil, errors.New("容量不能为负数")
+	}
+	if config.Capacity == 0 {
+		config.Capacity = DefaultHashCapacity
+	}
+
+	if config.MaxLoadFactor == 0 {
+		config.MaxLoadFactor = DefaultOpenAddressingLoad
+		if config.Strategy == HashChaining {
+			config.MaxLoadFactor = DefaultChainingLoadFactor
+		}
+	}
+	// 开放寻址的装载因子不能达到1，否则槽位用尽后无法插入
+	if config.Strategy == HashChaining && (config.MaxLoadFactor <= 0 || config.MaxLoadFactor > maxChainingLoadFactor) {
+		return nil, fmt.Errorf("拉链法的最大装载因子必须在0到%g之间", maxChainingLoadFactor)
+	}
+	if config.Strategy != HashChaining && (config.MaxLoadFactor <= 0 || config.MaxLoadFactor >= 1) {
+		return nil, errors.New("开放寻址的最大装载因子必须在0到1之间")
+	}
+
+	table := &HashTable[K, V]{kind: kind}
+	table.Strategy = config.Strategy
+	table.HashFunction = config.HashFunction
+	table.MaxLoadFactor = config.MaxLoadFactor
+	table.allocate(nextPrime(config.Capacity))
+	table.Begin()
+	return table, nil
+}
+
+// RestoreHashTable 按保存的位置和链中顺序直接放回各键值对和墓碑，不记录追踪
+func RestoreHashTable[K any, V any](config HashConfig, entries []SavedHashEntry[K, V], kind *ElementType[K]) (*HashTable[K, V], error) {
+	table, err := NewHashTable[K, V](config, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	tails := make([]*Node[HashEntry[K, V]], table.Capacity)
+	for _, saved := range entries {
+		if saved.Bucket < 0 || saved.Bucket >= table.Capacity {
+			return nil, fmt.Errorf("桶下标%d超出容量%d", saved.Bucket, table.Capacity)
+		}
+		entry := HashEntry[K, V]{Key: saved.Key, Value: saved.Value}
+		switch {
+		case table.IsChaining():
+			node := &Node[HashEntry[K, V]]{Value: entry}
+			if tails[saved.Bucket] == nil {
+				table.Buckets[saved.Bucket] = node
+			} else {
+				tails[saved.Bucket].Next = node
+			}
+			tails[saved.Bucket] = node
+			table.Size++
+		case saved.Tombstone:
+			table.Slots[saved.Bucket] = HashSlot[K, V]{State: SlotTombstone}
+			table.Tombstones++
+		default:
+			table.Slots[saved.Bucket] = HashSlot[K, V]{State: SlotOccupied, Entry: entry}
+			table.Size++
+		}
+	}
+
+	table.Begin()
+	return table, nil
+}
+
+// Kind 键的元素类型
+func (table *HashTable[K, V]) Kind() *ElementType[K] {
+	return table.kind
+}
+
+// IsChaining 是否使用拉链法
+func (table *HashTableState) IsChaining() bool {
+	return table.Strategy == HashChaining
+}
+
+// LoadFactor 键值对数量与容量之比
+func (table *HashTableState) LoadFactor() float64 {
+	return float64(table.Size) / float64(table.Capacity)
+}
+
+// Begin 开始一次新操作：清空计数、追踪和探查序列
+func (table *HashTable[K, V]) Begin() {
+	table.Recorder.Begin()
+	table.probes = make([]int, 0)
+}
+
+// Probes 最近一次操作中被操作的键依次探查的桶（槽位）下标
+func (table *HashTable[K, V]) Probes() []int {
+	return table.probes
+}
+
+// Saved 按桶（槽位）下标和链中顺序返回所有键值对和墓碑，供持久化后用 RestoreHashTable 重建
+func (table *HashTable[K, V]) Saved() []SavedHashEntry[K, V] {
+	entries := make([]SavedHashEntry[K, V], 0, table.Size+table.Tombstones)
+	if table.IsChaining() {
+		for bucket, node := range table.Buckets {
+			for ; node != nil; node = node.Next {
+				entries = append(entries, SavedHashEntry[K, V]{Bucket: bucket, Key: node.Value.Key, Value: node.Value.Value})
+			}
+		}
+		return entries
+	}
+
+	for index, slot := range table.Slots {
+		switch slot.State {
+		case SlotOccupied:
+			entries = append(entries, SavedHashEntry[K, V]{Bucket: index, Key: slot.Entry.Key, Value: slot.Entry.Value})
+		case SlotTombstone:
+			entries = append(entries, SavedHashEntry[K, V]{Bucket: index, Tombstone: true})
+		}
+	}
+	return entries
+}
+
+// Config 当前的策略、哈希函数、容量和最大装载因子
+func (table *HashTableState) Config() HashConfig {
+	return HashConfig{
+		Strategy:      table.Strategy,
+		HashFunction:  table.HashFunction,
+		Capacity:      table.Capacity,
+		MaxLoadFactor: table.MaxLoadFactor,
+	}
+}
+
+// 分配 capacity 个空桶（槽位）
+func (table *HashTable[K, V]) allocate(capacity int) {
+	table.Capacity = capacity
+	table.Size = 0
+	table.Tombstones = 0
+	table.Buckets = nil
+	table.Slots = nil
+	if table.IsChaining() {
+		table.Buckets = make([]*Node[HashEntry[K, V]], capacity)
+	} else {
+		table.Slots = make([]HashSlot[K, V], capacity)
+		for i := range table.Slots {
+			table.Slots[i].State = SlotEmpty
+		}
+	}
+}
+
+// 不小于 n 的最小素数（至少为3，使双重哈希的步长 1 + code mod (m-1) 有意义）
+func nextPrime(n int) int {
+	n = max(n, 3)
+	for !isPrime(n) {
+		n++
+	}
+	return n
+}
+
+func isPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// 键的文本形式，object 类型只取 key；浮点数加0把-0归一为0，使相等的键哈希相同
+func keyText(key any) string {
+	switch key := key.(type) {
+	case Record:
+		return key.Key()
+	case float64:
+		return fmt.Sprint(key + 0)
+	}
+	return fmt.Sprint(key)
+}
+
+// 键的32位 FNV-1a 值
+func fnvHash(text string) uint64 {
+	h := fnv.New32a()
+	h.Write([]byte(text))
+	return uint64(h.Sum32())
+}
+
+// 键的哈希码：整数取绝对值，其他类型取文本形式的 FNV-1a 值，便于手算整数键的除法和乘法哈希
+func (table *HashTable[K, V]) keyCode(key K) uint64 {
+	if i, ok := any(key).(int); ok {
+		if i < 0 {
+			return uint64(-i)
+		}
+		return uint64(i)
+	}
+	return fnvHash(keyText(key))
+}
+
+// 按哈希函数计算键的初始桶（槽位）下标，同时给出计算过程
+func (table *HashTable[K, V]) hash(key K) (int, string) {
+	m := uint64(table.Capacity)
+	code := table.keyCode(key)
+	switch table.HashFunction {
+	case HashMultiplication:
+		index := ((code * multiplicativeHashConstant) & 0xffffffff) * m >> 32
+		return int(index), fmt.Sprintf("h(%v) = floor(%d × frac(%d × A)) = %d", key, m, code, index)
+	case HashFNV:
+		code = fnvHash(keyText(key))
+		index := code % m
+		return int(index), fmt.Sprintf("h(%v) = fnv1a(%q) mod %d = %d mod %d = %d", key, keyText(key), m, code, m, index)
+	default:
+		index := code % m
+		return int(index), fmt.Sprintf("h(%v) = %d mod %d = %d", key, code, m, index)
+	}
+}
+
+// Home 键的初始桶（槽位）下标，不记录追踪；开放寻址中键所在槽位与它不同说明插入时发生了冲突
+func (table *HashTable[K, V]) Home(key K) int {
+	index, _ := table.hash(key)
+	return index
+}
+
+// 计算键的初始桶（槽位）下标，并记录计算过程
+func (table *HashTable[K, V]) home(key K) int {
+	index, detail := table.hash(key)
+	table.record(TraceStep{
+		Action: StepHash,
+		Index:  intRef(index),
+		Value:  key,
+		Detail: detail,
+	})
+	return index
+}
+
+// 双重哈希的步长，m 为素数时与 m 互素，探查序列能覆盖所有槽位
+func (table *HashTable[K, V]) step(key K) int {
+	m := uint64(table.Capacity)
+	code := table.keyCode(key)
+	step := 1 + code%(m-1)
+	table.record(TraceStep{
+		Action: StepHash,
+		Value:  key,
+		Detail: fmt.Sprintf("h2(%v) = 1 + %d mod %d = %d", key, code, m-1, step),
+	})
+	return int(step)
+}
+
+// 第 i 次探查的槽位下标
+func (table *HashTable[K, V]) probeIndex(home, step, i int) int {
+	m := table.Capacity
+	switch table.Strategy {
+	case HashQuadratic:
+		return (home + i*i%m) % m
+	case HashDouble:
+		return (home + i*step%m) % m
+	default:
+		return (home + i) % m
+	}
+}
+
+// 探查序列的文字说明
+func (table *HashTable[K, V]) probeFormula(home, step, i int) string {
+	switch table.Strategy {
+	case HashQuadratic:
+		return fmt.Sprintf("(%d + %d²) mod %d", home, i, table.Capacity)
+	case HashDouble:
+		return fmt.Sprintf("(%d + %d×%d) mod %d", home, i, step, table.Capacity)
+	default:
+		return fmt.Sprintf("(%d + %d) mod %d", home, i, table.Capacity)
+	}
+}
+
+// 比较两个键
+func (table *HashTable[K, V]) equal(a, b K) bool {
+	table.cost.Comparisons++
+	return table.kind.Equal(a, b)
+}
+
+// 开放寻址中沿探查序列查找键：返回键所在的槽位（不存在时为-1），以及可以插入的第一个墓碑或空槽（没有时为-1）
+func (table *HashTable[K, V]) locate(key K) (int, int) {
+	table.probes = table.probes[:0]
+	home := table.home(key)
+	step := 0
+	if table.Strategy == HashDouble {
+		step = table.step(key)
+	}
+
+	free := -1
+	for i := 0; i < table.Capacity; i++ {
+		index := table.probeIndex(home, step, i)
+		table.probes = append(table.probes, index)
+		slot := &table.Slots[index]
+		probe := TraceStep{Action: StepProbe, Index: intRef(index), Value: slot.Entry.Key}
+		if i > 0 {
+			probe.Detail = fmt.Sprintf("第%d次探查槽位%s = %d：", i, table.probeFormula(home, step, i), index)
+		} else {
+			probe.Detail = fmt.Sprintf("探查初始槽位%d：", index)
+		}
+
+		switch slot.State {
+		case SlotEmpty:
+			probe.Value = nil
+			probe.Detail += "空槽，键不存在"
+			table.record(probe)
+			if free < 0 {
+				free = index
+			}
+			return -1, free
+		case SlotTombstone:
+			probe.Value = nil
+			probe.Detail += "墓碑，跳过继续探查"
+			table.record(probe)
+			if free < 0 {
+				free = index
+			}
+		default:
+			if table.equal(key, slot.Entry.Key) {
+				probe.Detail += fmt.Sprintf("键%v匹配", slot.Entry.Key)
+				table.record(probe)
+				return index, free
+			}
+			probe.Detail += fmt.Sprintf("键%v不匹配（冲突），继续探查", slot.Entry.Key)
+			table.record(probe)
+		}
+	}
+	return -1, free
+}
+
+// 拉链法中桶内第 i 个节点的标识
+func chainLabel(bucket, i int) string {
+	return fmt.Sprintf("bucket[%d].node[%d]", bucket, i)
+}
+
+// 拉链法中沿桶的链表查找键：返回桶下标、键所在节点及其前驱和位置，未找到时节点为 nil、前驱为链尾
+func (table *HashTable[K, V]) chain(key K) (int, *Node[HashEntry[K, V]], *Node[HashEntry[K, V]], int) {
+	table.probes = table.probes[:0]
+	bucket := table.home(key)
+	table.probes = append(table.probes, bucket)
+
+	var prev *Node[HashEntry[K, V]]
+	i := 0
+	for node := table.Buckets[bucket]; node != nil; node = node.Next {
+		table.record(TraceStep{
+			Action: StepCompare,
+			Index:  intRef(bucket),
+			Value:  node.Value.Key,
+			Node:   chainLabel(bucket, i),
+			Detail: fmt.Sprintf("比较%v与桶%d第%d个节点的键%v", key, bucket, i, node.Value.Key),
+		})
+		if table.equal(key, node.Value.Key) {
+			return bucket, node, prev, i
+		}
+		prev = node
+		i++
+	}
+	return bucket, nil, prev, i
+}
+
+// 写入开放寻址的槽位
+func (table *HashTable[K, V]) writeSlot(index int, entry HashEntry[K, V], detail string) {
+	table.Slots[index] = HashSlot[K, V]{State: SlotOccupied, Entry: entry}
+	table.cost.Writes++
+	table.record(TraceStep{
+		Action: StepWrite,
+		Index:  intRef(index),
+		Value:  entry.Key,
+		Detail: detail,
+	})
+}
+
+// 在桶的链尾（tail 为 nil 时作为头节点）挂上新节点
+func (table *HashTable[K, V]) appendNode(bucket int, tail *Node[HashEntry[K, V]], position int, entry HashEntry[K, V]) {
+	node := &Node[HashEntry[K, V]]{Value: entry}
+	table.cost.Writes++
+	table.record(TraceStep{
+		Action: StepCreate,
+		Index:  intRef(bucket),
+		Value:  entry.Key,
+		Node:   "new",
+		Detail: fmt.Sprintf("为键%v创建新节点", entry.Key),
+	})
+	if tail == nil {
+		table.Buckets[bucket] = node
+		table.record(TraceStep{
+			Action: StepSetHead,
+			Index:  intRef(bucket),
+			Target: chainLabel(bucket, position),
+			Detail: fmt.Sprintf("桶%d为空，新节点成为链表头", bucket),
+		})
+		return
+	}
+	tail.Next = node
+	table.record(TraceStep{
+		Action: StepSetNext,
+		Index:  intRef(bucket),
+		Node:   chainLabel(bucket, position-1),
+		Target: chainLabel(bucket, position),
+		Detail: fmt.Sprintf("%s.Next = 新节点，挂在桶%d的链尾", chainLabel(bucket, position-1), bucket),
+	})
+}
+
+// Put 插入或更新键值对，键已存在时只更新值并返回 true；插入新键会使装载因子超过上限时先扩容再插入
+func (table *HashTable[K, V]) Put(key K, value V) bool {
+	entry := HashEntry[K, V]{Key: key, Value: value}
+
+	if table.IsChaining() {
+		bucket, node, tail, position := table.chain(key)
+		if node != nil {
+			node.Value.Value = value
+			table.cost.Writes++
+			table.record(TraceStep{
+				Action: StepWrite,
+				Index:  intRef(bucket),
+				Value:  key,
+				Node:   chainLabel(bucket, position),
+				Detail: fmt.Sprintf("键%v已存在，更新它的值", key),
+			})
+			return true
+		}
+		if table.overloaded(table.Size + 1) {
+			table.rehash(table.grownCapacity())
+			bucket, _, tail, position = table.chain(key)
+		}
+		table.appendNode(bucket, tail, position, entry)
+		table.Size++
+		return false
+	}
+
+	index, free := table.locate(key)
+	if index >= 0 {
+		table.Slots[index].Entry.Value = value
+		table.cost.Writes++
+		table.record(TraceStep{
+			Action: StepWrite,
+			Index:  intRef(index),
+			Value:  key,
+			Detail: fmt.Sprintf("键%v已存在，更新槽位%d的值", key, index),
+		})
+		return true
+	}
+
+	// 墓碑同样占用槽位、拉长探查序列：只有墓碑导致超限时按原容量重建，清除墓碑
+	switch {
+	case table.overloaded(table.Size + 1):
+		table.rehash(table.grownCapacity())
+		_, free = table.locate(key)
+	case table.overloaded(table.Size + table.Tombstones + 1):
+		table.rehash(table.Capacity)
+		_, free = table.locate(key)
+	}
+	// 平方探测不一定覆盖所有槽位，找不到空槽时扩容后重试
+	for free < 0 {
+		table.explain(fmt.Sprintf("探查了%d次仍未找到可用槽位，扩容后重试", table.Capacity))
+		table.rehash(table.grownCapacity())
+		_, free = table.locate(key)
+	}
+
+	if table.Slots[free].State == SlotTombstone {
+		table.Tombstones--
+		table.writeSlot(free, entry, fmt.Sprintf("复用槽位%d的墓碑存放键%v", free, key))
+	} else {
+		table.writeSlot(free, entry, fmt.Sprintf("在空槽%d存放键%v", free, key))
+	}
+	table.Size++
+	return false
+}
+
+// Get 查找键对应的值，键不存在时返回 ErrNotFound
+func (table *HashTable[K, V]) Get(key K) (V, error) {
+	if table.IsChaining() {
+		if _, node, _, _ := table.chain(key); node != nil {
+			return node.Value.Value, nil
+		}
+	} else if index, _ := table.locate(key); index >= 0 {
+		return table.Slots[index].Entry.Value, nil
+	}
+	var zero V
+	return zero, ErrNotFound
+}
+
+// Delete 删除键并返回它的值：拉链法把节点从链中摘除，开放寻址在槽位留下墓碑以免截断其他键的探查序列
+func (table *HashTable[K, V]) Delete(key K) (V, error) {
+	var zero V
+	if table.IsChaining() {
+		bucket, node, prev, position := table.chain(key)
+		if node == nil {
+			return zero, ErrNotFound
+		}
+		if prev == nil {
+			table.Buckets[bucket] = node.Next
+			table.record(TraceStep{
+				Action: StepSetHead,
+				Index:  intRef(bucket),
+				Target: chainLabelOrNil(bucket, position+1, node.Next),
+				Detail: fmt.Sprintf("桶%d的链表头改为下一个节点", bucket),
+			})
+		} else {
+			prev.Next = node.Next
+			table.record(TraceStep{
+				Action: StepSetNext,
+				Index:  intRef(bucket),
+				Node:   chainLabel(bucket, position-1),
+				Target: chainLabelOrNil(bucket, position+1, node.Next),
+				Detail: fmt.Sprintf("%s.Next跳过被删节点", chainLabel(bucket, position-1)),
+			})
+		}
+		table.record(TraceStep{
+			Action: StepFree,
+			Index:  intRef(bucket),
+			Value:  key,
+			Node:   chainLabel(bucket, position),
+			Detail: fmt.Sprintf("键%v所在节点脱离桶%d", key, bucket),
+		})
+		table.Size--
+		return node.Value.Value, nil
+	}
+
+	index, _ := table.locate(key)
+	if index < 0 {
+		return zero, ErrNotFound
+	}
+	value := table.Slots[index].Entry.Value
+	table.Slots[index] = HashSlot[K, V]{State: SlotTombstone}
+	table.cost.Writes++
+	table.record(TraceStep{
+		Action: StepTombstone,
+		Index:  intRef(index),
+		Value:  key,
+		Detail: fmt.Sprintf("槽位%d标记为墓碑，经过它的探查序列不会在此中断", index),
+	})
+	table.Size--
+	table.Tombstones++
+	return value, nil
+}
+
+// 链中节点的标识，节点为空时为 nil
+func chainLabelOrNil[T any](bucket, i int, node *Node[T]) string {
+	if node == nil {
+		return "nil"
+	}
+	return chainLabel(bucket, i)
+}
+
+// Rehash 按新容量（向上取为素数，0 表示保持原容量）重建哈希表，所有键按原顺序重新计算位置，墓碑被清除
+func (table *HashTable[K, V]) Rehash(capacity int) error {
+	if capacity < 0 {
+		return errors.New("容量不能为负数")
+	}
+	if capacity == 0 {
+		capacity = table.Capacity
+	}
+	capacity = nextPrime(capacity)
+	if float64(table.Size) > table.MaxLoadFactor*float64(capacity) {
+		return fmt.Errorf("容量%d放不下%d个键（最大装载因子%g）", capacity, table.Size, table.MaxLoadFactor)
+	}
+	table.rehash(capacity)
+	return nil
+}
+
+// 键数为 size 时是否超过最大装载因子
+func (table *HashTable[K, V]) overloaded(size int) bool {
+	return float64(size) > table.MaxLoadFactor*float64(table.Capacity)
+}
+
+// 扩容后的容量：容量逐次翻倍并取素数，直到再插入一个键也不超过最大装载因子
+func (table *HashTable[K, V]) grownCapacity() int {
+	capacity := nextPrime(2 * table.Capacity)
+	for float64(table.Size+1) > table.MaxLoadFactor*float64(capacity) {
+		capacity = nextPrime(2 * capacity)
+	}
+	return capacity
+}
+
+// 说明进入的情况分支
+func (table *HashTable[K, V]) explain(detail string) {
+	table.record(TraceStep{
+		Action: StepCase,
+		Detail: detail,
+	})
+}
+
+// 分配 capacity 个空桶后，按原桶（槽位）顺序把所有键值对重新放入
+func (table *HashTable[K, V]) rehash(capacity int) {
+	entries := make([]HashEntry[K, V], 0, table.Size)
+	for _, saved := range table.Saved() {
+		if !saved.Tombstone {
+			entries = append(entries, HashEntry[K, V]{Key: saved.Key, Value: saved.Value})
+		}
+	}
+
+	detail := fmt.Sprintf("装载因子%.2f，重新分配%d个桶", table.LoadFactor(), capacity)
+	if table.Tombstones > 0 {
+		detail += fmt.Sprintf("，清除%d个墓碑", table.Tombstones)
+	}
+	oldCapacity := table.Capacity
+	table.allocate(capacity)
+	table.cost.Reallocations++
+	table.record(TraceStep{
+		Action: StepRehash,
+		From:   intRef(oldCapacity),
+		To:     intRef(capacity),
+		Detail: detail,
+	})
+
+	for _, entry := range entries {
+		table.reinsert(entry)
+	}
+}
+
+// 重建时放入键值对：键互不相同，无需比较，拉链法挂在链尾，开放寻址放入探查到的第一个空槽
+func (table *HashTable[K, V]) reinsert(entry HashEntry[K, V]) {
+	table.cost.Copies++
+	home := table.home(entry.Key)
+
+	if table.IsChaining() {
+		var tail *Node[HashEntry[K, V]]
+		position := 0
+		for node := table.Buckets[home]; node != nil; node = node.Next {
+			tail = node
+			position++
+		}
+		node := &Node[HashEntry[K, V]]{Value: entry}
+		if tail == nil {
+			table.Buckets[home] = node
+		} else {
+			tail.Next = node
+		}
+		table.record(TraceStep{
+			Action: StepCopy,
+			Index:  intRef(home),
+			Value:  entry.Key,
+			Node:   chainLabel(home, position),
+			Detail: fmt.Sprintf("键%v移入桶%d", entry.Key, home),
+		})
+		table.Size++
+		return
+	}
+
+	step := 0
+	if table.Strategy == HashDouble {
+		step = table.step(entry.Key)
+	}
+	// 新容量下装载因子低于上限，探查一圈内总能找到空槽；平方探测找不到时说明上限设置不当，继续扩容
+	for i := 0; i < table.Capacity; i++ {
+		index := table.probeIndex(home, step, i)
+		if table.Slots[index].State == SlotEmpty {
+			table.Slots[index] = HashSlot[K, V]{State: SlotOccupied, Entry: entry}
+			table.record(TraceStep{
+				Action: StepCopy,
+				Index:  intRef(index),
+				Value:  entry.Key,
+				Detail: fmt.Sprintf("键%v移入槽位%d（探查%d次）", entry.Key, index, i+1),
+			})
+			table.Size++
+			return
+		}
+	}
+	table.rehash(table.grownCapacity())
+	table.reinsert(entry)
+}
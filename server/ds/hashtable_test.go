@@ -0,0 +1,42 @@
+package ds
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHashTableNegativeZeroKey(t *testing.T) {
+	negativeZero := math.Copysign(0, -1)
+
+	for _, strategy := range []string{HashChaining, HashLinear, HashQuadratic, HashDouble} {
+		for _, function := range []string{HashDivision, HashMultiplication, HashFNV} {
+			t.Run(strategy+"/"+function, func(t *testing.T) {
+				table, err := NewHashTable[float64, string](HashConfig{Strategy: strategy, HashFunction: function}, Floats)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// 0 和 -0 相等，必须落在同一个桶（槽位）上
+				if table.Home(0) != table.Home(negativeZero) {
+					t.Fatalf("0 和 -0 的初始位置分别为%d和%d", table.Home(0), table.Home(negativeZero))
+				}
+				table.Put(0, "zero")
+				if updated := table.Put(negativeZero, "negative zero"); !updated {
+					t.Fatalf("插入 -0 应更新已有的键 0")
+				}
+				if table.Size != 1 {
+					t.Fatalf("Size=%d，期望1", table.Size)
+				}
+				if value, err := table.Get(0); err != nil || value != "negative zero" {
+					t.Fatalf("Get(0) = %q, %v，期望更新后的值", value, err)
+				}
+				if _, err := table.Delete(negativeZero); err != nil {
+					t.Fatalf("删除 -0 失败: %v", err)
+				}
+				if table.Size != 0 {
+					t.Fatalf("删除后 Size=%d，期望0", table.Size)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package ds
+
+import "errors"
+
+// 堆类型
+const (
+	HeapMin = "min" // 小顶堆，堆顶为最小元素
+	HeapMax = "max" // 大顶堆，堆顶为最大元素
+)
+
+// Heap 二叉堆（优先队列），按层序存放在动态数组中：索引 i 的子节点为 2i+1 和 2i+2，父节点为 (i-1)/2
+type Heap[T any] struct {
+	Type string `json:"type"`
+
+	array *Array[T]
+	// 堆序与堆排序共用比较、交换和下沉：大顶堆即升序堆排序所建的堆，小顶堆即降序堆排序所建的堆
+	order *arraySorter[T]
+}
+
+// 堆类型对应的堆排序方向，空类型默认为小顶堆
+func heapOrder(heapType string) (string, string, error) {
+	switch heapType {
+	case "", HeapMin:
+		return HeapMin, OrderDesc, nil
+	case HeapMax:
+		return HeapMax, OrderAsc, nil
+	default:
+		return "", "", errors.New("堆类型必须是min或max")
+	}
+}
+
+// 在数组上建立指定类型的堆，不检查数组是否满足堆序
+func newHeapOn[T any](heapType string, array *Array[T]) (*Heap[T], error) {
+	heapType, order, err := heapOrder(heapType)
+	if err != nil {
+		return nil, err
+	}
+	return &Heap[T]{
+		Type:  heapType,
+		array: array,
+		order: newArraySorter(array, SortOptions{Order: order}),
+	}, nil
+}
+
+// NewHeap 创建指定类型的空堆，config 为底层数组的容量和扩缩容参数
+func NewHeap[T any](heapType string, config ArrayConfig, kind *ElementType[T]) (*Heap[T], error) {
+	if _, _, err := heapOrder(heapType); err != nil {
+		return nil, err
+	}
+	array, err := NewArray(config, kind)
+	if err != nil {
+		return nil, err
+	}
+	return newHeapOn(heapType, array)
+}
+
+// RestoreHeap 按层序重建已满足堆序的堆，不保留追踪
+func RestoreHeap[T any](heapType string, config ArrayConfig, values []T, kind *ElementType[T]) (*Heap[T], error) {
+	config.Capacity = max(config.Capacity, len(values))
+	heap, err := NewHeap(heapType, config, kind)
+	if err != nil {
+		return nil, err
+	}
+	heap.array.Elements = append(heap.array.Elements, values...)
+	heap.array.Size = len(values)
+	return heap, nil
+}
+
+// Heapify 把 values 原样放入数组后，自最后一个非叶节点向前逐个下沉建堆（O(n)），建堆的比较和交换计入追踪
+func Heapify[T any](heapType string, config ArrayConfig, values []T, kind *ElementType[T]) (*Heap[T], error) {
+	heap, err := RestoreHeap(heapType, config, values, kind)
+	if err != nil {
+		return nil, err
+	}
+	heap.Begin()
+	n := heap.array.Size
+	for i := n/2 - 1; i >= 0; i-- {
+		heap.order.siftDown(i, n)
+	}
+	return heap, nil
+}
+
+// Array 底层动态数组，元素按层序排列
+func (heap *Heap[T]) Array() *Array[T] {
+	return heap.array
+}
+
+// Kind 堆的元素类型
+func (heap *Heap[T]) Kind() *ElementType[T] {
+	return heap.array.Kind()
+}
+
+// Recorder 底层数组的计数和追踪
+func (heap *Heap[T]) Recorder() *Recorder {
+	return &heap.array.Recorder
+}
+
+// Begin 开始一次新操作，清空计数和追踪
+func (heap *Heap[T]) Begin() {
+	heap.array.Begin()
+}
+
+// Len 元素个数
+func (heap *Heap[T]) Len() int {
+	return heap.array.Size
+}
+
+// Values 按层序（即数组顺序）返回所有元素
+func (heap *Heap[T]) Values() []T {
+	return heap.array.Values()
+}
+
+// Peek 读取堆顶元素
+func (heap *Heap[T]) Peek() (T, error) {
+	if heap.array.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return heap.array.read(0), nil
+}
+
+// Insert 把元素追加到数组末尾，再逐层与父节点比较并上浮
+func (heap *Heap[T]) Insert(value T) (*ResizeEvent, error) {
+	resize, err := heap.array.Append(value)
+	if err != nil {
+		return nil, err
+	}
+	heap.order.siftUp(heap.array.Size - 1)
+	return resize, nil
+}
+
+// Extract 取出堆顶：把末尾元素移到根，缩短数组后下沉新的根
+func (heap *Heap[T]) Extract() (T, *ResizeEvent, error) {
+	if heap.array.Size == 0 {
+		var zero T
+		return zero, nil, ErrEmpty
+	}
+
+	top := heap.array.read(0)
+	last := heap.array.Size - 1
+	if last > 0 {
+		heap.array.shift(last, 0)
+	}
+	heap.array.Elements = heap.array.Elements[:last]
+	heap.array.Size = last
+	heap.order.siftDown(0, last)
+
+	return top, heap.array.shrinkIfNeeded(), nil
+}
+
+// DecreaseKey 提高索引处元素的优先级后上浮：小顶堆只能减小键值，大顶堆只能增大键值，返回旧值
+func (heap *Heap[T]) DecreaseKey(index int, value T) (T, error) {
+	if index < 0 || index >= heap.array.Size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+
+	oldValue := heap.array.read(index)
+	if heap.order.before(value, oldValue) {
+		if heap.Type == HeapMin {
+			return oldValue, errors.New("小顶堆的新键值不能大于原值")
+		}
+		return oldValue, errors.New("大顶堆的新键值不能小于原值")
+	}
+	heap.array.write(index, value)
+	heap.order.siftUp(index)
+	return oldValue, nil
+}
+
+// Sort 在副本上执行堆排序，不修改堆：反复把堆顶换到末尾并下沉新的根。
+// 大顶堆得到升序，小顶堆得到降序；返回有序元素和副本上的计数与追踪
+func (heap *Heap[T]) Sort() ([]T, *Recorder) {
+	clone := heap.array.Clone()
+	clone.Begin()
+	order := newArraySorter(clone, SortOptions{Order: heap.order.order()})
+	for end := clone.Size - 1; end > 0; end-- {
+		order.swap(0, end)
+		order.siftDown(0, end)
+	}
+	return clone.Values(), &clone.Recorder
+}
@@ -0,0 +1,527 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Node 链表节点结构体
+type Node[T any] struct {
+	Value T        `json:"value"`
+	Next  *Node[T] `json:"next,omitempty"`
+	Prev  *Node[T] `json:"prev,omitempty"`
+}
+
+// 链表类型
+const (
+	ListSingle         = "single"          // 单链表
+	ListDouble         = "double"          // 双向链表
+	ListCircular       = "circular"        // 单向循环链表
+	ListDoubleCircular = "double_circular" // 双向循环链表：Head.Prev == Tail，Tail.Next == Head
+)
+
+// ListState 链表中与元素类型无关的状态
+type ListState struct {
+	Type string `json:"type"` // "single", "double", "circular", "double_circular"
+	Size int    `json:"size"`
+
+	Recorder `json:"-"`
+}
+
+// LinkedList 链表，T 为元素类型；每次指针改写都记录到执行追踪中
+type LinkedList[T any] struct {
+	ListState
+	Head *Node[T] `json:"-"`
+	Tail *Node[T] `json:"-"`
+
+	labels map[*Node[T]]string // 操作开始时各节点的标识，用于描述追踪步骤
+	kind   *ElementType[T]
+}
+
+// 链表反转方式
+const (
+	ReverseIterative = "iterative" // 迭代：prev/current/next 三个游标逐个改写 Next
+	ReverseRecursive = "recursive" // 递归：先反转后继部分，回溯时让后继指回当前节点
+)
+
+// NewLinkedList 创建指定类型的空链表，listType 为空时创建单链表
+func NewLinkedList[T any](listType string, kind *ElementType[T]) (*LinkedList[T], error) {
+	if listType == "" {
+		listType = ListSingle
+	}
+	if listType != ListSingle && listType != ListDouble && listType != ListCircular && listType != ListDoubleCircular {
+		return nil, errors.New("链表类型必须是single、double、circular或double_circular")
+	}
+
+	list := &LinkedList[T]{kind: kind}
+	list.Type = listType
+	list.Begin()
+	return list, nil
+}
+
+// RestoreLinkedList 按从头到尾的值序列直接重建链表，不记录追踪
+func RestoreLinkedList[T any](listType string, values []T, kind *ElementType[T]) *LinkedList[T] {
+	list := &LinkedList[T]{kind: kind}
+	list.Type = listType
+
+	for _, value := range values {
+		node := &Node[T]{Value: value}
+		if list.Tail == nil {
+			list.Head = node
+		} else {
+			list.Tail.Next = node
+			if list.IsDoubly() {
+				node.Prev = list.Tail
+			}
+		}
+		list.Tail = node
+		list.Size++
+	}
+	if list.IsCircular() && list.Tail != nil {
+		list.Tail.Next = list.Head
+		if list.IsDoubly() {
+			list.Head.Prev = list.Tail
+		}
+	}
+
+	list.Begin()
+	return list
+}
+
+// Kind 链表的元素类型
+func (list *LinkedList[T]) Kind() *ElementType[T] {
+	return list.kind
+}
+
+// IsDoubly 是否维护 Prev 指针
+func (list *ListState) IsDoubly() bool {
+	return list.Type == ListDouble || list.Type == ListDoubleCircular
+}
+
+// IsCircular 尾节点是否指回头节点
+func (list *ListState) IsCircular() bool {
+	return list.Type == ListCircular || list.Type == ListDoubleCircular
+}
+
+// Begin 开始一次新操作：清空追踪并按当前位置为每个节点编号
+func (list *LinkedList[T]) Begin() {
+	list.Recorder.Begin()
+	list.labels = make(map[*Node[T]]string, list.Size)
+
+	current := list.Head
+	for i := 0; i < list.Size; i++ {
+		list.labels[current] = nodeLabel(i)
+		current = current.Next
+	}
+}
+
+// 节点在本次操作中的标识
+func (list *LinkedList[T]) label(node *Node[T]) string {
+	if node == nil {
+		return "nil"
+	}
+	if label, ok := list.labels[node]; ok {
+		return label
+	}
+	return "?"
+}
+
+// 创建新节点
+func (list *LinkedList[T]) newNode(value T) *Node[T] {
+	node := &Node[T]{Value: value}
+	list.labels[node] = "new"
+	list.record(TraceStep{
+		Action: StepCreate,
+		Value:  value,
+		Node:   "new",
+		Detail: fmt.Sprintf("创建值为%v的新节点", value),
+	})
+	return node
+}
+
+// 修改节点的 Next 指针
+func (list *LinkedList[T]) setNext(node, next *Node[T]) {
+	node.Next = next
+	list.record(TraceStep{
+		Action: StepSetNext,
+		Node:   list.label(node),
+		Target: list.label(next),
+		Detail: fmt.Sprintf("%s.Next = %s", list.label(node), list.label(next)),
+	})
+}
+
+// 修改节点的 Prev 指针
+func (list *LinkedList[T]) setPrev(node, prev *Node[T]) {
+	node.Prev = prev
+	list.record(TraceStep{
+		Action: StepSetPrev,
+		Node:   list.label(node),
+		Target: list.label(prev),
+		Detail: fmt.Sprintf("%s.Prev = %s", list.label(node), list.label(prev)),
+	})
+}
+
+// 修改头指针
+func (list *LinkedList[T]) setHead(node *Node[T]) {
+	list.Head = node
+	list.record(TraceStep{
+		Action: StepSetHead,
+		Target: list.label(node),
+		Detail: fmt.Sprintf("Head = %s", list.label(node)),
+	})
+}
+
+// 修改尾指针
+func (list *LinkedList[T]) setTail(node *Node[T]) {
+	list.Tail = node
+	list.record(TraceStep{
+		Action: StepSetTail,
+		Target: list.label(node),
+		Detail: fmt.Sprintf("Tail = %s", list.label(node)),
+	})
+}
+
+// 游标访问位置 index 的节点
+func (list *LinkedList[T]) visit(node *Node[T], index int) {
+	list.record(TraceStep{
+		Action: StepVisit,
+		Index:  intRef(index),
+		Value:  node.Value,
+		Node:   list.label(node),
+		Detail: fmt.Sprintf("访问位置%d的节点，值为%v", index, node.Value),
+	})
+}
+
+// 从头节点出发移动到位置 index 的节点；尾节点直接经 Tail 指针访问
+func (list *LinkedList[T]) nodeAt(index int) *Node[T] {
+	if index > 0 && index == list.Size-1 {
+		list.visit(list.Tail, index)
+		return list.Tail
+	}
+
+	current := list.Head
+	list.visit(current, 0)
+	for i := 1; i <= index; i++ {
+		current = current.Next
+		list.visit(current, i)
+	}
+	return current
+}
+
+// InsertAt 在位置 index 插入新节点，index 可以等于 Size，即追加
+func (list *LinkedList[T]) InsertAt(index int, value T) error {
+	if index < 0 || index > list.Size {
+		return ErrIndexOutOfRange
+	}
+
+	newNode := list.newNode(value)
+
+	if index == 0 {
+		// 在头部插入
+		if list.Head == nil {
+			list.setHead(newNode)
+			list.setTail(newNode)
+			if list.IsCircular() {
+				list.setNext(newNode, newNode)
+				if list.IsDoubly() {
+					list.setPrev(newNode, newNode)
+				}
+			}
+		} else {
+			list.setNext(newNode, list.Head)
+			if list.IsDoubly() {
+				list.setPrev(list.Head, newNode)
+			}
+			list.setHead(newNode)
+			if list.IsCircular() {
+				list.setNext(list.Tail, newNode)
+				if list.IsDoubly() {
+					list.setPrev(newNode, list.Tail)
+				}
+			}
+		}
+	} else {
+		// 找到前驱节点，先让新节点指向后继，再让前驱指向新节点；
+		// 循环链表在尾部插入时后继即为头节点，双向循环链表会同时更新 Head.Prev
+		prev := list.nodeAt(index - 1)
+		next := prev.Next
+
+		list.setNext(newNode, next)
+		if list.IsDoubly() {
+			list.setPrev(newNode, prev)
+			if next != nil {
+				list.setPrev(next, newNode)
+			}
+		}
+		list.setNext(prev, newNode)
+
+		if prev == list.Tail {
+			list.setTail(newNode)
+		}
+	}
+
+	list.Size++
+	return nil
+}
+
+// Prepend 在头部插入新节点
+func (list *LinkedList[T]) Prepend(value T) error {
+	return list.InsertAt(0, value)
+}
+
+// Append 在尾部追加新节点
+func (list *LinkedList[T]) Append(value T) error {
+	return list.InsertAt(list.Size, value)
+}
+
+// RemoveAt 删除位置 index 的节点，返回被删除节点的值
+func (list *LinkedList[T]) RemoveAt(index int) (T, error) {
+	if index < 0 || index >= list.Size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+
+	var target *Node[T]
+
+	if index == 0 {
+		// 删除头节点
+		target = list.Head
+		list.visit(target, 0)
+		if list.Size == 1 {
+			list.setHead(nil)
+			list.setTail(nil)
+		} else {
+			list.setHead(target.Next)
+			if list.IsCircular() {
+				list.setNext(list.Tail, list.Head)
+			}
+			if list.IsDoubly() {
+				if list.IsCircular() {
+					list.setPrev(list.Head, list.Tail)
+				} else {
+					list.setPrev(list.Head, nil)
+				}
+			}
+		}
+	} else {
+		// 找到前驱节点，让它跳过目标节点；双向链表删除尾节点时经 Tail.Prev 直接得到前驱
+		var prev *Node[T]
+		if list.IsDoubly() && index == list.Size-1 {
+			prev = list.Tail.Prev
+			list.visit(prev, index-1)
+		} else {
+			prev = list.nodeAt(index - 1)
+		}
+		target = prev.Next
+		list.visit(target, index)
+		next := target.Next
+
+		list.setNext(prev, next)
+		if list.IsDoubly() && next != nil {
+			list.setPrev(next, prev)
+		}
+		if target == list.Tail {
+			list.setTail(prev)
+		}
+	}
+
+	list.record(TraceStep{
+		Action: StepFree,
+		Index:  intRef(index),
+		Value:  target.Value,
+		Node:   list.label(target),
+		Detail: fmt.Sprintf("位置%d的节点已脱离链表", index),
+	})
+	list.Size--
+
+	return target.Value, nil
+}
+
+// Get 从头节点出发读取位置 index 的节点值
+func (list *LinkedList[T]) Get(index int) (T, error) {
+	if index < 0 || index >= list.Size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	return list.nodeAt(index).Value, nil
+}
+
+// Clear 断开头尾指针，删除全部节点
+func (list *LinkedList[T]) Clear() {
+	list.setHead(nil)
+	list.setTail(nil)
+	list.Size = 0
+}
+
+// IndexOf 从头节点开始查找第一个值为 value 的节点位置，未找到返回 -1
+func (list *LinkedList[T]) IndexOf(value T) int {
+	current := list.Head
+	for i := 0; i < list.Size; i++ {
+		list.visit(current, i)
+		list.record(TraceStep{
+			Action: StepCompare,
+			Index:  intRef(i),
+			Value:  current.Value,
+			Node:   list.label(current),
+			Detail: fmt.Sprintf("比较节点值%v与%v", current.Value, value),
+		})
+		if list.kind.Equal(current.Value, value) {
+			return i
+		}
+		current = current.Next
+	}
+	return -1
+}
+
+// SetAt 修改位置 index 的节点值，返回旧值
+func (list *LinkedList[T]) SetAt(index int, value T) (T, error) {
+	if index < 0 || index >= list.Size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+
+	node := list.nodeAt(index)
+	oldValue := node.Value
+	node.Value = value
+	list.record(TraceStep{
+		Action: StepWrite,
+		Index:  intRef(index),
+		Value:  value,
+		Node:   list.label(node),
+		Detail: fmt.Sprintf("将位置%d的节点值从%v修改为%v", index, oldValue, value),
+	})
+	return oldValue, nil
+}
+
+// 记录游标位置
+func (list *LinkedList[T]) moveCursors(detail string, names []string, nodes ...*Node[T]) {
+	cursors := make(map[string]string, len(names))
+	for i, name := range names {
+		cursors[name] = list.label(nodes[i])
+	}
+	list.record(TraceStep{
+		Action:  StepCursor,
+		Cursors: cursors,
+		Detail:  detail,
+	})
+}
+
+// 迭代反转：逐个让 current.Next 指回 prev；循环链表的 prev 从尾节点开始，
+// 这样原头节点会指向原尾节点，闭合新的环
+func (list *LinkedList[T]) reverseIterative() {
+	if list.Size < 2 {
+		return
+	}
+
+	cursorNames := []string{"prev", "current", "next"}
+	var prev *Node[T]
+	if list.IsCircular() {
+		prev = list.Tail
+	}
+	current := list.Head
+
+	for i := 0; i < list.Size; i++ {
+		next := current.Next
+		list.moveCursors(fmt.Sprintf("第%d轮：prev=%s，current=%s，next=%s", i+1,
+			list.label(prev), list.label(current), list.label(next)), cursorNames, prev, current, next)
+
+		list.setNext(current, prev)
+		if list.IsDoubly() {
+			list.setPrev(current, next)
+		}
+		prev = current
+		current = next
+	}
+	list.moveCursors(fmt.Sprintf("结束：prev=%s 成为新的头节点", list.label(prev)), cursorNames, prev, current, nil)
+
+	oldHead := list.Head
+	list.setHead(list.Tail)
+	list.setTail(oldHead)
+}
+
+// 递归反转：反转 node 之后的部分并返回新的头节点，回溯时让 node 的后继指回 node
+func (list *LinkedList[T]) reverseRecursive() {
+	if list.Size < 2 {
+		return
+	}
+
+	oldHead, oldTail := list.Head, list.Tail
+	newHead := list.reverseFrom(oldHead, 0)
+
+	// 递归结束后原头节点的 Next 为 nil，循环链表需重新闭合
+	if list.IsCircular() {
+		list.setNext(oldHead, newHead)
+		if list.IsDoubly() {
+			list.setPrev(newHead, oldHead)
+		}
+	}
+	list.setHead(oldTail)
+	list.setTail(oldHead)
+}
+
+// 反转从位置 depth 的 node 开始的后半段，返回新的头节点
+func (list *LinkedList[T]) reverseFrom(node *Node[T], depth int) *Node[T] {
+	list.record(TraceStep{
+		Action: StepRecurse,
+		Index:  intRef(depth),
+		Node:   list.label(node),
+		Detail: fmt.Sprintf("第%d层：reverse(%s)", depth, list.label(node)),
+	})
+
+	// 最后一个节点即为新的头节点
+	if depth == list.Size-1 {
+		if list.IsDoubly() {
+			list.setPrev(node, nil)
+		}
+		list.record(TraceStep{
+			Action: StepReturn,
+			Index:  intRef(depth),
+			Node:   list.label(node),
+			Detail: fmt.Sprintf("第%d层：到达最后一个节点，返回%s作为新的头节点", depth, list.label(node)),
+		})
+		return node
+	}
+
+	next := node.Next
+	newHead := list.reverseFrom(next, depth+1)
+
+	list.moveCursors(fmt.Sprintf("第%d层回溯：current=%s，next=%s，让next指回current", depth,
+		list.label(node), list.label(next)), []string{"current", "next"}, node, next)
+	list.setNext(next, node)
+	if list.IsDoubly() {
+		list.setPrev(node, next)
+	}
+	list.setNext(node, nil)
+
+	list.record(TraceStep{
+		Action: StepReturn,
+		Index:  intRef(depth),
+		Node:   list.label(newHead),
+		Detail: fmt.Sprintf("第%d层：返回新的头节点%s", depth, list.label(newHead)),
+	})
+	return newHead
+}
+
+// Reverse 按指定方式原地反转链表，mode 为空时使用迭代
+func (list *LinkedList[T]) Reverse(mode string) error {
+	switch mode {
+	case ReverseIterative, "":
+		list.reverseIterative()
+	case ReverseRecursive:
+		list.reverseRecursive()
+	default:
+		return errors.New("反转方式必须是iterative或recursive")
+	}
+	return nil
+}
+
+// Values 按从头到尾的顺序返回所有节点的值
+func (list *LinkedList[T]) Values() []T {
+	values := make([]T, 0, list.Size)
+	current := list.Head
+	for i := 0; i < list.Size; i++ {
+		values = append(values, current.Value)
+		current = current.Next
+	}
+	return values
+}
@@ -0,0 +1,122 @@
+package ds
+
+import (
+	"slices"
+	"testing"
+)
+
+var listTypes = []string{ListSingle, ListDouble, ListCircular, ListDoubleCircular}
+
+// 沿 Next 和 Prev 指针检查链表结构与 Size、Values 一致
+func checkList(t *testing.T, list *LinkedList[int], want []int) {
+	t.Helper()
+
+	if got := list.Values(); !slices.Equal(got, want) {
+		t.Fatalf("链表为 %v，期望 %v", got, want)
+	}
+	if list.Size != len(want) {
+		t.Fatalf("Size=%d，期望%d", list.Size, len(want))
+	}
+	if list.Size == 0 {
+		if list.Head != nil || list.Tail != nil {
+			t.Fatalf("空链表的 Head/Tail 应为 nil")
+		}
+		return
+	}
+
+	current := list.Head
+	for i := 1; i < list.Size; i++ {
+		if list.IsDoubly() && current.Next.Prev != current {
+			t.Fatalf("第%d个节点的 Prev 指针错误", i)
+		}
+		current = current.Next
+	}
+	if current != list.Tail {
+		t.Fatalf("第%d个节点不是 Tail", list.Size)
+	}
+
+	var next, prev *Node[int]
+	if list.IsCircular() {
+		next = list.Head
+		if list.IsDoubly() {
+			prev = list.Tail
+		}
+	}
+	if list.Tail.Next != next {
+		t.Fatalf("%s链表的 Tail.Next 错误", list.Type)
+	}
+	if list.Head.Prev != prev {
+		t.Fatalf("%s链表的 Head.Prev 错误", list.Type)
+	}
+}
+
+func TestLinkedListInsertRemove(t *testing.T) {
+	for _, listType := range listTypes {
+		t.Run(listType, func(t *testing.T) {
+			list, err := NewLinkedList(listType, Ints)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			steps := []struct {
+				op    string
+				index int
+				value int
+				want  []int
+			}{
+				{"append", 0, 1, []int{1}},
+				{"append", 0, 2, []int{1, 2}},
+				{"prepend", 0, 0, []int{0, 1, 2}},
+				{"insert", 2, 9, []int{0, 1, 9, 2}},
+				{"insert", 4, 3, []int{0, 1, 9, 2, 3}},
+				{"remove", 0, 0, []int{1, 9, 2, 3}},
+				{"remove", 3, 3, []int{1, 9, 2}},
+				{"remove", 1, 9, []int{1, 2}},
+				{"remove", 1, 2, []int{1}},
+				{"remove", 0, 1, []int{}},
+			}
+			for _, step := range steps {
+				switch step.op {
+				case "append":
+					err = list.Append(step.value)
+				case "prepend":
+					err = list.Prepend(step.value)
+				case "insert":
+					err = list.InsertAt(step.index, step.value)
+				case "remove":
+					var removed int
+					removed, err = list.RemoveAt(step.index)
+					if err == nil && removed != step.value {
+						t.Fatalf("删除位置%d得到%d，期望%d", step.index, removed, step.value)
+					}
+				}
+				if err != nil {
+					t.Fatalf("%s(%d, %d) 失败: %v", step.op, step.index, step.value, err)
+				}
+				checkList(t, list, step.want)
+			}
+
+			if _, err := list.RemoveAt(0); err == nil {
+				t.Fatalf("从空链表删除应返回错误")
+			}
+		})
+	}
+}
+
+func TestLinkedListReverse(t *testing.T) {
+	for _, listType := range listTypes {
+		for _, mode := range []string{ReverseIterative, ReverseRecursive} {
+			t.Run(listType+"/"+mode, func(t *testing.T) {
+				for _, values := range [][]int{{}, {1}, {1, 2}, {1, 2, 3, 4, 5}} {
+					list := RestoreLinkedList(listType, values, Ints)
+					if err := list.Reverse(mode); err != nil {
+						t.Fatal(err)
+					}
+					want := slices.Clone(values)
+					slices.Reverse(want)
+					checkList(t, list, want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,484 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// 区间树的类型
+const (
+	RangeSegment = "segment" // 线段树：节点按堆式布局存放，节点 i 的子节点为 2i 和 2i+1，根为1
+	RangeFenwick = "fenwick" // 树状数组：节点 i 存放 (i-lowbit(i), i] 的和，只支持求和
+)
+
+// 区间聚合运算
+const (
+	RangeSum = "sum"
+	RangeMin = "min"
+	RangeMax = "max"
+)
+
+// RangeTreeConfig 建立区间树的参数
+type RangeTreeConfig struct {
+	Type      string `json:"type"`      // 为空时建立线段树
+	Operation string `json:"operation"` // 为空时求和
+	Lazy      bool   `json:"lazy"`      // 仅线段树：区间更新使用懒标记
+}
+
+// RangeTreeState 区间树中可序列化的状态
+type RangeTreeState struct {
+	Type      string `json:"type"`
+	Operation string `json:"operation"`
+	Lazy      bool   `json:"lazy"`
+	Size      int    `json:"size"` // 原数组长度
+
+	Recorder `json:"-"`
+}
+
+// RangeTree 建立在数值数组上的线段树或树状数组；下标从0开始，区间均为闭区间
+type RangeTree struct {
+	RangeTreeState
+	Values  []float64 `json:"values"` // 当前的数组值
+	Nodes   []float64 `json:"-"`      // 节点值，下标从1开始：线段树长度为 4n，树状数组长度为 n+1
+	Pending []float64 `json:"-"`      // 仅带懒标记的线段树：节点上尚未下传给子节点的增量
+}
+
+// RangeNode 一个节点覆盖的区间和值，用于显示
+type RangeNode struct {
+	Index   int      `json:"index"`
+	Low     int      `json:"lo"`
+	High    int      `json:"hi"`
+	Value   float64  `json:"value"`
+	Pending *float64 `json:"pending,omitempty"` // 仅带懒标记的线段树：非零的懒标记
+	Parent  int      `json:"parent"`            // 线段树为 i/2；树状数组为更新时的下一个节点 i+lowbit(i)；没有时为0
+	Left    int      `json:"left,omitempty"`    // 仅线段树的内部节点
+	Right   int      `json:"right,omitempty"`   // 仅线段树的内部节点
+}
+
+// NewRangeTree 在 values 上建立区间树，建树过程记入追踪
+func NewRangeTree(config RangeTreeConfig, values []float64) (*RangeTree, error) {
+	if config.Type == "" {
+		config.Type = RangeSegment
+	}
+	if config.Operation == "" {
+		config.Operation = RangeSum
+	}
+	if config.Type != RangeSegment && config.Type != RangeFenwick {
+		return nil, errors.New("区间树类型必须是segment或fenwick")
+	}
+	if config.Operation != RangeSum && config.Operation != RangeMin && config.Operation != RangeMax {
+		return nil, errors.New("聚合运算必须是sum、min或max")
+	}
+	if config.Type == RangeFenwick && config.Operation != RangeSum {
+		return nil, errors.New("树状数组只支持求和")
+	}
+	if config.Type == RangeFenwick && config.Lazy {
+		return nil, errors.New("懒标记只适用于线段树")
+	}
+	if len(values) == 0 {
+		return nil, errors.New("数组为空，无法建树")
+	}
+
+	tree := &RangeTree{Values: append([]float64(nil), values...)}
+	tree.Type = config.Type
+	tree.Operation = config.Operation
+	tree.Lazy = config.Lazy
+	tree.Size = len(values)
+	tree.Begin()
+
+	if tree.Type == RangeFenwick {
+		tree.Nodes = make([]float64, tree.Size+1)
+		tree.buildFenwick()
+	} else {
+		tree.Nodes = make([]float64, 4*tree.Size)
+		tree.Pending = make([]float64, 4*tree.Size)
+		tree.build(1, 0, tree.Size-1)
+	}
+	return tree, nil
+}
+
+// RestoreRangeTree 按保存的数组值重新建树，不记录追踪；尚未下传的懒标记在重建后已体现在各节点中
+func RestoreRangeTree(config RangeTreeConfig, values []float64) (*RangeTree, error) {
+	tree, err := NewRangeTree(config, values)
+	if err != nil {
+		return nil, err
+	}
+	tree.Begin()
+	return tree, nil
+}
+
+// Config 区间树的建立参数
+func (tree *RangeTreeState) Config() RangeTreeConfig {
+	return RangeTreeConfig{Type: tree.Type, Operation: tree.Operation, Lazy: tree.Lazy}
+}
+
+// 节点在追踪中的标识
+func (tree *RangeTree) label(node int) string {
+	if tree.Type == RangeFenwick {
+		return fmt.Sprintf("bit[%d]", node)
+	}
+	return fmt.Sprintf("seg[%d]", node)
+}
+
+// 聚合运算的单位元：求和为0，最小值为+∞，最大值为-∞
+func (tree *RangeTree) identity() float64 {
+	switch tree.Operation {
+	case RangeMin:
+		return math.Inf(1)
+	case RangeMax:
+		return math.Inf(-1)
+	default:
+		return 0
+	}
+}
+
+// 合并两个子区间的结果，最小值和最大值计入比较次数
+func (tree *RangeTree) combine(a, b float64) float64 {
+	switch tree.Operation {
+	case RangeMin:
+		tree.cost.Comparisons++
+		return math.Min(a, b)
+	case RangeMax:
+		tree.cost.Comparisons++
+		return math.Max(a, b)
+	default:
+		return a + b
+	}
+}
+
+// 检查闭区间 [l, r] 是否在数组范围内
+func (tree *RangeTree) checkRange(l, r int) error {
+	if l < 0 || r >= tree.Size || l > r {
+		return ErrIndexOutOfRange
+	}
+	return nil
+}
+
+// 访问覆盖 [lo, hi] 的节点
+func (tree *RangeTree) visit(node, lo, hi int) {
+	tree.record(TraceStep{
+		Action: StepVisit,
+		Index:  intRef(node),
+		Low:    intRef(lo),
+		High:   intRef(hi),
+		Value:  tree.Nodes[node],
+		Node:   tree.label(node),
+		Detail: fmt.Sprintf("访问%s，覆盖[%d, %d]，值为%g", tree.label(node), lo, hi, tree.Nodes[node]),
+	})
+}
+
+// 说明进入的情况分支
+func (tree *RangeTree) explain(node, lo, hi int, detail string) {
+	tree.record(TraceStep{
+		Action: StepCase,
+		Index:  intRef(node),
+		Low:    intRef(lo),
+		High:   intRef(hi),
+		Node:   tree.label(node),
+		Detail: detail,
+	})
+}
+
+// 写入节点值
+func (tree *RangeTree) write(node, lo, hi int, value float64, detail string) {
+	tree.Nodes[node] = value
+	tree.cost.Writes++
+	tree.record(TraceStep{
+		Action: StepWrite,
+		Index:  intRef(node),
+		Low:    intRef(lo),
+		High:   intRef(hi),
+		Value:  value,
+		Node:   tree.label(node),
+		Detail: detail,
+	})
+}
+
+// Visited 本次操作访问过的节点下标，按访问顺序排列，可能重复
+func (tree *RangeTree) Visited() []int {
+	visited := make([]int, 0)
+	for _, step := range tree.trace {
+		if step.Action == StepVisit {
+			visited = append(visited, *step.Index)
+		}
+	}
+	return visited
+}
+
+// Layout 所有节点覆盖的区间和值：线段树按先序，树状数组按下标
+func (tree *RangeTree) Layout() []RangeNode {
+	nodes := make([]RangeNode, 0, len(tree.Nodes))
+	if tree.Type == RangeFenwick {
+		for i := 1; i <= tree.Size; i++ {
+			node := RangeNode{Index: i, Low: i - lowbit(i), High: i - 1, Value: tree.Nodes[i]}
+			if parent := i + lowbit(i); parent <= tree.Size {
+				node.Parent = parent
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+
+	var walk func(node, lo, hi int)
+	walk = func(node, lo, hi int) {
+		entry := RangeNode{Index: node, Low: lo, High: hi, Value: tree.Nodes[node], Parent: node / 2}
+		if tree.Lazy && tree.Pending[node] != 0 {
+			pending := tree.Pending[node]
+			entry.Pending = &pending
+		}
+		if lo < hi {
+			entry.Left, entry.Right = 2*node, 2*node+1
+		}
+		nodes = append(nodes, entry)
+		if lo < hi {
+			mid := (lo + hi) / 2
+			walk(2*node, lo, mid)
+			walk(2*node+1, mid+1, hi)
+		}
+	}
+	walk(1, 0, tree.Size-1)
+	return nodes
+}
+
+// Update 把下标 index 处的值改为 value，并更新覆盖它的所有节点
+func (tree *RangeTree) Update(index int, value float64) error {
+	if index < 0 || index >= tree.Size {
+		return ErrIndexOutOfRange
+	}
+
+	if tree.Type == RangeFenwick {
+		delta := value - tree.Values[index]
+		tree.Values[index] = value
+		tree.record(TraceStep{
+			Action: StepCase,
+			Index:  intRef(index),
+			Value:  delta,
+			Detail: fmt.Sprintf("a[%d]从%g改为%g，相当于加上%g", index, value-delta, value, delta),
+		})
+		tree.addFenwick(index, delta)
+		return nil
+	}
+
+	tree.Values[index] = value
+	tree.assign(1, 0, tree.Size-1, index, value)
+	return nil
+}
+
+// RangeUpdate 把 [l, r] 内的每个值都加上 delta
+func (tree *RangeTree) RangeUpdate(l, r int, delta float64) error {
+	if err := tree.checkRange(l, r); err != nil {
+		return err
+	}
+	for i := l; i <= r; i++ {
+		tree.Values[i] += delta
+	}
+
+	switch {
+	case tree.Type == RangeFenwick:
+		tree.record(TraceStep{
+			Action: StepCase,
+			Low:    intRef(l),
+			High:   intRef(r),
+			Value:  delta,
+			Detail: fmt.Sprintf("树状数组逐个下标做点更新，共%d次", r-l+1),
+		})
+		for i := l; i <= r; i++ {
+			tree.addFenwick(i, delta)
+		}
+	case tree.Lazy:
+		tree.addLazy(1, 0, tree.Size-1, l, r, delta)
+	default:
+		tree.addEach(1, 0, tree.Size-1, l, r, delta)
+	}
+	return nil
+}
+
+// Query 求 [l, r] 的聚合值
+func (tree *RangeTree) Query(l, r int) (float64, error) {
+	if err := tree.checkRange(l, r); err != nil {
+		return 0, err
+	}
+	if tree.Type == RangeFenwick {
+		result := tree.prefix(r)
+		if l > 0 {
+			result -= tree.prefix(l - 1)
+		}
+		return result, nil
+	}
+	return tree.query(1, 0, tree.Size-1, l, r), nil
+}
+
+// 线段树：自底向上建树，叶节点取数组值，内部节点合并两个子节点
+func (tree *RangeTree) build(node, lo, hi int) {
+	if lo == hi {
+		tree.write(node, lo, hi, tree.Values[lo], fmt.Sprintf("叶节点%s = a[%d] = %g", tree.label(node), lo, tree.Values[lo]))
+		return
+	}
+	mid := (lo + hi) / 2
+	tree.build(2*node, lo, mid)
+	tree.build(2*node+1, mid+1, hi)
+	tree.pull(node, lo, hi)
+}
+
+// 线段树：由两个子节点重新计算节点值
+func (tree *RangeTree) pull(node, lo, hi int) {
+	value := tree.combine(tree.Nodes[2*node], tree.Nodes[2*node+1])
+	tree.write(node, lo, hi, value, fmt.Sprintf("%s = %s(%s, %s) = %g",
+		tree.label(node), tree.Operation, tree.label(2*node), tree.label(2*node+1), value))
+}
+
+// 线段树：给覆盖 [lo, hi] 的节点加上 delta；带懒标记时内部节点另记下尚未下传的增量
+func (tree *RangeTree) apply(node, lo, hi int, delta float64) {
+	value := tree.Nodes[node] + delta
+	if tree.Operation == RangeSum {
+		value = tree.Nodes[node] + delta*float64(hi-lo+1)
+	}
+	tree.Nodes[node] = value
+	tree.cost.Writes++
+
+	detail := fmt.Sprintf("%s的值更新为%g", tree.label(node), value)
+	if lo < hi {
+		tree.Pending[node] += delta
+		detail = fmt.Sprintf("%s的值更新为%g，懒标记累计为%g，暂不下传", tree.label(node), value, tree.Pending[node])
+	}
+	tree.record(TraceStep{
+		Action: StepLazy,
+		Index:  intRef(node),
+		Low:    intRef(lo),
+		High:   intRef(hi),
+		Value:  value,
+		Node:   tree.label(node),
+		Detail: detail,
+	})
+}
+
+// 线段树：把节点的懒标记下传给两个子节点
+func (tree *RangeTree) pushDown(node, lo, hi int) {
+	if !tree.Lazy || tree.Pending[node] == 0 {
+		return
+	}
+	delta := tree.Pending[node]
+	tree.Pending[node] = 0
+	tree.record(TraceStep{
+		Action: StepPushDown,
+		Index:  intRef(node),
+		Low:    intRef(lo),
+		High:   intRef(hi),
+		Value:  delta,
+		Node:   tree.label(node),
+		Detail: fmt.Sprintf("把%s的懒标记%g下传给%s和%s", tree.label(node), delta, tree.label(2*node), tree.label(2*node+1)),
+	})
+	mid := (lo + hi) / 2
+	tree.apply(2*node, lo, mid, delta)
+	tree.apply(2*node+1, mid+1, hi, delta)
+}
+
+// 线段树：沿根到叶的路径单点赋值，返回时逐层重新合并
+func (tree *RangeTree) assign(node, lo, hi, index int, value float64) {
+	tree.visit(node, lo, hi)
+	if lo == hi {
+		tree.write(node, lo, hi, value, fmt.Sprintf("叶节点%s = %g", tree.label(node), value))
+		return
+	}
+	tree.pushDown(node, lo, hi)
+	mid := (lo + hi) / 2
+	if index <= mid {
+		tree.assign(2*node, lo, mid, index, value)
+	} else {
+		tree.assign(2*node+1, mid+1, hi, index, value)
+	}
+	tree.pull(node, lo, hi)
+}
+
+// 线段树：带懒标记的区间加，完全覆盖的节点打上标记后不再向下
+func (tree *RangeTree) addLazy(node, lo, hi, l, r int, delta float64) {
+	tree.visit(node, lo, hi)
+	if r < lo || hi < l {
+		tree.explain(node, lo, hi, fmt.Sprintf("[%d, %d]与[%d, %d]不相交，跳过", lo, hi, l, r))
+		return
+	}
+	if l <= lo && hi <= r {
+		tree.explain(node, lo, hi, fmt.Sprintf("[%d, %d]完全在[%d, %d]内，整体加%g", lo, hi, l, r, delta))
+		tree.apply(node, lo, hi, delta)
+		return
+	}
+	tree.pushDown(node, lo, hi)
+	mid := (lo + hi) / 2
+	tree.addLazy(2*node, lo, mid, l, r, delta)
+	tree.addLazy(2*node+1, mid+1, hi, l, r, delta)
+	tree.pull(node, lo, hi)
+}
+
+// 线段树：不带懒标记的区间加，每个受影响的叶节点都要走到，返回时逐层重新合并
+func (tree *RangeTree) addEach(node, lo, hi, l, r int, delta float64) {
+	tree.visit(node, lo, hi)
+	if r < lo || hi < l {
+		tree.explain(node, lo, hi, fmt.Sprintf("[%d, %d]与[%d, %d]不相交，跳过", lo, hi, l, r))
+		return
+	}
+	if lo == hi {
+		value := tree.Nodes[node] + delta
+		tree.write(node, lo, hi, value, fmt.Sprintf("叶节点%s加%g，变为%g", tree.label(node), delta, value))
+		return
+	}
+	mid := (lo + hi) / 2
+	tree.addEach(2*node, lo, mid, l, r, delta)
+	tree.addEach(2*node+1, mid+1, hi, l, r, delta)
+	tree.pull(node, lo, hi)
+}
+
+// 线段树：区间查询，完全覆盖的节点直接取值，不相交的节点取单位元
+func (tree *RangeTree) query(node, lo, hi, l, r int) float64 {
+	tree.visit(node, lo, hi)
+	if r < lo || hi < l {
+		tree.explain(node, lo, hi, fmt.Sprintf("[%d, %d]与[%d, %d]不相交，不计入", lo, hi, l, r))
+		return tree.identity()
+	}
+	if l <= lo && hi <= r {
+		tree.explain(node, lo, hi, fmt.Sprintf("[%d, %d]完全在[%d, %d]内，直接使用%g", lo, hi, l, r, tree.Nodes[node]))
+		return tree.Nodes[node]
+	}
+	tree.pushDown(node, lo, hi)
+	mid := (lo + hi) / 2
+	left := tree.query(2*node, lo, mid, l, r)
+	right := tree.query(2*node+1, mid+1, hi, l, r)
+	return tree.combine(left, right)
+}
+
+// 最低位的1所代表的值
+func lowbit(i int) int {
+	return i & -i
+}
+
+// 树状数组：O(n) 建树，每个节点把自己的值加到父节点 i+lowbit(i) 上
+func (tree *RangeTree) buildFenwick() {
+	for i := 1; i <= tree.Size; i++ {
+		tree.write(i, i-lowbit(i), i-1, tree.Nodes[i]+tree.Values[i-1],
+			fmt.Sprintf("%s加上a[%d] = %g", tree.label(i), i-1, tree.Values[i-1]))
+		if parent := i + lowbit(i); parent <= tree.Size {
+			tree.write(parent, parent-lowbit(parent), parent-1, tree.Nodes[parent]+tree.Nodes[i],
+				fmt.Sprintf("%s覆盖[%d, %d]，把它的值%g加到父节点%s", tree.label(i), i-lowbit(i), i-1, tree.Nodes[i], tree.label(parent)))
+		}
+	}
+}
+
+// 树状数组：下标 index 处加 delta，沿 i += lowbit(i) 更新所有覆盖它的节点
+func (tree *RangeTree) addFenwick(index int, delta float64) {
+	for i := index + 1; i <= tree.Size; i += lowbit(i) {
+		tree.visit(i, i-lowbit(i), i-1)
+		tree.write(i, i-lowbit(i), i-1, tree.Nodes[i]+delta,
+			fmt.Sprintf("%s加%g，变为%g；下一个节点为%d + %d = %d", tree.label(i), delta, tree.Nodes[i]+delta, i, lowbit(i), i+lowbit(i)))
+	}
+}
+
+// 树状数组：[0, index] 的前缀和，沿 i -= lowbit(i) 累加
+func (tree *RangeTree) prefix(index int) float64 {
+	sum := 0.0
+	for i := index + 1; i > 0; i -= lowbit(i) {
+		tree.visit(i, i-lowbit(i), i-1)
+		sum += tree.Nodes[i]
+		tree.explain(i, i-lowbit(i), i-1, fmt.Sprintf("前缀和[0, %d]累加%s，当前为%g", index, tree.label(i), sum))
+	}
+	return sum
+}
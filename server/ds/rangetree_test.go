@@ -0,0 +1,114 @@
+package ds
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// 逐个元素计算 [l, r] 的聚合值，作为区间树查询的对照
+func bruteForce(operation string, values []float64, l, r int) float64 {
+	result := values[l]
+	for _, v := range values[l+1 : r+1] {
+		switch operation {
+		case RangeSum:
+			result += v
+		case RangeMin:
+			result = math.Min(result, v)
+		case RangeMax:
+			result = math.Max(result, v)
+		}
+	}
+	return result
+}
+
+func TestRangeTreeQueryAfterRangeUpdate(t *testing.T) {
+	initial := []float64{5, -2, 8, 1, 0, 7, 3, -6, 4}
+	updates := []struct {
+		l, r  int
+		delta float64
+	}{
+		{0, 8, 1},
+		{2, 5, -4},
+		{3, 3, 10},
+		{6, 8, 2.5},
+		{0, 4, -3},
+	}
+
+	configs := []RangeTreeConfig{
+		{Type: RangeSegment, Operation: RangeSum, Lazy: false},
+		{Type: RangeSegment, Operation: RangeSum, Lazy: true},
+		{Type: RangeSegment, Operation: RangeMin, Lazy: false},
+		{Type: RangeSegment, Operation: RangeMin, Lazy: true},
+		{Type: RangeSegment, Operation: RangeMax, Lazy: false},
+		{Type: RangeSegment, Operation: RangeMax, Lazy: true},
+		{Type: RangeFenwick, Operation: RangeSum},
+	}
+
+	for _, config := range configs {
+		t.Run(fmt.Sprintf("%s/%s/lazy=%t", config.Type, config.Operation, config.Lazy), func(t *testing.T) {
+			tree, err := NewRangeTree(config, initial)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values := append([]float64(nil), initial...)
+
+			for _, update := range updates {
+				if err := tree.RangeUpdate(update.l, update.r, update.delta); err != nil {
+					t.Fatal(err)
+				}
+				for i := update.l; i <= update.r; i++ {
+					values[i] += update.delta
+				}
+
+				// 每次更新后检查所有区间，懒标记需要在部分覆盖时正确下传
+				for l := range values {
+					for r := l; r < len(values); r++ {
+						got, err := tree.Query(l, r)
+						if err != nil {
+							t.Fatal(err)
+						}
+						if want := bruteForce(config.Operation, values, l, r); got != want {
+							t.Fatalf("区间[%d, %d]加%g后查询[%d, %d]得到%g，期望%g",
+								update.l, update.r, update.delta, l, r, got, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRangeTreeUpdateThenQuery(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		tree, err := NewRangeTree(RangeTreeConfig{Operation: RangeSum, Lazy: lazy}, []float64{1, 2, 3, 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tree.RangeUpdate(0, 3, 10); err != nil {
+			t.Fatal(err)
+		}
+		// 单点赋值覆盖该位置上此前的区间增量
+		if err := tree.Update(1, 0); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := tree.Query(0, 3); got != 11+0+13+14 {
+			t.Fatalf("lazy=%t: sum[0, 3] = %g，期望38", lazy, got)
+		}
+	}
+}
+
+func TestRangeTreeRejectsInvalidRange(t *testing.T) {
+	tree, err := NewRangeTree(RangeTreeConfig{}, []float64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bounds := range [][2]int{{-1, 1}, {2, 1}, {0, 3}} {
+		if _, err := tree.Query(bounds[0], bounds[1]); err == nil {
+			t.Fatalf("查询[%d, %d]应返回错误", bounds[0], bounds[1])
+		}
+		if err := tree.RangeUpdate(bounds[0], bounds[1], 1); err == nil {
+			t.Fatalf("更新[%d, %d]应返回错误", bounds[0], bounds[1])
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RingState 环形缓冲区中与元素类型无关的状态
+type RingState struct {
+	Capacity        int    `json:"capacity"`
+	Size            int    `json:"size"`
+	Head            int    `json:"head"` // 队首元素所在的槽位
+	Tail            int    `json:"tail"` // 下一个从队尾写入的槽位，队满时与 Head 重合
+	GrowthStrategy  string `json:"growthStrategy"`
+	GrowthIncrement int    `json:"growthIncrement,omitempty"`
+
+	Recorder `json:"-"`
+}
+
+// RingBuffer 环形缓冲区：在固定大小的槽位数组上移动 Head/Tail 下标，越过末尾时回绕到 0；
+// 槽位用尽时按扩容策略重新分配，并把元素按逻辑顺序复制到新存储的开头
+type RingBuffer[T any] struct {
+	RingState
+
+	slots []T
+	kind  *ElementType[T]
+}
+
+// NewRingBuffer 按配置创建空的环形缓冲区，环形缓冲区不缩容，ShrinkFactor 必须为0
+func NewRingBuffer[T any](config ArrayConfig, kind *ElementType[T]) (*RingBuffer[T], error) {
+	if config.Capacity <= 0 {
+		config.Capacity = DefaultCapacity
+	}
+	if config.GrowthStrategy == "" {
+		config.GrowthStrategy = DefaultGrowthStrategy
+	}
+	if _, err := NewGrowthStrategy(config.GrowthStrategy, config.GrowthIncrement); err != nil {
+		return nil, err
+	}
+	if config.ShrinkFactor != 0 {
+		return nil, errors.New("环形缓冲区不支持缩容")
+	}
+	if config.GrowthStrategy == GrowthFixedIncrement && config.GrowthIncrement <= 0 {
+		config.GrowthIncrement = DefaultGrowthIncrement
+	}
+
+	ring := &RingBuffer[T]{
+		slots: make([]T, config.Capacity),
+		kind:  kind,
+	}
+	ring.Capacity = config.Capacity
+	ring.GrowthStrategy = config.GrowthStrategy
+	ring.GrowthIncrement = config.GrowthIncrement
+	return ring, nil
+}
+
+// RestoreRingBuffer 从 head 槽位开始依次放入 values 重建环形缓冲区，保留原来的回绕位置
+func RestoreRingBuffer[T any](config ArrayConfig, head int, values []T, kind *ElementType[T]) (*RingBuffer[T], error) {
+	config.Capacity = max(config.Capacity, len(values))
+	ring, err := NewRingBuffer(config, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if head < 0 || head >= ring.Capacity {
+		head = 0
+	}
+	for i, value := range values {
+		ring.slots[(head+i)%ring.Capacity] = value
+	}
+	ring.Head = head
+	ring.Size = len(values)
+	ring.Tail = (head + ring.Size) % ring.Capacity
+	return ring, nil
+}
+
+// Kind 环形缓冲区的元素类型
+func (ring *RingBuffer[T]) Kind() *ElementType[T] {
+	return ring.kind
+}
+
+// Config 按当前容量和扩容参数生成的配置
+func (ring *RingBuffer[T]) Config() ArrayConfig {
+	return ArrayConfig{
+		Capacity:        ring.Capacity,
+		GrowthStrategy:  ring.GrowthStrategy,
+		GrowthIncrement: ring.GrowthIncrement,
+	}
+}
+
+// Slots 原始槽位，空槽位为 nil
+func (ring *RingBuffer[T]) Slots() []*T {
+	slots := make([]*T, ring.Capacity)
+	for i := 0; i < ring.Size; i++ {
+		index := (ring.Head + i) % ring.Capacity
+		slots[index] = &ring.slots[index]
+	}
+	return slots
+}
+
+// Values 从队首到队尾返回所有元素
+func (ring *RingBuffer[T]) Values() []T {
+	values := make([]T, ring.Size)
+	for i := range values {
+		values[i] = ring.slots[(ring.Head+i)%ring.Capacity]
+	}
+	return values
+}
+
+// 下标在环上前进一格，越过末尾时回绕到 0
+func (ring *RingBuffer[T]) next(index int) (int, bool) {
+	if index == ring.Capacity-1 {
+		return 0, true
+	}
+	return index + 1, false
+}
+
+// 下标在环上后退一格，越过开头时回绕到末尾
+func (ring *RingBuffer[T]) prev(index int) (int, bool) {
+	if index == 0 {
+		return ring.Capacity - 1, true
+	}
+	return index - 1, false
+}
+
+// 描述下标移动，回绕时特别说明
+func moveDetail(name string, from, to int, wrapped bool) string {
+	if wrapped {
+		return fmt.Sprintf("%s从%d移动到%d（回绕）", name, from, to)
+	}
+	return fmt.Sprintf("%s从%d移动到%d", name, from, to)
+}
+
+// 移动队首下标
+func (ring *RingBuffer[T]) moveHead(to int, wrapped bool) {
+	ring.record(TraceStep{
+		Action: StepMoveHead,
+		From:   intRef(ring.Head),
+		To:     intRef(to),
+		Detail: moveDetail("head", ring.Head, to, wrapped),
+	})
+	ring.Head = to
+}
+
+// 移动队尾下标
+func (ring *RingBuffer[T]) moveTail(to int, wrapped bool) {
+	ring.record(TraceStep{
+		Action: StepMoveTail,
+		From:   intRef(ring.Tail),
+		To:     intRef(to),
+		Detail: moveDetail("tail", ring.Tail, to, wrapped),
+	})
+	ring.Tail = to
+}
+
+// 读取槽位中的元素
+func (ring *RingBuffer[T]) read(slot int) T {
+	value := ring.slots[slot]
+	ring.record(TraceStep{
+		Action: StepRead,
+		Index:  intRef(slot),
+		Value:  value,
+		Detail: fmt.Sprintf("读取槽位%d中的元素%v", slot, value),
+	})
+	return value
+}
+
+// 向槽位写入元素
+func (ring *RingBuffer[T]) write(slot int, value T) {
+	ring.slots[slot] = value
+	ring.cost.Writes++
+	ring.record(TraceStep{
+		Action: StepWrite,
+		Index:  intRef(slot),
+		Value:  value,
+		Detail: fmt.Sprintf("将%v写入槽位%d", value, slot),
+	})
+}
+
+// 取出槽位中的元素并清空该槽位
+func (ring *RingBuffer[T]) take(slot int) T {
+	value := ring.read(slot)
+	var zero T
+	ring.slots[slot] = zero
+	return value
+}
+
+// 槽位用尽时按扩容策略分配新存储，按逻辑顺序把元素复制到新存储的 0..Size-1
+func (ring *RingBuffer[T]) ensureCapacity() (*ResizeEvent, error) {
+	if ring.Size < ring.Capacity {
+		return nil, nil
+	}
+
+	strategy, err := NewGrowthStrategy(ring.GrowthStrategy, ring.GrowthIncrement)
+	if err != nil {
+		return nil, err
+	}
+	newCap := strategy.NewCapacity(ring.Capacity, ring.Size+1)
+	if newCap <= ring.Size {
+		return nil, ErrFull
+	}
+
+	event := &ResizeEvent{
+		Kind:           "grow",
+		OldCapacity:    ring.Capacity,
+		NewCapacity:    newCap,
+		ElementsCopied: ring.Size,
+	}
+	ring.record(TraceStep{
+		Action: StepAllocate,
+		Detail: fmt.Sprintf("分配%d个槽位的新存储（原容量%d）", newCap, ring.Capacity),
+	})
+
+	slots := make([]T, newCap)
+	for i := 0; i < ring.Size; i++ {
+		from := (ring.Head + i) % ring.Capacity
+		slots[i] = ring.slots[from]
+		ring.cost.Copies++
+		ring.record(TraceStep{
+			Action: StepCopy,
+			From:   intRef(from),
+			To:     intRef(i),
+			Value:  slots[i],
+			Detail: fmt.Sprintf("复制槽位%d的元素%v到新存储的槽位%d", from, slots[i], i),
+		})
+	}
+	ring.slots = slots
+	ring.Capacity = newCap
+	ring.cost.Reallocations++
+	ring.moveHead(0, false)
+	ring.moveTail(ring.Size%newCap, false)
+
+	return event, nil
+}
+
+// PushBack 在队尾写入元素，Tail 前进一格
+func (ring *RingBuffer[T]) PushBack(value T) (*ResizeEvent, error) {
+	resize, err := ring.ensureCapacity()
+	if err != nil {
+		return nil, err
+	}
+
+	ring.write(ring.Tail, value)
+	ring.moveTail(ring.next(ring.Tail))
+	ring.Size++
+	return resize, nil
+}
+
+// PushFront Head 后退一格后在队首写入元素
+func (ring *RingBuffer[T]) PushFront(value T) (*ResizeEvent, error) {
+	resize, err := ring.ensureCapacity()
+	if err != nil {
+		return nil, err
+	}
+
+	ring.moveHead(ring.prev(ring.Head))
+	ring.write(ring.Head, value)
+	ring.Size++
+	return resize, nil
+}
+
+// PopFront 取出队首元素，Head 前进一格
+func (ring *RingBuffer[T]) PopFront() (T, error) {
+	if ring.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+
+	value := ring.take(ring.Head)
+	ring.moveHead(ring.next(ring.Head))
+	ring.Size--
+	return value, nil
+}
+
+// PopBack Tail 后退一格后取出队尾元素
+func (ring *RingBuffer[T]) PopBack() (T, error) {
+	if ring.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+
+	ring.moveTail(ring.prev(ring.Tail))
+	value := ring.take(ring.Tail)
+	ring.Size--
+	return value, nil
+}
+
+// Front 读取队首元素
+func (ring *RingBuffer[T]) Front() (T, error) {
+	if ring.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return ring.read(ring.Head), nil
+}
+
+// Back 读取队尾元素，即 Tail 前一个槽位
+func (ring *RingBuffer[T]) Back() (T, error) {
+	if ring.Size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	last, _ := ring.prev(ring.Tail)
+	return ring.read(last), nil
+}
@@ -0,0 +1,338 @@
+package ds
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// 查找算法
+const (
+	SearchLinear        = "linear"
+	SearchAll           = "all" // 线性扫描，返回所有匹配的位置
+	SearchBinary        = "binary"
+	SearchInterpolation = "interpolation"
+	SearchExponential   = "exponential"
+	SearchJump          = "jump"
+	SearchLowerBound    = "lower_bound" // 第一个不小于目标值的位置
+	SearchUpperBound    = "upper_bound" // 第一个大于目标值的位置
+)
+
+// SearchResult 查找结果
+type SearchResult struct {
+	Algorithm string `json:"algorithm"`
+	Value     any    `json:"value"`
+	Found     bool   `json:"found"`
+	Index     int    `json:"index"`             // 匹配的位置，lower_bound/upper_bound 为边界位置，未找到为 -1
+	Indices   []int  `json:"indices,omitempty"` // all 的全部匹配位置
+	Probes    []int  `json:"probes"`            // 按顺序探查过的索引
+}
+
+// 各元素类型的 arraySearcher[T] 都实现的查找方法，供注册表按名称分发
+type searcher interface {
+	linearSearch() *SearchResult
+	findAll() *SearchResult
+	binarySearch() *SearchResult
+	interpolationSearch() *SearchResult
+	exponentialSearch() *SearchResult
+	jumpSearch() *SearchResult
+	lowerBound() *SearchResult
+	upperBound() *SearchResult
+}
+
+// 已登记的查找算法
+type searchAlgorithm struct {
+	sorted  bool // 是否要求数组按升序排列
+	numeric bool // 是否要求元素为数值类型
+	run     func(s searcher) *SearchResult
+}
+
+// 已注册的查找算法，新增算法只需在此登记
+var searchAlgorithms = map[string]searchAlgorithm{
+	SearchLinear:        {sorted: false, run: searcher.linearSearch},
+	SearchAll:           {sorted: false, run: searcher.findAll},
+	SearchBinary:        {sorted: true, run: searcher.binarySearch},
+	SearchInterpolation: {sorted: true, numeric: true, run: searcher.interpolationSearch},
+	SearchExponential:   {sorted: true, run: searcher.exponentialSearch},
+	SearchJump:          {sorted: true, run: searcher.jumpSearch},
+	SearchLowerBound:    {sorted: true, run: searcher.lowerBound},
+	SearchUpperBound:    {sorted: true, run: searcher.upperBound},
+}
+
+// SearchAlgorithmNames 按字母顺序返回所有已注册的查找算法名称
+func SearchAlgorithmNames() []string {
+	names := make([]string, 0, len(searchAlgorithms))
+	for name := range searchAlgorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnsortedError 数组不满足查找算法的有序前提，Index 为第一个逆序的位置
+type UnsortedError struct {
+	Algorithm string
+	Index     int
+	Element   any
+	Previous  any
+}
+
+func (e *UnsortedError) Error() string {
+	return fmt.Sprintf("%s查找要求数组按升序排列，但索引%d处的%v小于前一个元素%v，请先排序",
+		e.Algorithm, e.Index, e.Element, e.Previous)
+}
+
+// 检查查找算法能否用于数组的元素类型和当前顺序
+func (array *Array[T]) checkSearch(name string) error {
+	algorithm, ok := searchAlgorithms[name]
+	if !ok {
+		return fmt.Errorf("查找算法必须是%s之一", strings.Join(SearchAlgorithmNames(), "、"))
+	}
+	if algorithm.numeric && array.kind.Number == nil {
+		return fmt.Errorf("%s查找只支持%s或%s类型元素", name, ElementInt, ElementFloat)
+	}
+
+	// 二分类算法的前提是数组有序，否则结果没有意义
+	if algorithm.sorted {
+		if index := array.firstUnsorted(); index >= 0 {
+			return &UnsortedError{
+				Algorithm: name,
+				Index:     index,
+				Element:   array.Elements[index],
+				Previous:  array.Elements[index-1],
+			}
+		}
+	}
+	return nil
+}
+
+// Search 使用指定算法查找 value，每次探查计一次比较；二分类算法要求数组按升序排列，否则返回 *UnsortedError
+func (array *Array[T]) Search(name string, value T) (*SearchResult, error) {
+	if err := array.checkSearch(name); err != nil {
+		return nil, err
+	}
+
+	searcher := &arraySearcher[T]{array: array, value: value, probes: make([]int, 0)}
+	result := searchAlgorithms[name].run(searcher)
+	result.Algorithm = name
+	return result, nil
+}
+
+// 在数组上执行查找，每次探查计一次比较，并记录查找范围的变化
+type arraySearcher[T any] struct {
+	array  *Array[T]
+	value  T
+	probes []int
+}
+
+// 探查索引处的元素并与目标值比较，返回比较结果：元素小于目标值为负，相等为0
+func (s *arraySearcher[T]) probe(index int) int {
+	element := s.array.Elements[index]
+	s.probes = append(s.probes, index)
+	s.array.cost.Comparisons++
+	s.array.record(TraceStep{
+		Action: StepProbe,
+		Index:  intRef(index),
+		Value:  element,
+		Detail: fmt.Sprintf("探查索引%d处的元素%v，与%v比较", index, element, s.value),
+	})
+	return s.array.kind.Compare(element, s.value)
+}
+
+// 记录新的查找范围 [lo, hi]
+func (s *arraySearcher[T]) narrow(lo, hi int, reason string) {
+	s.array.record(TraceStep{
+		Action: StepNarrow,
+		Low:    intRef(lo),
+		High:   intRef(hi),
+		Detail: fmt.Sprintf("%s，查找范围为[%d, %d]", reason, lo, hi),
+	})
+}
+
+// 生成查找结果，index 为 -1 表示未找到
+func (s *arraySearcher[T]) result(index int) *SearchResult {
+	return &SearchResult{
+		Value:  s.value,
+		Found:  index >= 0,
+		Index:  index,
+		Probes: s.probes,
+	}
+}
+
+// 线性查找：从头逐个比较，返回第一个匹配的位置
+func (s *arraySearcher[T]) linearSearch() *SearchResult {
+	for i := 0; i < s.array.Size; i++ {
+		if s.probe(i) == 0 {
+			return s.result(i)
+		}
+	}
+	return s.result(-1)
+}
+
+// 查找所有匹配的位置
+func (s *arraySearcher[T]) findAll() *SearchResult {
+	indices := make([]int, 0)
+	for i := 0; i < s.array.Size; i++ {
+		if s.probe(i) == 0 {
+			indices = append(indices, i)
+		}
+	}
+
+	result := s.result(-1)
+	if len(indices) > 0 {
+		result = s.result(indices[0])
+	}
+	result.Indices = indices
+	return result
+}
+
+// 二分查找：在闭区间 [lo, hi] 内反复取中点，返回任意一个匹配的位置
+func (s *arraySearcher[T]) binarySearch() *SearchResult {
+	return s.result(s.binaryRange(0, s.array.Size-1))
+}
+
+// 在闭区间 [lo, hi] 内二分查找，未找到返回 -1
+func (s *arraySearcher[T]) binaryRange(lo, hi int) int {
+	s.narrow(lo, hi, "开始二分查找")
+	for lo <= hi {
+		mid := lo + (hi-lo)/2
+		order := s.probe(mid)
+		switch {
+		case order == 0:
+			return mid
+		case order < 0:
+			lo = mid + 1
+			s.narrow(lo, hi, fmt.Sprintf("%v小于%v，lo移到mid+1", s.array.Elements[mid], s.value))
+		default:
+			hi = mid - 1
+			s.narrow(lo, hi, fmt.Sprintf("%v大于%v，hi移到mid-1", s.array.Elements[mid], s.value))
+		}
+	}
+	return -1
+}
+
+// 插值查找：按目标值在 [e[lo], e[hi]] 中的比例估计位置，适合分布均匀的数据
+func (s *arraySearcher[T]) interpolationSearch() *SearchResult {
+	elements := s.array.Elements
+	number := s.array.kind.Number
+	value := number(s.value)
+	lo, hi := 0, s.array.Size-1
+	s.narrow(lo, hi, "开始插值查找")
+
+	for lo <= hi && value >= number(elements[lo]) && value <= number(elements[hi]) {
+		pos := lo
+		if number(elements[hi]) != number(elements[lo]) {
+			// 用浮点数计算比例，避免大数相乘溢出
+			ratio := (value - number(elements[lo])) / (number(elements[hi]) - number(elements[lo]))
+			pos = lo + int(math.Floor(ratio*float64(hi-lo)))
+			pos = min(max(pos, lo), hi)
+		}
+
+		order := s.probe(pos)
+		switch {
+		case order == 0:
+			return s.result(pos)
+		case order < 0:
+			lo = pos + 1
+			s.narrow(lo, hi, fmt.Sprintf("%v小于%v，lo移到pos+1", elements[pos], s.value))
+		default:
+			hi = pos - 1
+			s.narrow(lo, hi, fmt.Sprintf("%v大于%v，hi移到pos-1", elements[pos], s.value))
+		}
+	}
+	return s.result(-1)
+}
+
+// 指数查找：边界按1、2、4……倍增，直到越过目标值，再在最后一段内二分查找
+func (s *arraySearcher[T]) exponentialSearch() *SearchResult {
+	n := s.array.Size
+	if n == 0 {
+		return s.result(-1)
+	}
+	if s.probe(0) == 0 {
+		return s.result(0)
+	}
+
+	bound := 1
+	for bound < n && s.probe(bound) < 0 {
+		s.narrow(bound, min(2*bound, n-1), fmt.Sprintf("索引%d处的元素小于%v，边界倍增", bound, s.value))
+		bound *= 2
+	}
+	return s.result(s.binaryRange(bound/2, min(bound, n-1)))
+}
+
+// 跳跃查找：每次跳过 √n 个元素，找到目标值所在的块后在块内线性查找
+func (s *arraySearcher[T]) jumpSearch() *SearchResult {
+	n := s.array.Size
+	if n == 0 {
+		return s.result(-1)
+	}
+
+	step := max(int(math.Sqrt(float64(n))), 1)
+	prev, next := 0, step
+	s.narrow(prev, min(next, n)-1, fmt.Sprintf("块大小为%d", step))
+	for s.probe(min(next, n)-1) < 0 {
+		prev = next
+		if prev >= n {
+			return s.result(-1)
+		}
+		next += step
+		s.narrow(prev, min(next, n)-1, "块末元素小于目标值，跳到下一块")
+	}
+
+	for i := prev; i < min(next, n); i++ {
+		order := s.probe(i)
+		if order == 0 {
+			return s.result(i)
+		}
+		if order > 0 {
+			break
+		}
+	}
+	return s.result(-1)
+}
+
+// 第一个不小于目标值的位置，在半开区间 [lo, hi) 内二分
+func (s *arraySearcher[T]) lowerBound() *SearchResult {
+	return s.boundResult(s.bound(func(order int) bool { return order < 0 }))
+}
+
+// 第一个大于目标值的位置
+func (s *arraySearcher[T]) upperBound() *SearchResult {
+	return s.boundResult(s.bound(func(order int) bool { return order <= 0 }))
+}
+
+// 在 [0, n) 内二分出第一个使 before 为假的位置，before 以元素与目标值的比较结果判断元素是否在边界之前
+func (s *arraySearcher[T]) bound(before func(order int) bool) int {
+	lo, hi := 0, s.array.Size
+	s.narrow(lo, hi, "开始二分，区间为半开区间")
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if before(s.probe(mid)) {
+			lo = mid + 1
+			s.narrow(lo, hi, fmt.Sprintf("%v在边界之前，lo移到mid+1", s.array.Elements[mid]))
+		} else {
+			hi = mid
+			s.narrow(lo, hi, fmt.Sprintf("%v在边界处或之后，hi移到mid", s.array.Elements[mid]))
+		}
+	}
+	return lo
+}
+
+// 边界查找的结果总是有效位置，Found 表示该位置的元素是否等于目标值
+func (s *arraySearcher[T]) boundResult(index int) *SearchResult {
+	result := s.result(index)
+	result.Found = index < s.array.Size && s.array.kind.Equal(s.array.Elements[index], s.value)
+	return result
+}
+
+// 检查数组是否按升序排列，返回第一个逆序的位置，有序时返回 -1
+func (array *Array[T]) firstUnsorted() int {
+	for i := 1; i < array.Size; i++ {
+		if array.kind.Compare(array.Elements[i], array.Elements[i-1]) < 0 {
+			return i
+		}
+	}
+	return -1
+}
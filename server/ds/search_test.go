@@ -0,0 +1,98 @@
+package ds
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSearchAlgorithms(t *testing.T) {
+	sorted := []int{1, 3, 3, 3, 7, 9, 12}
+
+	tests := []struct {
+		value int
+		want  map[string]int // 各算法返回的位置，-1 表示未找到
+	}{
+		{3, map[string]int{
+			SearchLinear: 1, SearchAll: 1, SearchLowerBound: 1, SearchUpperBound: 4,
+		}},
+		{9, map[string]int{
+			SearchLinear: 5, SearchAll: 5, SearchBinary: 5, SearchInterpolation: 5,
+			SearchExponential: 5, SearchJump: 5, SearchLowerBound: 5, SearchUpperBound: 6,
+		}},
+		{4, map[string]int{
+			SearchLinear: -1, SearchAll: -1, SearchBinary: -1, SearchInterpolation: -1,
+			SearchExponential: -1, SearchJump: -1, SearchLowerBound: 4, SearchUpperBound: 4,
+		}},
+		{20, map[string]int{
+			SearchLinear: -1, SearchBinary: -1, SearchInterpolation: -1,
+			SearchExponential: -1, SearchJump: -1, SearchLowerBound: 7, SearchUpperBound: 7,
+		}},
+	}
+
+	for _, algorithm := range SearchAlgorithmNames() {
+		t.Run(algorithm, func(t *testing.T) {
+			for _, tt := range tests {
+				array := newIntArray(t, sorted)
+				result, err := array.Search(algorithm, tt.value)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want, ok := tt.want[algorithm]
+				if !ok {
+					// 有重复元素时二分类算法可能命中任意一个，只检查命中的值
+					if result.Found && sorted[result.Index] != tt.value {
+						t.Fatalf("查找%d命中了位置%d的%d", tt.value, result.Index, sorted[result.Index])
+					}
+					if !result.Found && tt.value == 3 {
+						t.Fatalf("查找%d未找到", tt.value)
+					}
+					continue
+				}
+				if result.Index != want {
+					t.Fatalf("查找%d返回位置%d，期望%d", tt.value, result.Index, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchAllIndices(t *testing.T) {
+	array := newIntArray(t, []int{3, 1, 3, 2, 3})
+	result, err := array.Search(SearchAll, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Found || len(result.Indices) != 3 || result.Indices[0] != 0 || result.Indices[2] != 4 {
+		t.Fatalf("查找全部3得到 %+v，期望位置 [0 2 4]", *result)
+	}
+}
+
+func TestSortedSearchRejectsUnsortedArray(t *testing.T) {
+	array := newIntArray(t, []int{1, 5, 4, 6})
+
+	for _, algorithm := range SearchAlgorithmNames() {
+		_, err := array.Search(algorithm, 4)
+		var unsorted *UnsortedError
+		if searchAlgorithms[algorithm].sorted {
+			if !errors.As(err, &unsorted) || unsorted.Index != 2 {
+				t.Fatalf("%s查找无序数组返回 %v，期望在索引2处的 UnsortedError", algorithm, err)
+			}
+		} else if err != nil {
+			t.Fatalf("%s查找不要求有序，但返回 %v", algorithm, err)
+		}
+	}
+}
+
+func TestInterpolationSearchRequiresNumbers(t *testing.T) {
+	array, err := NewArray(ArrayConfig{}, Strings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := array.Search(SearchInterpolation, "a"); err == nil {
+		t.Fatalf("字符串数组的插值查找应返回错误")
+	}
+	if _, err := array.Search(SearchBinary, "a"); err != nil {
+		t.Fatalf("字符串数组的二分查找失败: %v", err)
+	}
+}
@@ -0,0 +1,344 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// 跳表的默认参数
+const (
+	DefaultSkipListMaxLevel    = 16
+	DefaultSkipListProbability = 0.5
+)
+
+// 跳表层数的上限，超过后提升的概率已可以忽略
+const maxSkipListLevel = 32
+
+// SkipListConfig 创建跳表的参数，零值字段使用默认值
+type SkipListConfig struct {
+	MaxLevel    int     `json:"maxLevel"`
+	Probability float64 `json:"probability"` // 新节点每提升一层的概率
+	Seed        int64   `json:"seed"`        // 决定提升的随机数种子，相同种子和相同操作序列得到相同的结构
+}
+
+// SkipListState 跳表中与元素类型无关的状态
+type SkipListState struct {
+	MaxLevel    int     `json:"maxLevel"`
+	Probability float64 `json:"probability"`
+	Seed        int64   `json:"seed"`
+	Level       int     `json:"level"` // 当前使用的层数，空表为0
+	Size        int     `json:"size"`
+	Draws       int     `json:"draws"` // 已经抽取的随机数个数，恢复时据此重放随机数序列
+
+	Recorder `json:"-"`
+}
+
+// SkipListNode 跳表节点，Next[i] 为第 i 层（从0开始，第0层包含全部节点）的后继
+type SkipListNode[T any] struct {
+	ID    int
+	Value T
+	Next  []*SkipListNode[T]
+}
+
+// SavedSkipListNode 持久化的节点，按第0层的顺序排列并保存各自的层数即可重建全部指针
+type SavedSkipListNode[T any] struct {
+	ID    int `json:"id"`
+	Value T   `json:"value"`
+	Level int `json:"level"`
+}
+
+// SkipListStep 查找路径上的一步：在第 Level 层到达节点 Node，头节点的 Node 为-1
+type SkipListStep struct {
+	Level int `json:"level"`
+	Node  int `json:"node"`
+}
+
+// SkipList 跳表，元素有序且不重复；新节点的层数由带种子的随机数决定
+type SkipList[T any] struct {
+	SkipListState
+	Head *SkipListNode[T] `json:"-"` // 头节点不存放元素，在每一层都存在
+
+	nextID int
+	rng    *rand.Rand
+	kind   *ElementType[T]
+}
+
+// NewSkipList 按配置创建空跳表
+func NewSkipList[T any](config SkipListConfig, kind *ElementType[T]) (*SkipList[T], error) {
+	if config.MaxLevel == 0 {
+		config.MaxLevel = DefaultSkipListMaxLevel
+	}
+	if config.Probability == 0 {
+		config.Probability = DefaultSkipListProbability
+	}
+	if config.MaxLevel < 1 || config.MaxLevel > maxSkipListLevel {
+		return nil, fmt.Errorf("最大层数必须在1到%d之间", maxSkipListLevel)
+	}
+	if config.Probability <= 0 || config.Probability >= 1 {
+		return nil, errors.New("提升概率必须在0到1之间")
+	}
+
+	list := &SkipList[T]{
+		Head: &SkipListNode[T]{ID: -1, Next: make([]*SkipListNode[T], config.MaxLevel)},
+		rng:  rand.New(rand.NewSource(config.Seed)),
+		kind: kind,
+	}
+	list.MaxLevel = config.MaxLevel
+	list.Probability = config.Probability
+	list.Seed = config.Seed
+	list.Begin()
+	return list, nil
+}
+
+// RestoreSkipList 按第0层顺序的节点和各自的层数重建跳表，不记录追踪；
+// 随机数生成器重放 draws 次，之后插入的节点与未保存时得到相同的层数
+func RestoreSkipList[T any](config SkipListConfig, draws int, nodes []SavedSkipListNode[T], kind *ElementType[T]) (*SkipList[T], error) {
+	list, err := NewSkipList(config, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	last := make([]*SkipListNode[T], list.MaxLevel)
+	for i := range last {
+		last[i] = list.Head
+	}
+	for i, saved := range nodes {
+		if saved.Level < 1 || saved.Level > list.MaxLevel {
+			return nil, fmt.Errorf("节点%d的层数%d超出范围", saved.ID, saved.Level)
+		}
+		if i > 0 && kind.Compare(nodes[i-1].Value, saved.Value) >= 0 {
+			return nil, errors.New("跳表节点没有按升序排列")
+		}
+		node := &SkipListNode[T]{ID: saved.ID, Value: saved.Value, Next: make([]*SkipListNode[T], saved.Level)}
+		for level := range saved.Level {
+			last[level].Next[level] = node
+			last[level] = node
+		}
+		list.Level = max(list.Level, saved.Level)
+		list.nextID = max(list.nextID, saved.ID+1)
+		list.Size++
+	}
+
+	for range draws {
+		list.rng.Float64()
+	}
+	list.Draws = draws
+	return list, nil
+}
+
+// Kind 跳表的元素类型
+func (list *SkipList[T]) Kind() *ElementType[T] {
+	return list.kind
+}
+
+// Config 跳表的创建参数
+func (list *SkipListState) Config() SkipListConfig {
+	return SkipListConfig{MaxLevel: list.MaxLevel, Probability: list.Probability, Seed: list.Seed}
+}
+
+// Saved 按第0层顺序列出节点及其层数，用于持久化
+func (list *SkipList[T]) Saved() []SavedSkipListNode[T] {
+	nodes := make([]SavedSkipListNode[T], 0, list.Size)
+	for node := list.Head.Next[0]; node != nil; node = node.Next[0] {
+		nodes = append(nodes, SavedSkipListNode[T]{ID: node.ID, Value: node.Value, Level: len(node.Next)})
+	}
+	return nodes
+}
+
+// Values 按升序列出全部元素
+func (list *SkipList[T]) Values() []T {
+	values := make([]T, 0, list.Size)
+	for node := list.Head.Next[0]; node != nil; node = node.Next[0] {
+		values = append(values, node.Value)
+	}
+	return values
+}
+
+// 节点在追踪中的标识
+func (list *SkipList[T]) label(node *SkipListNode[T]) string {
+	switch node {
+	case nil:
+		return "nil"
+	case list.Head:
+		return "head"
+	}
+	return nodeLabel(node.ID)
+}
+
+// 比较节点的值与 value，节点的值较小时返回负数
+func (list *SkipList[T]) compare(node *SkipListNode[T], level int, value T) int {
+	list.cost.Comparisons++
+	list.record(TraceStep{
+		Action: StepCompare,
+		Index:  intRef(level),
+		Value:  node.Value,
+		Node:   list.label(node),
+		Detail: fmt.Sprintf("第%d层：比较节点%s的值%v与%v", level, list.label(node), node.Value, value),
+	})
+	return list.kind.Compare(node.Value, value)
+}
+
+// 修改节点在第 level 层的后继
+func (list *SkipList[T]) setNext(node *SkipListNode[T], level int, next *SkipListNode[T]) {
+	node.Next[level] = next
+	list.cost.Writes++
+	list.record(TraceStep{
+		Action: StepSetNext,
+		Index:  intRef(level),
+		Node:   list.label(node),
+		Target: list.label(next),
+		Detail: fmt.Sprintf("%s.Next[%d] = %s", list.label(node), level, list.label(next)),
+	})
+}
+
+// 从头节点的最高层出发，在每一层向右走到最后一个小于 value 的节点后下降一层。
+// 返回每一层最后停留的节点（插入和删除时需要修改它们的后继）、第0层的下一个节点是否等于 value，以及经过的路径
+func (list *SkipList[T]) locate(value T) ([]*SkipListNode[T], bool, []SkipListStep) {
+	update := make([]*SkipListNode[T], list.MaxLevel)
+	for i := range update {
+		update[i] = list.Head
+	}
+	path := []SkipListStep{{Level: max(list.Level-1, 0), Node: list.Head.ID}}
+
+	found := false
+	current := list.Head
+	for level := list.Level - 1; level >= 0; level-- {
+		for next := current.Next[level]; next != nil; next = current.Next[level] {
+			order := list.compare(next, level, value)
+			if order >= 0 {
+				found = level == 0 && order == 0
+				break
+			}
+			current = next
+			path = append(path, SkipListStep{Level: level, Node: current.ID})
+			list.record(TraceStep{
+				Action: StepVisit,
+				Index:  intRef(level),
+				Value:  current.Value,
+				Node:   list.label(current),
+				Detail: fmt.Sprintf("第%d层：%v小于%v，沿第%d层前进到%s", level, current.Value, value, level, list.label(current)),
+			})
+		}
+		update[level] = current
+		if level > 0 {
+			path = append(path, SkipListStep{Level: level - 1, Node: current.ID})
+			list.record(TraceStep{
+				Action: StepDescend,
+				From:   intRef(level),
+				To:     intRef(level - 1),
+				Node:   list.label(current),
+				Target: list.label(current.Next[level]),
+				Detail: fmt.Sprintf("第%d层的下一个节点是%s，从%s下降到第%d层", level, list.label(current.Next[level]), list.label(current), level-1),
+			})
+		}
+	}
+	return update, found, path
+}
+
+// 抛硬币决定新节点的层数：从1层开始，每次以 Probability 的概率再提升一层，直到失败或达到最大层数
+func (list *SkipList[T]) randomLevel() int {
+	level := 1
+	for level < list.MaxLevel {
+		draw := list.rng.Float64()
+		list.Draws++
+		promoted := draw < list.Probability
+		detail := fmt.Sprintf("随机数%.4f ≥ %g，不再提升，新节点共%d层", draw, list.Probability, level)
+		if promoted {
+			detail = fmt.Sprintf("随机数%.4f < %g，提升到第%d层", draw, list.Probability, level)
+		}
+		list.record(TraceStep{
+			Action: StepPromote,
+			Index:  intRef(level),
+			Value:  promoted,
+			Detail: detail,
+		})
+		if !promoted {
+			break
+		}
+		level++
+	}
+	return level
+}
+
+// Search 查找 value，同时返回从最高层到第0层经过的节点；找到时路径以该元素的节点结束
+func (list *SkipList[T]) Search(value T) (bool, []SkipListStep) {
+	update, found, path := list.locate(value)
+	if found {
+		target := update[0].Next[0]
+		path = append(path, SkipListStep{Level: 0, Node: target.ID})
+		list.record(TraceStep{
+			Action: StepVisit,
+			Index:  intRef(0),
+			Value:  target.Value,
+			Node:   list.label(target),
+			Detail: fmt.Sprintf("第0层：%s的值等于%v，查找成功", list.label(target), value),
+		})
+	}
+	return found, path
+}
+
+// Insert 插入 value 并返回新节点的层数，元素已存在时返回 ErrDuplicate（不消耗随机数）
+func (list *SkipList[T]) Insert(value T) (int, error) {
+	update, found, _ := list.locate(value)
+	if found {
+		return 0, ErrDuplicate
+	}
+
+	level := list.randomLevel()
+	if level > list.Level {
+		list.record(TraceStep{
+			Action: StepCase,
+			From:   intRef(list.Level),
+			To:     intRef(level),
+			Detail: fmt.Sprintf("新节点有%d层，跳表的层数从%d增加到%d，新增的层从head开始", level, list.Level, level),
+		})
+		list.Level = level
+	}
+
+	node := &SkipListNode[T]{ID: list.nextID, Value: value, Next: make([]*SkipListNode[T], level)}
+	list.nextID++
+	list.record(TraceStep{
+		Action: StepCreate,
+		Value:  value,
+		Node:   list.label(node),
+		Detail: fmt.Sprintf("创建值为%v、共%d层的新节点%s", value, level, list.label(node)),
+	})
+	for i := range level {
+		list.setNext(node, i, update[i].Next[i])
+		list.setNext(update[i], i, node)
+	}
+	list.Size++
+	return level, nil
+}
+
+// Delete 删除 value，在它出现的每一层把前驱的后继改为它的后继；元素不存在时返回 ErrNotFound
+func (list *SkipList[T]) Delete(value T) error {
+	update, found, _ := list.locate(value)
+	if !found {
+		return ErrNotFound
+	}
+
+	target := update[0].Next[0]
+	for i := range target.Next {
+		list.setNext(update[i], i, target.Next[i])
+	}
+	list.record(TraceStep{
+		Action: StepFree,
+		Value:  target.Value,
+		Node:   list.label(target),
+		Detail: fmt.Sprintf("节点%s已从全部%d层中脱离", list.label(target), len(target.Next)),
+	})
+	list.Size--
+
+	for list.Level > 0 && list.Head.Next[list.Level-1] == nil {
+		list.Level--
+		list.record(TraceStep{
+			Action: StepCase,
+			From:   intRef(list.Level + 1),
+			To:     intRef(list.Level),
+			Detail: fmt.Sprintf("第%d层已空，跳表的层数减为%d", list.Level, list.Level),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,574 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// 排序算法
+const (
+	SortBubble    = "bubble"
+	SortSelection = "selection"
+	SortInsertion = "insertion"
+	SortMerge     = "merge"
+	SortQuick     = "quick"
+	SortHeap      = "heap"
+	SortShell     = "shell"
+	SortCounting  = "counting"
+	SortRadix     = "radix"
+)
+
+// 排序方向
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// 快速排序的基准选择方式
+const (
+	PivotFirst         = "first"
+	PivotLast          = "last"
+	PivotMiddle        = "middle"
+	PivotRandom        = "random"
+	PivotMedianOfThree = "median_of_three"
+)
+
+// 计数排序允许的最大取值范围，避免为稀疏数据分配过大的计数数组
+const maxCountingRange = 1 << 16
+
+// 基数排序的基数
+const radixBase = 10
+
+// SortOptions 排序参数
+type SortOptions struct {
+	Algorithm string `json:"algorithm"`
+	Order     string `json:"order"` // asc（默认）或 desc
+	Pivot     string `json:"pivot"` // 仅快速排序使用，默认 last
+	Seed      int64  `json:"seed"`  // 随机基准的种子，相同种子的过程可以复现
+}
+
+// SortInfo 实际使用的排序参数（已填充默认值）及算法是否稳定
+type SortInfo struct {
+	Algorithm string `json:"algorithm"`
+	Order     string `json:"order"`
+	Pivot     string `json:"pivot,omitempty"`
+	Stable    bool   `json:"stable"`
+}
+
+// 各元素类型的 arraySorter[T] 都实现的排序方法，供注册表按名称分发
+type sorter interface {
+	bubbleSort()
+	selectionSort()
+	insertionSort()
+	mergeSort()
+	quickSort()
+	heapSort()
+	shellSort()
+	countingSort()
+	radixSort()
+}
+
+// 已登记的排序算法
+type sortAlgorithm struct {
+	stable  bool
+	integer bool // 只适用于整数元素
+	run     func(s sorter)
+}
+
+// 已注册的排序算法，新增算法只需在此登记
+var sortAlgorithms = map[string]sortAlgorithm{
+	SortBubble:    {stable: true, run: sorter.bubbleSort},
+	SortSelection: {stable: false, run: sorter.selectionSort},
+	SortInsertion: {stable: true, run: sorter.insertionSort},
+	SortMerge:     {stable: true, run: sorter.mergeSort},
+	SortQuick:     {stable: false, run: sorter.quickSort},
+	SortHeap:      {stable: false, run: sorter.heapSort},
+	SortShell:     {stable: false, run: sorter.shellSort},
+	SortCounting:  {stable: true, integer: true, run: sorter.countingSort},
+	SortRadix:     {stable: true, integer: true, run: sorter.radixSort},
+}
+
+var pivotStrategies = []string{PivotFirst, PivotLast, PivotMiddle, PivotRandom, PivotMedianOfThree}
+
+// SortAlgorithmNames 按字母顺序返回所有已注册的排序算法名称
+func SortAlgorithmNames() []string {
+	names := make([]string, 0, len(sortAlgorithms))
+	for name := range sortAlgorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 填充默认值并校验排序参数中与元素无关的部分
+func (options *SortOptions) validate() error {
+	if _, ok := sortAlgorithms[options.Algorithm]; !ok {
+		return fmt.Errorf("排序算法必须是%s之一", strings.Join(SortAlgorithmNames(), "、"))
+	}
+
+	if options.Order == "" {
+		options.Order = OrderAsc
+	}
+	if options.Order != OrderAsc && options.Order != OrderDesc {
+		return errors.New("排序方向必须是asc或desc")
+	}
+
+	if options.Algorithm == SortQuick {
+		if options.Pivot == "" {
+			options.Pivot = PivotLast
+		}
+		if !slices.Contains(pivotStrategies, options.Pivot) {
+			return fmt.Errorf("基准选择方式必须是%s之一", strings.Join(pivotStrategies, "、"))
+		}
+	} else {
+		options.Pivot = ""
+	}
+
+	return nil
+}
+
+// 检查排序参数是否适用于数组的元素类型和取值
+func (array *Array[T]) validateSort(options *SortOptions) error {
+	if err := options.validate(); err != nil {
+		return err
+	}
+
+	if sortAlgorithms[options.Algorithm].integer && array.kind.Integer == nil {
+		return fmt.Errorf("%s排序只支持%s类型元素", options.Algorithm, ElementInt)
+	}
+
+	if options.Algorithm == SortCounting && array.Size > 0 {
+		lo, hi := array.valueRange()
+		if uint64(hi)-uint64(lo) >= maxCountingRange {
+			return fmt.Errorf("元素取值范围超过%d，不适合计数排序", maxCountingRange)
+		}
+	}
+
+	return nil
+}
+
+// 返回整数元素的最小值和最大值
+func (array *Array[T]) valueRange() (int, int) {
+	lo := array.kind.Integer(array.Elements[0])
+	hi := lo
+	for _, element := range array.Elements[1:array.Size] {
+		value := array.kind.Integer(element)
+		lo, hi = min(lo, value), max(hi, value)
+	}
+	return lo, hi
+}
+
+// Sort 按参数排序数组，比较、交换、写入都计入计数和执行追踪，返回实际使用的参数
+func (array *Array[T]) Sort(options SortOptions) (*SortInfo, error) {
+	if err := array.validateSort(&options); err != nil {
+		return nil, err
+	}
+
+	algorithm := sortAlgorithms[options.Algorithm]
+	algorithm.run(newArraySorter(array, options))
+	return &SortInfo{
+		Algorithm: options.Algorithm,
+		Order:     options.Order,
+		Pivot:     options.Pivot,
+		Stable:    algorithm.stable,
+	}, nil
+}
+
+// 在数组上执行排序，比较、交换、写入都计入数组的代价统计和执行追踪
+type arraySorter[T any] struct {
+	array *Array[T]
+	desc  bool
+	pivot string
+	rng   *rand.Rand
+}
+
+func newArraySorter[T any](array *Array[T], options SortOptions) *arraySorter[T] {
+	return &arraySorter[T]{
+		array: array,
+		desc:  options.Order == OrderDesc,
+		pivot: options.Pivot,
+		rng:   rand.New(rand.NewSource(options.Seed)),
+	}
+}
+
+// 排序方向
+func (s *arraySorter[T]) order() string {
+	if s.desc {
+		return OrderDesc
+	}
+	return OrderAsc
+}
+
+// a 是否应严格排在 b 之前
+func (s *arraySorter[T]) before(a, b T) bool {
+	order := s.array.kind.Compare(a, b)
+	if s.desc {
+		return order > 0
+	}
+	return order < 0
+}
+
+// 比较索引 i 与 j 处的元素，i 处的元素应严格排在 j 之前时返回 true
+func (s *arraySorter[T]) less(i, j int) bool {
+	a, b := s.array.Elements[i], s.array.Elements[j]
+	s.array.cost.Comparisons++
+	s.array.record(TraceStep{
+		Action: StepCompare,
+		From:   intRef(i),
+		To:     intRef(j),
+		Value:  a,
+		Detail: fmt.Sprintf("比较索引%d处的%v与索引%d处的%v", i, a, j, b),
+	})
+	return s.before(a, b)
+}
+
+// 比较已从数组中取出的两个值，from、to 为它们原先所在的索引
+func (s *arraySorter[T]) lessValues(a, b T, from, to int) bool {
+	s.array.cost.Comparisons++
+	s.array.record(TraceStep{
+		Action: StepCompare,
+		From:   intRef(from),
+		To:     intRef(to),
+		Value:  a,
+		Detail: fmt.Sprintf("比较%v（来自索引%d）与%v（来自索引%d）", a, from, b, to),
+	})
+	return s.before(a, b)
+}
+
+// 交换索引 i 与 j 处的元素
+func (s *arraySorter[T]) swap(i, j int) {
+	elements := s.array.Elements
+	elements[i], elements[j] = elements[j], elements[i]
+	s.array.cost.Swaps++
+	s.array.cost.Writes += 2
+	s.array.record(TraceStep{
+		Action: StepSwap,
+		From:   intRef(i),
+		To:     intRef(j),
+		Value:  elements[j],
+		Detail: fmt.Sprintf("交换索引%d处的%v与索引%d处的%v", i, elements[j], j, elements[i]),
+	})
+}
+
+// 冒泡排序：相邻逆序即交换，一轮没有交换时提前结束
+func (s *arraySorter[T]) bubbleSort() {
+	n := s.array.Size
+	for i := 0; i < n-1; i++ {
+		swapped := false
+		for j := 0; j < n-1-i; j++ {
+			if s.less(j+1, j) {
+				s.swap(j, j+1)
+				swapped = true
+			}
+		}
+		if !swapped {
+			return
+		}
+	}
+}
+
+// 选择排序：每轮从未排序部分选出最前的元素换到前面
+func (s *arraySorter[T]) selectionSort() {
+	n := s.array.Size
+	for i := 0; i < n-1; i++ {
+		best := i
+		for j := i + 1; j < n; j++ {
+			if s.less(j, best) {
+				best = j
+			}
+		}
+		if best != i {
+			s.swap(i, best)
+		}
+	}
+}
+
+// 插入排序：间隔为1的间隔插入排序
+func (s *arraySorter[T]) insertionSort() {
+	s.gapInsertion(1)
+}
+
+// 希尔排序：间隔从 n/2 开始逐次减半，最后一轮即插入排序
+func (s *arraySorter[T]) shellSort() {
+	for gap := s.array.Size / 2; gap > 0; gap /= 2 {
+		s.gapInsertion(gap)
+	}
+}
+
+// 对间隔为 gap 的各子序列做插入排序：取出元素，较大的元素依次后移，再写入空位
+func (s *arraySorter[T]) gapInsertion(gap int) {
+	for i := gap; i < s.array.Size; i++ {
+		value := s.array.read(i)
+		j := i
+		for j >= gap && s.lessValues(value, s.array.Elements[j-gap], i, j-gap) {
+			s.array.shift(j-gap, j)
+			j -= gap
+		}
+		if j != i {
+			s.array.write(j, value)
+		}
+	}
+}
+
+// 归并排序：自顶向下递归
+func (s *arraySorter[T]) mergeSort() {
+	s.mergeRange(0, s.array.Size-1)
+}
+
+// 排序闭区间 [lo, hi]
+func (s *arraySorter[T]) mergeRange(lo, hi int) {
+	if lo >= hi {
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	s.mergeRange(lo, mid)
+	s.mergeRange(mid+1, hi)
+
+	// 将两段读入缓冲区，再依次把较前的元素写回
+	buffer := make([]T, hi-lo+1)
+	for k := lo; k <= hi; k++ {
+		buffer[k-lo] = s.array.read(k)
+	}
+
+	i, j := 0, mid+1-lo
+	for k := lo; k <= hi; k++ {
+		// 右段元素严格更前时才取右段，保证稳定
+		if i > mid-lo || (j <= hi-lo && s.lessValues(buffer[j], buffer[i], lo+j, lo+i)) {
+			s.array.write(k, buffer[j])
+			j++
+		} else {
+			s.array.write(k, buffer[i])
+			i++
+		}
+	}
+}
+
+// 快速排序
+func (s *arraySorter[T]) quickSort() {
+	s.quickRange(0, s.array.Size-1)
+}
+
+// 排序闭区间 [lo, hi]：较短的一段递归处理，较长的一段继续循环，递归深度不超过 O(log n)
+func (s *arraySorter[T]) quickRange(lo, hi int) {
+	for lo < hi {
+		p := s.partition(lo, hi)
+		if p-lo < hi-p {
+			s.quickRange(lo, p-1)
+			lo = p + 1
+		} else {
+			s.quickRange(p+1, hi)
+			hi = p - 1
+		}
+	}
+}
+
+// 按基准选择方式返回基准的索引
+func (s *arraySorter[T]) choosePivot(lo, hi int) int {
+	mid := lo + (hi-lo)/2
+	switch s.pivot {
+	case PivotFirst:
+		return lo
+	case PivotMiddle:
+		return mid
+	case PivotRandom:
+		return lo + s.rng.Intn(hi-lo+1)
+	case PivotMedianOfThree:
+		// 对首、中、尾三个元素排序，中位数落在 mid
+		if s.less(mid, lo) {
+			s.swap(lo, mid)
+		}
+		if s.less(hi, lo) {
+			s.swap(lo, hi)
+		}
+		if s.less(hi, mid) {
+			s.swap(mid, hi)
+		}
+		return mid
+	}
+	return hi
+}
+
+// Lomuto 划分：基准换到末尾，排在基准之前的元素依次换到前部，返回基准的最终位置
+func (s *arraySorter[T]) partition(lo, hi int) int {
+	p := s.choosePivot(lo, hi)
+	s.array.record(TraceStep{
+		Action: StepPivot,
+		Index:  intRef(p),
+		Value:  s.array.Elements[p],
+		Detail: fmt.Sprintf("选择索引%d处的%v作为基准，划分区间[%d, %d]", p, s.array.Elements[p], lo, hi),
+	})
+	if p != hi {
+		s.swap(p, hi)
+	}
+
+	store := lo
+	for i := lo; i < hi; i++ {
+		if s.less(i, hi) {
+			if i != store {
+				s.swap(i, store)
+			}
+			store++
+		}
+	}
+	if store != hi {
+		s.swap(store, hi)
+	}
+	return store
+}
+
+// 堆排序：先建堆，再反复把堆顶换到末尾；升序使用大顶堆，降序使用小顶堆
+func (s *arraySorter[T]) heapSort() {
+	n := s.array.Size
+	for i := n/2 - 1; i >= 0; i-- {
+		s.siftDown(i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		s.swap(0, end)
+		s.siftDown(0, end)
+	}
+}
+
+// 在前 n 个元素构成的堆中下沉索引 i 处的元素
+func (s *arraySorter[T]) siftDown(i, n int) {
+	for {
+		top := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && s.less(top, left) {
+			top = left
+		}
+		if right < n && s.less(top, right) {
+			top = right
+		}
+		if top == i {
+			return
+		}
+		s.swap(i, top)
+		i = top
+	}
+}
+
+// 在堆中上浮索引 i 处的元素，直到父节点不再排在它之后
+func (s *arraySorter[T]) siftUp(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !s.less(parent, i) {
+			return
+		}
+		s.swap(parent, i)
+		i = parent
+	}
+}
+
+// 计数排序：统计每个取值的出现次数，前缀和得到各取值的起始位置，再按原顺序分配后写回
+func (s *arraySorter[T]) countingSort() {
+	n := s.array.Size
+	if n == 0 {
+		return
+	}
+
+	lo, hi := s.array.valueRange()
+	counts := make([]int, hi-lo+1)
+	for i := 0; i < n; i++ {
+		value := s.array.read(i)
+		b := s.bucket(value, lo, hi)
+		counts[b]++
+		s.array.record(TraceStep{
+			Action: StepCount,
+			Index:  intRef(i),
+			Value:  value,
+			Detail: fmt.Sprintf("取值%v的计数加1，当前为%d", value, counts[b]),
+		})
+	}
+
+	start := 0
+	for b := range counts {
+		counts[b], start = start, start+counts[b]
+	}
+	buffer := make([]T, n)
+	for i := 0; i < n; i++ {
+		value := s.array.Elements[i]
+		b := s.bucket(value, lo, hi)
+		buffer[counts[b]] = value
+		counts[b]++
+	}
+	for i, value := range buffer {
+		s.array.write(i, value)
+	}
+}
+
+// 计数排序中元素所在的桶，降序时桶的顺序反转
+func (s *arraySorter[T]) bucket(value T, lo, hi int) int {
+	if s.desc {
+		return hi - s.array.kind.Integer(value)
+	}
+	return s.array.kind.Integer(value) - lo
+}
+
+// 基数排序：LSD，每一位做一次稳定的计数分配；负数先减去最小值转为非负的键
+func (s *arraySorter[T]) radixSort() {
+	n := s.array.Size
+	if n == 0 {
+		return
+	}
+
+	lo, hi := s.array.valueRange()
+	maxKey := uint64(hi) - uint64(lo)
+	buffer := make([]T, n)
+
+	for exp := uint64(1); ; exp *= radixBase {
+		// 统计每个桶的元素数，前缀和得到各桶在缓冲区中的起始位置
+		var counts [radixBase]int
+		for i := 0; i < n; i++ {
+			value := s.array.read(i)
+			digit := s.digit(value, lo, exp)
+			counts[digit]++
+			s.array.record(TraceStep{
+				Action: StepCount,
+				Index:  intRef(i),
+				Value:  value,
+				Detail: fmt.Sprintf("按第%d位分配：%v放入桶%d", digitPosition(exp), value, digit),
+			})
+		}
+
+		start := 0
+		for d := range counts {
+			counts[d], start = start, start+counts[d]
+		}
+		for i := 0; i < n; i++ {
+			value := s.array.Elements[i]
+			digit := s.digit(value, lo, exp)
+			buffer[counts[digit]] = value
+			counts[digit]++
+		}
+		for i, value := range buffer {
+			s.array.write(i, value)
+		}
+
+		if maxKey/exp < radixBase {
+			return
+		}
+	}
+}
+
+// 元素的键在 exp 所在位上的数字，降序时取反使大的数字排在前面
+func (s *arraySorter[T]) digit(value T, lo int, exp uint64) int {
+	digit := int((uint64(s.array.kind.Integer(value)) - uint64(lo)) / exp % radixBase)
+	if s.desc {
+		digit = radixBase - 1 - digit
+	}
+	return digit
+}
+
+// exp 对应的位序号，个位为1
+func digitPosition(exp uint64) int {
+	position := 1
+	for ; exp >= radixBase; exp /= radixBase {
+		position++
+	}
+	return position
+}
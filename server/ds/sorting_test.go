@@ -0,0 +1,103 @@
+package ds
+
+import (
+	"slices"
+	"testing"
+)
+
+func newIntArray(t *testing.T, values []int) *Array[int] {
+	t.Helper()
+
+	array, err := NewArray(ArrayConfig{Capacity: max(len(values), 1)}, Ints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range values {
+		if _, err := array.Append(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return array
+}
+
+func TestSortAlgorithms(t *testing.T) {
+	inputs := [][]int{
+		{},
+		{7},
+		{5, 3, 9, 1, 3, 8, 2, 7},
+		{1, 2, 3, 4, 5},
+		{5, 4, 3, 2, 1},
+		{-4, 12, 0, -4, 105, 33, 7},
+	}
+
+	for _, algorithm := range SortAlgorithmNames() {
+		for _, order := range []string{OrderAsc, OrderDesc} {
+			t.Run(algorithm+"/"+order, func(t *testing.T) {
+				for _, input := range inputs {
+					array := newIntArray(t, input)
+					info, err := array.Sort(SortOptions{Algorithm: algorithm, Order: order, Seed: 1})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if info.Algorithm != algorithm || info.Order != order {
+						t.Fatalf("返回的参数 %+v 与请求不符", *info)
+					}
+
+					want := slices.Clone(input)
+					slices.Sort(want)
+					if order == OrderDesc {
+						slices.Reverse(want)
+					}
+					if got := array.Values(); !slices.Equal(got, want) {
+						t.Fatalf("%v 排序后为 %v，期望 %v", input, got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestQuickSortPivots(t *testing.T) {
+	input := []int{5, 3, 9, 1, 3, 8, 2, 7}
+	want := slices.Sorted(slices.Values(input))
+
+	for _, pivot := range pivotStrategies {
+		t.Run(pivot, func(t *testing.T) {
+			array := newIntArray(t, input)
+			if _, err := array.Sort(SortOptions{Algorithm: SortQuick, Pivot: pivot, Seed: 42}); err != nil {
+				t.Fatal(err)
+			}
+			if got := array.Values(); !slices.Equal(got, want) {
+				t.Fatalf("排序后为 %v，期望 %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSortRejectsInvalidOptions(t *testing.T) {
+	strings, err := NewArray(ArrayConfig{}, Strings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"b", "a"} {
+		if _, err := strings.Append(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options SortOptions
+	}{
+		{"unknown algorithm", SortOptions{Algorithm: "bogo"}},
+		{"unknown order", SortOptions{Algorithm: SortMerge, Order: "up"}},
+		{"unknown pivot", SortOptions{Algorithm: SortQuick, Pivot: "any"}},
+		{"counting on strings", SortOptions{Algorithm: SortCounting}},
+		{"radix on strings", SortOptions{Algorithm: SortRadix}},
+	}
+	for _, tt := range tests {
+		if _, err := strings.Sort(tt.options); err == nil {
+			t.Fatalf("%s: 应返回错误", tt.name)
+		}
+	}
+}
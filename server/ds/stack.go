@@ -0,0 +1,156 @@
+package ds
+
+import (
+	"errors"
+	"slices"
+)
+
+// 栈、队列等抽象数据类型的底层结构
+const (
+	BackingArray = "array" // 动态数组
+	BackingList  = "list"  // 链表
+)
+
+// Stack 栈，底层为动态数组或单链表：数组以末尾为栈顶，链表以头节点为栈顶，压栈和出栈都是 O(1)
+type Stack[T any] struct {
+	Backing string `json:"backing"`
+
+	array *Array[T]
+	list  *LinkedList[T]
+}
+
+// NewStack 创建指定底层结构的空栈，config 只在底层为数组时使用
+func NewStack[T any](backing string, config ArrayConfig, kind *ElementType[T]) (*Stack[T], error) {
+	if backing == "" {
+		backing = BackingArray
+	}
+
+	stack := &Stack[T]{Backing: backing}
+	switch backing {
+	case BackingArray:
+		array, err := NewArray(config, kind)
+		if err != nil {
+			return nil, err
+		}
+		stack.array = array
+	case BackingList:
+		list, err := NewLinkedList(ListSingle, kind)
+		if err != nil {
+			return nil, err
+		}
+		stack.list = list
+	default:
+		return nil, errors.New("底层结构必须是array或list")
+	}
+	return stack, nil
+}
+
+// RestoreStack 按自底向顶的值序列重建栈，不保留追踪
+func RestoreStack[T any](backing string, config ArrayConfig, values []T, kind *ElementType[T]) (*Stack[T], error) {
+	stack, err := NewStack(backing, config, kind)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		if _, err := stack.Push(value); err != nil {
+			return nil, err
+		}
+	}
+	stack.Begin()
+	return stack, nil
+}
+
+// Array 底层动态数组，底层为链表时返回 nil
+func (stack *Stack[T]) Array() *Array[T] {
+	return stack.array
+}
+
+// List 底层链表，底层为数组时返回 nil
+func (stack *Stack[T]) List() *LinkedList[T] {
+	return stack.list
+}
+
+// Kind 栈的元素类型
+func (stack *Stack[T]) Kind() *ElementType[T] {
+	if stack.array != nil {
+		return stack.array.Kind()
+	}
+	return stack.list.Kind()
+}
+
+// Recorder 底层结构的计数和追踪
+func (stack *Stack[T]) Recorder() *Recorder {
+	if stack.array != nil {
+		return &stack.array.Recorder
+	}
+	return &stack.list.Recorder
+}
+
+// Begin 开始一次新操作，清空底层结构的计数和追踪
+func (stack *Stack[T]) Begin() {
+	if stack.array != nil {
+		stack.array.Begin()
+	} else {
+		stack.list.Begin()
+	}
+}
+
+// Len 栈中的元素个数
+func (stack *Stack[T]) Len() int {
+	if stack.array != nil {
+		return stack.array.Size
+	}
+	return stack.list.Size
+}
+
+// Push 压栈；底层数组容量用尽且不允许扩容时返回 ErrFull
+func (stack *Stack[T]) Push(value T) (*ResizeEvent, error) {
+	if stack.array != nil {
+		return stack.array.Append(value)
+	}
+	return nil, stack.list.Prepend(value)
+}
+
+// Pop 出栈并返回栈顶元素，空栈返回 ErrEmpty
+func (stack *Stack[T]) Pop() (T, *ResizeEvent, error) {
+	if stack.Len() == 0 {
+		var zero T
+		return zero, nil, ErrEmpty
+	}
+	if stack.array != nil {
+		return stack.array.RemoveAt(stack.array.Size - 1)
+	}
+	value, err := stack.list.RemoveAt(0)
+	return value, nil, err
+}
+
+// Peek 读取栈顶元素，空栈返回 ErrEmpty
+func (stack *Stack[T]) Peek() (T, error) {
+	if stack.Len() == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	if stack.array != nil {
+		return stack.array.Get(stack.array.Size - 1)
+	}
+	return stack.list.Get(0)
+}
+
+// Clear 清空栈，底层数组保留当前容量
+func (stack *Stack[T]) Clear() {
+	if stack.array != nil {
+		stack.array.Clear()
+	} else {
+		stack.list.Clear()
+	}
+}
+
+// Values 自底向顶返回所有元素
+func (stack *Stack[T]) Values() []T {
+	if stack.array != nil {
+		return stack.array.Values()
+	}
+	values := stack.list.Values()
+	slices.Reverse(values)
+	return values
+}
@@ -0,0 +1,136 @@
+package ds
+
+import "fmt"
+
+// 追踪步骤类型
+const (
+	// 数组
+	StepRead     = "read"     // 读取下标处的元素
+	StepCompare  = "compare"  // 比较元素与目标值
+	StepWrite    = "write"    // 写入元素
+	StepShift    = "shift"    // 元素从 from 移动到 to
+	StepAllocate = "allocate" // 分配新的底层存储
+	StepCopy     = "copy"     // 重新分配时复制元素
+
+	// 环形缓冲区
+	StepMoveHead = "move_head" // 队首下标从 from 移动到 to
+	StepMoveTail = "move_tail" // 队尾（下一个写入位置）下标从 from 移动到 to
+
+	// 查找
+	StepProbe  = "probe"  // 探查下标处的元素并与目标值比较
+	StepNarrow = "narrow" // 查找范围缩小为 [lo, hi]
+
+	// 排序
+	StepSwap  = "swap"  // 交换 from 与 to 处的元素
+	StepPivot = "pivot" // 选定快速排序的基准元素
+	StepCount = "count" // 计数排序或基数排序统计元素所在的桶
+
+	// 链表
+	StepCreate  = "create"   // 创建新节点
+	StepVisit   = "visit"    // 游标移动到节点；图算法中表示开始处理顶点
+	StepSetNext = "set_next" // 修改节点的 Next 指针
+	StepSetPrev = "set_prev" // 修改节点的 Prev 指针
+	StepSetHead = "set_head" // 修改链表的 Head
+	StepSetTail = "set_tail" // 修改链表的 Tail
+	StepFree    = "free"     // 节点脱离链表
+	StepCursor  = "cursor"   // 多个游标（如 prev/current/next）移动到新位置
+	StepRecurse = "recurse"  // 进入一层递归调用
+	StepReturn  = "return"   // 从一层递归调用返回
+
+	// 树
+	StepSetLeft  = "set_left"  // 修改节点的左子节点
+	StepSetRight = "set_right" // 修改节点的右子节点
+	StepSetRoot  = "set_root"  // 修改树的根节点
+	StepCase     = "case"      // 说明本次操作进入的情况分支，如删除的三种情况
+
+	// 平衡树
+	StepHeight          = "height"            // AVL 节点的高度更新，detail 中给出新的平衡因子
+	StepRotateLeft      = "rotate_left"       // 以节点为轴左旋
+	StepRotateRight     = "rotate_right"      // 以节点为轴右旋
+	StepRotateLeftRight = "rotate_left_right" // 先左旋左子节点再右旋节点，随后记录两次单旋
+	StepRotateRightLeft = "rotate_right_left" // 先右旋右子节点再左旋节点，随后记录两次单旋
+	StepRecolor         = "recolor"           // 红黑树节点改变颜色，value 为新颜色
+
+	// 哈希表
+	StepHash      = "hash"      // 计算键的哈希值，index 为初始桶（槽位）
+	StepTombstone = "tombstone" // 开放寻址删除键后在槽位留下墓碑
+	StepRehash    = "rehash"    // 按新容量重建哈希表，from/to 为新旧容量
+
+	// 图
+	StepEdge     = "edge"      // 检查一条边，node 为起点，target 为终点，value 为权重
+	StepDiscover = "discover"  // 顶点首次被发现，加入边界（队列、栈或候选集合）
+	StepRelax    = "relax"     // 经由一条边缩短了到终点的距离，value 为新距离
+	StepTreeEdge = "tree_edge" // 边加入遍历树或生成树
+
+	// 字典树
+	StepSetChild = "set_child" // 修改节点在 index 处的子边，value 为边上的字符，target 为新指向
+	StepTerminal = "terminal"  // 修改节点的单词结尾标记，value 为新标记
+
+	// 并查集
+	StepMakeSet  = "make_set" // 加入新元素，父指针指向自己
+	StepLink     = "link"     // 合并时把一棵树的根挂到另一棵树的根下，from/to 为旧/新父节点下标
+	StepCompress = "compress" // 路径压缩把节点直接挂到根下，from/to 为旧/新父节点下标
+
+	// 线段树与树状数组
+	StepLazy     = "lazy"      // 区间更新整体作用于节点，value 为节点新值；内部节点同时累加懒标记
+	StepPushDown = "push_down" // 把节点的懒标记下传给两个子节点，value 为下传的增量
+
+	// 跳表
+	StepPromote = "promote" // 插入时抛硬币决定是否把新节点提升到更高一层，index 为当前层数，value 为是否提升
+	StepDescend = "descend" // 查找时从 from 层下降到 to 层，target 为当前节点在 from 层的后继
+)
+
+// TraceStep 操作执行过程中的一个微步骤
+type TraceStep struct {
+	Action string `json:"action"`
+	Index  *int   `json:"index,omitempty"`  // 涉及的数组下标或链表位置
+	From   *int   `json:"from,omitempty"`   // 移动或复制的源下标
+	To     *int   `json:"to,omitempty"`     // 移动或复制的目标下标
+	Low    *int   `json:"lo,omitempty"`     // 查找范围的下界
+	High   *int   `json:"hi,omitempty"`     // 查找范围的上界
+	Value  any    `json:"value,omitempty"`  // 涉及的元素值
+	Node   string `json:"node,omitempty"`   // 被访问或修改的节点，如 node[2]、new
+	Target string `json:"target,omitempty"` // 指针的新指向，如 node[3]、nil
+	// 各游标指向的节点，如 {"prev": "nil", "current": "node[0]", "next": "node[1]"}
+	Cursors map[string]string `json:"cursors,omitempty"`
+	// 图算法在这一步之后的边界：BFS 的队列、DFS 的递归栈、Dijkstra 和 Prim 的候选顶点等
+	Frontier []string `json:"frontier,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// 返回整数的指针，便于填写可选的下标字段
+func intRef(i int) *int {
+	return &i
+}
+
+// 操作开始前链表中第 i 个节点的标识
+func nodeLabel(i int) string {
+	return fmt.Sprintf("node[%d]", i)
+}
+
+// Recorder 记录最近一次操作的基本操作计数和执行追踪，嵌入到各数据结构中
+type Recorder struct {
+	cost  OperationCost
+	trace []TraceStep
+}
+
+// Begin 开始一次新操作，清空计数和追踪
+func (r *Recorder) Begin() {
+	r.cost = OperationCost{}
+	r.trace = make([]TraceStep, 0)
+}
+
+// Cost 本次操作到目前为止的计数
+func (r *Recorder) Cost() OperationCost {
+	return r.cost
+}
+
+// Trace 本次操作到目前为止的追踪步骤
+func (r *Recorder) Trace() []TraceStep {
+	return r.trace
+}
+
+// 追加一个追踪步骤
+func (r *Recorder) record(step TraceStep) {
+	r.trace = append(r.trace, step)
+}
@@ -0,0 +1,584 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 树的类型
+const (
+	TreeBST      = "bst"       // 不做平衡的二叉搜索树
+	TreeAVL      = "avl"       // AVL 树：任一节点左右子树高度差不超过1
+	TreeRedBlack = "red_black" // 红黑树：根和空节点为黑，红节点的子节点为黑，各路径黑节点数相同
+)
+
+// 红黑树节点颜色
+const (
+	ColorRed   = "red"
+	ColorBlack = "black"
+)
+
+// 删除有两个子节点的节点时用来替代它的节点
+const (
+	ReplaceSuccessor   = "successor"   // 中序后继：右子树中的最小节点
+	ReplacePredecessor = "predecessor" // 中序前驱：左子树中的最大节点
+)
+
+// 遍历顺序
+const (
+	TraverseInOrder    = "inorder"
+	TraversePreOrder   = "preorder"
+	TraversePostOrder  = "postorder"
+	TraverseLevelOrder = "levelorder"
+)
+
+// TreeNode 二叉搜索树节点，ID 在创建时分配且不随旋转等调整改变，用于在各次操作之间对应同一个节点
+type TreeNode[T any] struct {
+	ID     int
+	Value  T
+	Left   *TreeNode[T]
+	Right  *TreeNode[T]
+	Parent *TreeNode[T]
+	Height int    // 仅 AVL 树维护：以该节点为根的子树高度，叶节点为1
+	Color  string // 仅红黑树使用
+}
+
+// 子树高度，空节点为0
+func height[T any](node *TreeNode[T]) int {
+	if node == nil {
+		return 0
+	}
+	return node.Height
+}
+
+// BalanceFactor AVL 树中左子树高度减右子树高度
+func (node *TreeNode[T]) BalanceFactor() int {
+	return height(node.Left) - height(node.Right)
+}
+
+// 是否为红节点，空节点视为黑色
+func isRed[T any](node *TreeNode[T]) bool {
+	return node != nil && node.Color == ColorRed
+}
+
+// SavedTreeNode 持久化的节点，按先序排列即可唯一确定一棵二叉搜索树的形状；红黑树另存颜色
+type SavedTreeNode[T any] struct {
+	ID    int    `json:"id"`
+	Value T      `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+// TreeState 树中与元素类型无关的状态
+type TreeState struct {
+	Type string `json:"type"`
+	Size int    `json:"size"`
+
+	Recorder `json:"-"`
+}
+
+// Tree 二叉搜索树，元素不重复；每次指针改写都记录到执行追踪中
+type Tree[T any] struct {
+	TreeState
+	Root *TreeNode[T] `json:"-"`
+
+	nextID int
+	kind   *ElementType[T]
+}
+
+// NewTree 创建指定类型的空树，treeType 为空时创建普通二叉搜索树
+func NewTree[T any](treeType string, kind *ElementType[T]) (*Tree[T], error) {
+	if treeType == "" {
+		treeType = TreeBST
+	}
+	if treeType != TreeBST && treeType != TreeAVL && treeType != TreeRedBlack {
+		return nil, errors.New("树类型必须是bst、avl或red_black")
+	}
+
+	tree := &Tree[T]{kind: kind}
+	tree.Type = treeType
+	tree.Begin()
+	return tree, nil
+}
+
+// RestoreTree 按先序排列的节点逐个挂到查找路径的末端，重建出原来形状的树，不记录追踪；
+// 红黑树沿用保存的颜色，AVL 树按形状重新计算高度
+func RestoreTree[T any](treeType string, nodes []SavedTreeNode[T], kind *ElementType[T]) (*Tree[T], error) {
+	tree, err := NewTree(treeType, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, saved := range nodes {
+		node := &TreeNode[T]{ID: saved.ID, Value: saved.Value, Color: saved.Color}
+		tree.nextID = max(tree.nextID, saved.ID+1)
+		tree.Size++
+
+		if tree.Root == nil {
+			tree.Root = node
+			continue
+		}
+		parent := tree.Root
+		for {
+			next := &parent.Right
+			if kind.Compare(node.Value, parent.Value) < 0 {
+				next = &parent.Left
+			}
+			if *next == nil {
+				*next = node
+				node.Parent = parent
+				break
+			}
+			parent = *next
+		}
+	}
+
+	if treeType == TreeAVL {
+		var restoreHeight func(node *TreeNode[T]) int
+		restoreHeight = func(node *TreeNode[T]) int {
+			if node == nil {
+				return 0
+			}
+			node.Height = 1 + max(restoreHeight(node.Left), restoreHeight(node.Right))
+			return node.Height
+		}
+		restoreHeight(tree.Root)
+	}
+
+	tree.Begin()
+	return tree, nil
+}
+
+// Kind 树的元素类型
+func (tree *Tree[T]) Kind() *ElementType[T] {
+	return tree.kind
+}
+
+// Saved 按先序返回所有节点，供持久化后用 RestoreTree 重建
+func (tree *Tree[T]) Saved() []SavedTreeNode[T] {
+	nodes := make([]SavedTreeNode[T], 0, tree.Size)
+	var walk func(node *TreeNode[T])
+	walk = func(node *TreeNode[T]) {
+		if node == nil {
+			return
+		}
+		nodes = append(nodes, SavedTreeNode[T]{ID: node.ID, Value: node.Value, Color: node.Color})
+		walk(node.Left)
+		walk(node.Right)
+	}
+	walk(tree.Root)
+	return nodes
+}
+
+// 节点在追踪中的标识
+func (tree *Tree[T]) label(node *TreeNode[T]) string {
+	if node == nil {
+		return "nil"
+	}
+	return nodeLabel(node.ID)
+}
+
+// 创建新节点，AVL 树的新节点高度为1，红黑树的新节点为红色
+func (tree *Tree[T]) newNode(value T) *TreeNode[T] {
+	node := &TreeNode[T]{ID: tree.nextID, Value: value}
+	tree.nextID++
+	switch tree.Type {
+	case TreeAVL:
+		node.Height = 1
+	case TreeRedBlack:
+		node.Color = ColorRed
+	}
+	tree.record(TraceStep{
+		Action: StepCreate,
+		Value:  value,
+		Node:   tree.label(node),
+		Detail: fmt.Sprintf("创建值为%v的新节点%s", value, tree.label(node)),
+	})
+	return node
+}
+
+// 访问节点
+func (tree *Tree[T]) visit(node *TreeNode[T]) {
+	tree.record(TraceStep{
+		Action: StepVisit,
+		Value:  node.Value,
+		Node:   tree.label(node),
+		Detail: fmt.Sprintf("访问节点%s，值为%v", tree.label(node), node.Value),
+	})
+}
+
+// 比较 value 与节点的值，value 较小时返回负数
+func (tree *Tree[T]) compare(value T, node *TreeNode[T]) int {
+	tree.cost.Comparisons++
+	tree.record(TraceStep{
+		Action: StepCompare,
+		Value:  node.Value,
+		Node:   tree.label(node),
+		Detail: fmt.Sprintf("比较%v与节点%s的值%v", value, tree.label(node), node.Value),
+	})
+	return tree.kind.Compare(value, node.Value)
+}
+
+// 说明进入的情况分支
+func (tree *Tree[T]) explain(node *TreeNode[T], detail string) {
+	tree.record(TraceStep{
+		Action: StepCase,
+		Node:   tree.label(node),
+		Detail: detail,
+	})
+}
+
+// 修改节点的左子节点，并让子节点的 Parent 指回该节点
+func (tree *Tree[T]) setLeft(node, child *TreeNode[T]) {
+	node.Left = child
+	if child != nil {
+		child.Parent = node
+	}
+	tree.record(TraceStep{
+		Action: StepSetLeft,
+		Node:   tree.label(node),
+		Target: tree.label(child),
+		Detail: fmt.Sprintf("%s.Left = %s", tree.label(node), tree.label(child)),
+	})
+}
+
+// 修改节点的右子节点，并让子节点的 Parent 指回该节点
+func (tree *Tree[T]) setRight(node, child *TreeNode[T]) {
+	node.Right = child
+	if child != nil {
+		child.Parent = node
+	}
+	tree.record(TraceStep{
+		Action: StepSetRight,
+		Node:   tree.label(node),
+		Target: tree.label(child),
+		Detail: fmt.Sprintf("%s.Right = %s", tree.label(node), tree.label(child)),
+	})
+}
+
+// 修改根节点
+func (tree *Tree[T]) setRoot(node *TreeNode[T]) {
+	tree.Root = node
+	if node != nil {
+		node.Parent = nil
+	}
+	tree.record(TraceStep{
+		Action: StepSetRoot,
+		Target: tree.label(node),
+		Detail: fmt.Sprintf("Root = %s", tree.label(node)),
+	})
+}
+
+// 用 replacement 替换 node 在其父节点（或根）中的位置
+func (tree *Tree[T]) transplant(node, replacement *TreeNode[T]) {
+	switch {
+	case node.Parent == nil:
+		tree.setRoot(replacement)
+	case node == node.Parent.Left:
+		tree.setLeft(node.Parent, replacement)
+	default:
+		tree.setRight(node.Parent, replacement)
+	}
+}
+
+// 从根节点开始查找值为 value 的节点，沿途比较计入追踪；未找到返回 nil
+func (tree *Tree[T]) find(value T) *TreeNode[T] {
+	current := tree.Root
+	for current != nil {
+		order := tree.compare(value, current)
+		if order == 0 {
+			return current
+		}
+		if order < 0 {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+	return nil
+}
+
+// 子树中的最小节点：一直向左走
+func (tree *Tree[T]) minimum(node *TreeNode[T]) *TreeNode[T] {
+	tree.visit(node)
+	for node.Left != nil {
+		node = node.Left
+		tree.visit(node)
+	}
+	return node
+}
+
+// 子树中的最大节点：一直向右走
+func (tree *Tree[T]) maximum(node *TreeNode[T]) *TreeNode[T] {
+	tree.visit(node)
+	for node.Right != nil {
+		node = node.Right
+		tree.visit(node)
+	}
+	return node
+}
+
+// Insert 沿查找路径找到空位后挂上新节点，元素已存在时返回 ErrDuplicate；平衡树随后自新节点向上调整
+func (tree *Tree[T]) Insert(value T) error {
+	var parent *TreeNode[T]
+	order := 0
+	for current := tree.Root; current != nil; {
+		parent = current
+		order = tree.compare(value, current)
+		if order == 0 {
+			return ErrDuplicate
+		}
+		if order < 0 {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+
+	node := tree.newNode(value)
+	switch {
+	case parent == nil:
+		tree.setRoot(node)
+	case order < 0:
+		tree.setLeft(parent, node)
+	default:
+		tree.setRight(parent, node)
+	}
+	tree.Size++
+
+	switch tree.Type {
+	case TreeAVL:
+		tree.retrace(parent)
+	case TreeRedBlack:
+		tree.insertFixup(node)
+	}
+	return nil
+}
+
+// Delete 删除值为 value 的节点，有两个子节点时由 replacement 指定用中序后继还是前驱替代；
+// 替代节点整体移动到被删节点的位置，节点的值不被改写。平衡树随后自结构发生变化的最低处向上调整
+func (tree *Tree[T]) Delete(value T, replacement string) error {
+	if replacement == "" {
+		replacement = ReplaceSuccessor
+	}
+	if replacement != ReplaceSuccessor && replacement != ReplacePredecessor {
+		return errors.New("替代节点必须是successor或predecessor")
+	}
+
+	node := tree.find(value)
+	if node == nil {
+		return ErrNotFound
+	}
+
+	// child 为移动到空出位置的子树（可能为空），parent 为它的新父节点，即结构发生变化的最低处；
+	// removedColor 为实际离开原位置的节点颜色，红黑树据此判断是否少了一个黑节点
+	var child, parent *TreeNode[T]
+	removedColor := node.Color
+	switch {
+	case node.Left == nil && node.Right == nil:
+		tree.explain(node, fmt.Sprintf("情况一：%s是叶节点，直接移除", tree.label(node)))
+		parent = node.Parent
+		tree.transplant(node, nil)
+	case node.Left == nil:
+		tree.explain(node, fmt.Sprintf("情况二：%s只有右子节点，由右子节点替代", tree.label(node)))
+		child, parent = node.Right, node.Parent
+		tree.transplant(node, child)
+	case node.Right == nil:
+		tree.explain(node, fmt.Sprintf("情况二：%s只有左子节点，由左子节点替代", tree.label(node)))
+		child, parent = node.Left, node.Parent
+		tree.transplant(node, child)
+	case replacement == ReplaceSuccessor:
+		tree.explain(node, fmt.Sprintf("情况三：%s有两个子节点，由中序后继（右子树的最小节点）替代", tree.label(node)))
+		successor := tree.minimum(node.Right)
+		removedColor = successor.Color
+		child, parent = successor.Right, successor
+		if successor.Parent != node {
+			parent = successor.Parent
+			tree.transplant(successor, successor.Right)
+			tree.setRight(successor, node.Right)
+		}
+		tree.transplant(node, successor)
+		tree.setLeft(successor, node.Left)
+		tree.inherit(successor, node)
+	default:
+		tree.explain(node, fmt.Sprintf("情况三：%s有两个子节点，由中序前驱（左子树的最大节点）替代", tree.label(node)))
+		predecessor := tree.maximum(node.Left)
+		removedColor = predecessor.Color
+		child, parent = predecessor.Left, predecessor
+		if predecessor.Parent != node {
+			parent = predecessor.Parent
+			tree.transplant(predecessor, predecessor.Left)
+			tree.setLeft(predecessor, node.Left)
+		}
+		tree.transplant(node, predecessor)
+		tree.setRight(predecessor, node.Right)
+		tree.inherit(predecessor, node)
+	}
+
+	tree.record(TraceStep{
+		Action: StepFree,
+		Value:  node.Value,
+		Node:   tree.label(node),
+		Detail: fmt.Sprintf("节点%s脱离树", tree.label(node)),
+	})
+	tree.Size--
+
+	switch tree.Type {
+	case TreeAVL:
+		tree.retrace(parent)
+	case TreeRedBlack:
+		if removedColor == ColorBlack {
+			tree.deleteFixup(child, parent)
+		}
+	}
+	return nil
+}
+
+// 替代节点接管被删节点的颜色，AVL 树的高度随后在向上调整时重新计算
+func (tree *Tree[T]) inherit(replacement, node *TreeNode[T]) {
+	if tree.Type == TreeRedBlack {
+		tree.recolor(replacement, node.Color)
+	}
+}
+
+// Contains 从根节点开始查找 value
+func (tree *Tree[T]) Contains(value T) bool {
+	return tree.find(value) != nil
+}
+
+// Min 最小元素，空树返回 ErrEmpty
+func (tree *Tree[T]) Min() (T, error) {
+	if tree.Root == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return tree.minimum(tree.Root).Value, nil
+}
+
+// Max 最大元素，空树返回 ErrEmpty
+func (tree *Tree[T]) Max() (T, error) {
+	if tree.Root == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return tree.maximum(tree.Root).Value, nil
+}
+
+// Floor 不大于 value 的最大元素，不存在时返回 ErrNotFound
+func (tree *Tree[T]) Floor(value T) (T, error) {
+	var candidate *TreeNode[T]
+	current := tree.Root
+	for current != nil {
+		order := tree.compare(value, current)
+		if order == 0 {
+			return current.Value, nil
+		}
+		if order < 0 {
+			current = current.Left
+		} else {
+			// 当前节点不大于 value，记为候选后到右子树找更接近的
+			candidate = current
+			current = current.Right
+		}
+	}
+
+	if candidate == nil {
+		var zero T
+		return zero, ErrNotFound
+	}
+	return candidate.Value, nil
+}
+
+// Ceiling 不小于 value 的最小元素，不存在时返回 ErrNotFound
+func (tree *Tree[T]) Ceiling(value T) (T, error) {
+	var candidate *TreeNode[T]
+	current := tree.Root
+	for current != nil {
+		order := tree.compare(value, current)
+		if order == 0 {
+			return current.Value, nil
+		}
+		if order > 0 {
+			current = current.Right
+		} else {
+			// 当前节点不小于 value，记为候选后到左子树找更接近的
+			candidate = current
+			current = current.Left
+		}
+	}
+
+	if candidate == nil {
+		var zero T
+		return zero, ErrNotFound
+	}
+	return candidate.Value, nil
+}
+
+// Traverse 按指定顺序遍历，每输出一个节点记录一次访问
+func (tree *Tree[T]) Traverse(order string) ([]T, error) {
+	values := make([]T, 0, tree.Size)
+	output := func(node *TreeNode[T]) {
+		tree.record(TraceStep{
+			Action: StepVisit,
+			Index:  intRef(len(values)),
+			Value:  node.Value,
+			Node:   tree.label(node),
+			Detail: fmt.Sprintf("输出节点%s的值%v", tree.label(node), node.Value),
+		})
+		values = append(values, node.Value)
+	}
+
+	var walk func(node *TreeNode[T])
+	switch order {
+	case TraverseInOrder:
+		walk = func(node *TreeNode[T]) {
+			if node == nil {
+				return
+			}
+			walk(node.Left)
+			output(node)
+			walk(node.Right)
+		}
+	case TraversePreOrder:
+		walk = func(node *TreeNode[T]) {
+			if node == nil {
+				return
+			}
+			output(node)
+			walk(node.Left)
+			walk(node.Right)
+		}
+	case TraversePostOrder:
+		walk = func(node *TreeNode[T]) {
+			if node == nil {
+				return
+			}
+			walk(node.Left)
+			walk(node.Right)
+			output(node)
+		}
+	case TraverseLevelOrder:
+		// 用队列逐层输出，同一层从左到右
+		walk = func(root *TreeNode[T]) {
+			if root == nil {
+				return
+			}
+			queue := []*TreeNode[T]{root}
+			for len(queue) > 0 {
+				node := queue[0]
+				queue = queue[1:]
+				output(node)
+				if node.Left != nil {
+					queue = append(queue, node.Left)
+				}
+				if node.Right != nil {
+					queue = append(queue, node.Right)
+				}
+			}
+		}
+	default:
+		return nil, errors.New("遍历顺序必须是inorder、preorder、postorder或levelorder")
+	}
+
+	walk(tree.Root)
+	return values, nil
+}
@@ -0,0 +1,375 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TrieNode 字典树节点，从父节点到它的边上是字符 Char；子节点按字符升序排列
+type TrieNode struct {
+	Char     rune
+	Terminal bool // 从根到该节点的路径是一个已插入的单词
+	Children []*TrieNode
+}
+
+// TrieState 字典树中可序列化的状态
+type TrieState struct {
+	Size      int `json:"size"`      // 单词数
+	NodeCount int `json:"nodeCount"` // 节点数，含根节点
+
+	Recorder `json:"-"`
+}
+
+// Trie 字符串字典树，单词不重复；每个节点对应一个前缀，根节点对应空串
+type Trie struct {
+	TrieState
+	Root *TrieNode `json:"-"`
+}
+
+// NewTrie 创建只有根节点的空字典树
+func NewTrie() *Trie {
+	trie := &Trie{Root: &TrieNode{}}
+	trie.NodeCount = 1
+	trie.Begin()
+	return trie
+}
+
+// RestoreTrie 按单词逐个插入重建字典树，不记录追踪
+func RestoreTrie(words []string) (*Trie, error) {
+	trie := NewTrie()
+	for _, word := range words {
+		if err := trie.Insert(word); err != nil {
+			return nil, err
+		}
+	}
+	trie.Begin()
+	return trie, nil
+}
+
+// Words 按字典序返回所有单词，供持久化后用 RestoreTrie 重建
+func (trie *Trie) Words() []string {
+	words := make([]string, 0, trie.Size)
+	var walk func(node *TrieNode, prefix []rune)
+	walk = func(node *TrieNode, prefix []rune) {
+		if node.Terminal {
+			words = append(words, string(prefix))
+		}
+		for _, child := range node.Children {
+			walk(child, append(prefix, child.Char))
+		}
+	}
+	walk(trie.Root, nil)
+	return words
+}
+
+// 节点在追踪中的标识：根节点为 root，其余为它对应的前缀
+func trieLabel(prefix []rune) string {
+	if len(prefix) == 0 {
+		return "root"
+	}
+	return fmt.Sprintf("%q", string(prefix))
+}
+
+// 在节点的子节点中查找字符为 char 的一个，逐个比较并计入计数；不存在时返回 nil 和应插入的位置
+func (trie *Trie) child(node *TrieNode, prefix []rune, char rune) (*TrieNode, int) {
+	for i, child := range node.Children {
+		trie.cost.Comparisons++
+		if child.Char == char {
+			trie.record(TraceStep{
+				Action: StepVisit,
+				Value:  string(char),
+				Node:   trieLabel(append(prefix, char)),
+				Detail: fmt.Sprintf("节点%s有字符'%c'的边，移动到%s", trieLabel(prefix), char, trieLabel(append(prefix, char))),
+			})
+			return child, i
+		}
+		if child.Char > char {
+			return nil, i
+		}
+	}
+	return nil, len(node.Children)
+}
+
+// 沿前缀从根向下走，返回前缀对应的节点；中途缺少边时返回 nil 并说明在哪一步失败
+func (trie *Trie) walk(prefix []rune) *TrieNode {
+	node := trie.Root
+	for i, char := range prefix {
+		next, _ := trie.child(node, prefix[:i], char)
+		if next == nil {
+			trie.record(TraceStep{
+				Action: StepCase,
+				Value:  string(char),
+				Node:   trieLabel(prefix[:i]),
+				Detail: fmt.Sprintf("节点%s没有字符'%c'的边，前缀%q不存在", trieLabel(prefix[:i]), char, string(prefix)),
+			})
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// 修改节点的结尾标记
+func (trie *Trie) setTerminal(node *TrieNode, prefix []rune, terminal bool) {
+	node.Terminal = terminal
+	trie.cost.Writes++
+	detail := fmt.Sprintf("将%s标记为单词结尾", trieLabel(prefix))
+	if !terminal {
+		detail = fmt.Sprintf("清除%s的单词结尾标记", trieLabel(prefix))
+	}
+	trie.record(TraceStep{
+		Action: StepTerminal,
+		Value:  terminal,
+		Node:   trieLabel(prefix),
+		Detail: detail,
+	})
+}
+
+// Insert 插入单词：沿已有的边向下走，缺少的字符逐个创建节点，最后标记单词结尾；单词已存在时返回 ErrDuplicate
+func (trie *Trie) Insert(word string) error {
+	if word == "" {
+		return errors.New("单词不能为空")
+	}
+
+	runes := []rune(word)
+	node := trie.Root
+	for i, char := range runes {
+		next, at := trie.child(node, runes[:i], char)
+		if next == nil {
+			next = &TrieNode{Char: char}
+			node.Children = append(node.Children, nil)
+			copy(node.Children[at+1:], node.Children[at:])
+			node.Children[at] = next
+			trie.NodeCount++
+			trie.cost.Writes++
+			trie.record(TraceStep{
+				Action: StepCreate,
+				Value:  string(char),
+				Node:   trieLabel(runes[:i+1]),
+				Detail: fmt.Sprintf("创建节点%s", trieLabel(runes[:i+1])),
+			})
+			trie.record(TraceStep{
+				Action: StepSetChild,
+				Index:  intRef(at),
+				Value:  string(char),
+				Node:   trieLabel(runes[:i]),
+				Target: trieLabel(runes[:i+1]),
+				Detail: fmt.Sprintf("%s的第%d条边'%c'指向新节点%s", trieLabel(runes[:i]), at, char, trieLabel(runes[:i+1])),
+			})
+		}
+		node = next
+	}
+
+	if node.Terminal {
+		trie.record(TraceStep{
+			Action: StepCase,
+			Node:   trieLabel(runes),
+			Detail: fmt.Sprintf("%s已标记为单词结尾，单词%q已存在", trieLabel(runes), word),
+		})
+		return ErrDuplicate
+	}
+	trie.setTerminal(node, runes, true)
+	trie.Size++
+	return nil
+}
+
+// Search 查找单词：路径存在且终点带有单词结尾标记
+func (trie *Trie) Search(word string) bool {
+	runes := []rune(word)
+	node := trie.walk(runes)
+	if node == nil {
+		return false
+	}
+	if !node.Terminal {
+		trie.record(TraceStep{
+			Action: StepCase,
+			Node:   trieLabel(runes),
+			Detail: fmt.Sprintf("%s没有单词结尾标记，%q只是其他单词的前缀", trieLabel(runes), word),
+		})
+	}
+	return node.Terminal
+}
+
+// Delete 删除单词：清除结尾标记，再自下而上剪掉不再是任何单词前缀的节点；单词不存在时返回 ErrNotFound
+func (trie *Trie) Delete(word string) error {
+	runes := []rune(word)
+
+	// 记下路径上的节点，path[i] 对应前缀 runes[:i]
+	path := []*TrieNode{trie.Root}
+	node := trie.Root
+	for i, char := range runes {
+		next, _ := trie.child(node, runes[:i], char)
+		if next == nil {
+			trie.record(TraceStep{
+				Action: StepCase,
+				Value:  string(char),
+				Node:   trieLabel(runes[:i]),
+				Detail: fmt.Sprintf("节点%s没有字符'%c'的边，单词%q不存在", trieLabel(runes[:i]), char, word),
+			})
+			return ErrNotFound
+		}
+		node = next
+		path = append(path, node)
+	}
+	if !node.Terminal {
+		trie.record(TraceStep{
+			Action: StepCase,
+			Node:   trieLabel(runes),
+			Detail: fmt.Sprintf("%s没有单词结尾标记，单词%q不存在", trieLabel(runes), word),
+		})
+		return ErrNotFound
+	}
+
+	trie.setTerminal(node, runes, false)
+	trie.Size--
+
+	for i := len(runes); i > 0; i-- {
+		node := path[i]
+		if node.Terminal || len(node.Children) > 0 {
+			trie.record(TraceStep{
+				Action: StepCase,
+				Node:   trieLabel(runes[:i]),
+				Detail: fmt.Sprintf("%s仍是其他单词的前缀，停止剪枝", trieLabel(runes[:i])),
+			})
+			break
+		}
+
+		parent := path[i-1]
+		for at, child := range parent.Children {
+			if child == node {
+				parent.Children = append(parent.Children[:at], parent.Children[at+1:]...)
+				trie.record(TraceStep{
+					Action: StepSetChild,
+					Index:  intRef(at),
+					Value:  string(node.Char),
+					Node:   trieLabel(runes[:i-1]),
+					Target: "nil",
+					Detail: fmt.Sprintf("移除%s的边'%c'", trieLabel(runes[:i-1]), node.Char),
+				})
+				break
+			}
+		}
+		trie.NodeCount--
+		trie.cost.Writes++
+		trie.record(TraceStep{
+			Action: StepFree,
+			Node:   trieLabel(runes[:i]),
+			Detail: fmt.Sprintf("%s既不是单词结尾也没有子节点，释放", trieLabel(runes[:i])),
+		})
+	}
+	return nil
+}
+
+// WithPrefix 按字典序返回以 prefix 开头的所有单词，prefix 为空时返回全部单词
+func (trie *Trie) WithPrefix(prefix string) []string {
+	runes := []rune(prefix)
+	words := make([]string, 0)
+	node := trie.walk(runes)
+	if node == nil {
+		return words
+	}
+
+	var collect func(node *TrieNode, path []rune)
+	collect = func(node *TrieNode, path []rune) {
+		if node.Terminal {
+			words = append(words, string(path))
+			trie.record(TraceStep{
+				Action: StepRead,
+				Value:  string(path),
+				Node:   trieLabel(path),
+				Detail: fmt.Sprintf("%s是单词结尾，收集%q", trieLabel(path), string(path)),
+			})
+		}
+		for _, child := range node.Children {
+			next := append(path[:len(path):len(path)], child.Char)
+			trie.record(TraceStep{
+				Action: StepVisit,
+				Value:  string(child.Char),
+				Node:   trieLabel(next),
+				Detail: fmt.Sprintf("沿边'%c'进入%s", child.Char, trieLabel(next)),
+			})
+			collect(child, next)
+		}
+	}
+	collect(node, runes)
+	return words
+}
+
+// LongestCommonPrefix 所有单词的最长公共前缀：从根向下走，直到节点是单词结尾或有不止一个子节点
+func (trie *Trie) LongestCommonPrefix() string {
+	prefix := make([]rune, 0)
+	node := trie.Root
+	for !node.Terminal && len(node.Children) == 1 {
+		node = node.Children[0]
+		prefix = append(prefix, node.Char)
+		trie.record(TraceStep{
+			Action: StepVisit,
+			Value:  string(node.Char),
+			Node:   trieLabel(prefix),
+			Detail: fmt.Sprintf("%s只有一个子节点，沿边'%c'进入%s", trieLabel(prefix[:len(prefix)-1]), node.Char, trieLabel(prefix)),
+		})
+	}
+
+	detail := fmt.Sprintf("%s有%d个子节点，公共前缀到此为止", trieLabel(prefix), len(node.Children))
+	if node.Terminal {
+		detail = fmt.Sprintf("%s是单词结尾，公共前缀到此为止", trieLabel(prefix))
+	}
+	if trie.Size == 0 {
+		detail = "字典树为空，公共前缀为空串"
+	}
+	trie.record(TraceStep{
+		Action: StepCase,
+		Node:   trieLabel(prefix),
+		Detail: detail,
+	})
+	return string(prefix)
+}
+
+// Autocomplete 返回以 prefix 开头的至多 limit 个补全：从前缀节点按层序搜索，较短的单词在前，
+// 同样长度按字典序；凑够 limit 个后立即停止，不再展开剩余节点。limit 不大于0时不限数量
+func (trie *Trie) Autocomplete(prefix string, limit int) []string {
+	runes := []rune(prefix)
+	words := make([]string, 0)
+	node := trie.walk(runes)
+	if node == nil {
+		return words
+	}
+
+	type entry struct {
+		node *TrieNode
+		path []rune
+	}
+	queue := []entry{{node, runes}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.node.Terminal {
+			words = append(words, string(current.path))
+			trie.record(TraceStep{
+				Action: StepRead,
+				Value:  string(current.path),
+				Node:   trieLabel(current.path),
+				Detail: fmt.Sprintf("%s是单词结尾，补全%q（第%d个）", trieLabel(current.path), string(current.path), len(words)),
+			})
+			if limit > 0 && len(words) == limit {
+				trie.record(TraceStep{
+					Action: StepCase,
+					Node:   trieLabel(current.path),
+					Detail: fmt.Sprintf("已凑够%d个补全，停止搜索", limit),
+				})
+				break
+			}
+		}
+		for _, child := range current.node.Children {
+			next := append(current.path[:len(current.path):len(current.path)], child.Char)
+			trie.record(TraceStep{
+				Action: StepVisit,
+				Value:  string(child.Char),
+				Node:   trieLabel(next),
+				Detail: fmt.Sprintf("沿边'%c'发现%s，加入队列", child.Char, trieLabel(next)),
+			})
+			queue = append(queue, entry{child, next})
+		}
+	}
+	return words
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"echo/ds"
+)
+
+// 默认的元素类型，兼容只支持整数的旧客户端和旧快照
+const defaultElementType = ds.ElementInt
+
+// 每种元素类型对应的数据结构构造函数，由泛型实现实例化后按类型名分发
+type elementFactory struct {
+	newArray           func(req ArrayRequest) (arrayResource, error)
+	restoreArray       func(data []byte) (arrayResource, error)
+	newList            func(req LinkedListRequest) (listResource, error)
+	restoreList        func(data []byte) (listResource, error)
+	newStack           func(req StackRequest) (stackResource, error)
+	restoreStack       func(data []byte) (stackResource, error)
+	newDeque           func(req DequeRequest) (dequeResource, error)
+	restoreDeque       func(data []byte) (dequeResource, error)
+	newHeap            func(req HeapRequest) (heapResource, error)
+	restoreHeap        func(data []byte) (heapResource, error)
+	heapifyArray       func(req HeapifyRequest, source arrayResource) (heapResource, error)
+	newTree            func(req TreeRequest) (treeResource, error)
+	restoreTree        func(data []byte) (treeResource, error)
+	newHashTable       func(req HashTableRequest) (hashTableResource, error)
+	restoreHashTable   func(data []byte) (hashTableResource, error)
+	newGraph           func(req GraphRequest) (graphResource, error)
+	restoreGraph       func(data []byte) (graphResource, error)
+	newDisjointSet     func(req DisjointSetRequest) (disjointSetResource, error)
+	restoreDisjointSet func(data []byte) (disjointSetResource, error)
+	newSkipList        func(req SkipListRequest) (skipListResource, error)
+	restoreSkipList    func(data []byte) (skipListResource, error)
+}
+
+// 为元素类型 T 实例化各数据结构的构造函数
+func factoryFor[T any](kind *ds.ElementType[T]) elementFactory {
+	// 出错时显式返回 nil 接口，避免包着 nil 指针的非 nil 接口
+	return elementFactory{
+		newArray: func(req ArrayRequest) (arrayResource, error) {
+			array, err := newDynamicArray(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return array, nil
+		},
+		restoreArray: func(data []byte) (arrayResource, error) {
+			array, err := restoreTypedArray(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return array, nil
+		},
+		newList: func(req LinkedListRequest) (listResource, error) {
+			list, err := newLinkedList(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return list, nil
+		},
+		restoreList: func(data []byte) (listResource, error) {
+			list, err := restoreTypedList(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return list, nil
+		},
+		newStack: func(req StackRequest) (stackResource, error) {
+			stack, err := newStack(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return stack, nil
+		},
+		restoreStack: func(data []byte) (stackResource, error) {
+			stack, err := restoreTypedStack(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return stack, nil
+		},
+		newDeque: func(req DequeRequest) (dequeResource, error) {
+			deque, err := newDeque(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return deque, nil
+		},
+		restoreDeque: func(data []byte) (dequeResource, error) {
+			deque, err := restoreTypedDeque(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return deque, nil
+		},
+		newHeap: func(req HeapRequest) (heapResource, error) {
+			heap, err := newHeap(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return heap, nil
+		},
+		restoreHeap: func(data []byte) (heapResource, error) {
+			heap, err := restoreTypedHeap(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return heap, nil
+		},
+		heapifyArray: func(req HeapifyRequest, source arrayResource) (heapResource, error) {
+			array, ok := source.(*DynamicArray[T])
+			if !ok {
+				return nil, fmt.Errorf("数组的元素类型不是%s", kind.Name)
+			}
+			heap, err := heapifyArray(req, array)
+			if err != nil {
+				return nil, err
+			}
+			return heap, nil
+		},
+		newTree: func(req TreeRequest) (treeResource, error) {
+			tree, err := newTree(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return tree, nil
+		},
+		restoreTree: func(data []byte) (treeResource, error) {
+			tree, err := restoreTypedTree(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return tree, nil
+		},
+		newHashTable: func(req HashTableRequest) (hashTableResource, error) {
+			table, err := newHashTable(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return table, nil
+		},
+		restoreHashTable: func(data []byte) (hashTableResource, error) {
+			table, err := restoreTypedHashTable(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return table, nil
+		},
+		newGraph: func(req GraphRequest) (graphResource, error) {
+			graph, err := newGraph(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return graph, nil
+		},
+		restoreGraph: func(data []byte) (graphResource, error) {
+			graph, err := restoreTypedGraph(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return graph, nil
+		},
+		newDisjointSet: func(req DisjointSetRequest) (disjointSetResource, error) {
+			sets, err := newDisjointSet(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return sets, nil
+		},
+		restoreDisjointSet: func(data []byte) (disjointSetResource, error) {
+			sets, err := restoreTypedDisjointSet(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return sets, nil
+		},
+		newSkipList: func(req SkipListRequest) (skipListResource, error) {
+			list, err := newSkipList(req, kind)
+			if err != nil {
+				return nil, err
+			}
+			return list, nil
+		},
+		restoreSkipList: func(data []byte) (skipListResource, error) {
+			list, err := restoreTypedSkipList(data, kind)
+			if err != nil {
+				return nil, err
+			}
+			return list, nil
+		},
+	}
+}
+
+// 已注册的元素类型
+var elementFactories = map[string]elementFactory{
+	ds.ElementInt:    factoryFor(ds.Ints),
+	ds.ElementFloat:  factoryFor(ds.Floats),
+	ds.ElementString: factoryFor(ds.Strings),
+	ds.ElementBool:   factoryFor(ds.Bools),
+	ds.ElementObject: factoryFor(ds.Objects),
+}
+
+// 根据名称查找元素类型，空名称使用默认类型
+func elementFactoryFor(name string) (elementFactory, string, error) {
+	if name == "" {
+		name = defaultElementType
+	}
+	factory, ok := elementFactories[name]
+	if !ok {
+		return elementFactory{}, name, fmt.Errorf("元素类型必须是%s之一", strings.Join(elementTypeNames(), "、"))
+	}
+	return factory, name, nil
+}
+
+// 按字母顺序返回所有已注册的元素类型名称
+func elementTypeNames() []string {
+	names := make([]string, 0, len(elementFactories))
+	for name := range elementFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 快照中用于分发到具体元素类型的字段
+type elementTypeProbe struct {
+	ElementType string `json:"elementType"`
+}
+
+// 读取快照的元素类型，旧快照没有该字段时按默认类型处理
+func snapshotElementType(data []byte) (elementFactory, error) {
+	var probe elementTypeProbe
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return elementFactory{}, err
+	}
+	factory, _, err := elementFactoryFor(probe.ElementType)
+	return factory, err
+}
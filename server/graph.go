@@ -0,0 +1,754 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GraphArcData 邻接表中出边节点的值，终点以顶点值表示
+type GraphArcData[T any] struct {
+	To     T       `json:"to"`
+	Weight float64 `json:"weight"`
+}
+
+// AdjacencyData 一个顶点的出边链表，节点与链表的 NodeData 相同
+type AdjacencyData[T any] struct {
+	Vertex T                            `json:"vertex"`
+	Nodes  []*NodeData[GraphArcData[T]] `json:"nodes"`
+}
+
+// graphHeader 图中与顶点类型无关的服务端状态：标识、版本和锁
+type graphHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"` // 顶点的元素类型
+	Version     int64  `json:"version"`     // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	recorder *ds.Recorder // 最近一次操作的计数和追踪
+
+	mu      sync.Mutex // 串行化对同一个图的操作，不同图之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// Graph 图结构体，操作由 ds.Graph 实现；视图只给出当前存储方式对应的邻接表或邻接矩阵
+type Graph[T any] struct {
+	graphHeader
+	*ds.Graph[T]
+
+	EdgeList        []ds.GraphEdge[T]   `json:"edges"`
+	AdjacencyList   []*AdjacencyData[T] `json:"adjacencyList,omitempty"`   // 仅邻接表
+	AdjacencyMatrix [][]*float64        `json:"adjacencyMatrix,omitempty"` // 仅邻接矩阵：按顶点顺序，无边为 null
+}
+
+// graphResource 与顶点类型无关的图接口，处理函数通过它操作任意顶点类型的 Graph[T]
+type graphResource interface {
+	header() *graphHeader
+	parseValue(s string) (any, error)
+	decodeValue(raw json.RawMessage) (any, error)
+	beginOperation()
+	addVertexAny(value any) error
+	removeVertexAny(value any) error
+	addEdgeAny(from, to any, weight float64) error
+	removeEdgeAny(from, to any) error
+	setRepresentation(representation string) error
+	run(algorithm string, start any) (any, error)
+	updateVisualizationData()
+	snapshot() any
+}
+
+// GraphRequest 创建图的请求
+type GraphRequest struct {
+	Name           string `json:"name"`
+	ElementType    string `json:"elementType"` // 顶点的元素类型
+	Directed       bool   `json:"directed"`
+	Weighted       bool   `json:"weighted"`
+	Representation string `json:"representation"` // list（默认）或 matrix
+}
+
+// GraphVertexRequest 加入顶点的请求，Value 按图的顶点类型解码
+type GraphVertexRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// GraphEdgeRequest 加入边的请求，带权图必须给出 Weight
+type GraphEdgeRequest struct {
+	From   json.RawMessage `json:"from"`
+	To     json.RawMessage `json:"to"`
+	Weight *float64        `json:"weight"`
+}
+
+// RepresentationRequest 切换存储方式的请求
+type RepresentationRequest struct {
+	Representation string `json:"representation"` // list 或 matrix
+}
+
+// GraphResponse 图操作响应结构体
+type GraphResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Graph   graphResource     `json:"graph,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Cost    *ds.OperationCost `json:"cost,omitempty"`
+	Trace   []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局图存储，后端由 initStorage 根据配置选择
+var graphs Storage[graphResource] = newMemoryStorage[graphResource]("graph")
+
+// 获取图并加锁，调用方负责解锁；图不存在或已被删除时返回 false
+func lockGraph(id string) (graphResource, bool) {
+	res, exists := graphs.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	graph := res.header()
+	graph.mu.Lock()
+	if graph.removed {
+		graph.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitGraph(c echo.Context, res graphResource) error {
+	graph := res.header()
+	graph.Version++
+	if err := graphs.Save(graph.ID, res); err != nil {
+		return err
+	}
+	setETag(c, graph.Version)
+	return nil
+}
+
+// 按请求创建顶点类型为 T 的空图，参数无效时返回错误；ID 由调用方在校验通过后分配
+func newGraph[T any](req GraphRequest, kind *ds.ElementType[T]) (*Graph[T], error) {
+	core, err := ds.NewGraph(ds.GraphConfig{
+		Directed:       req.Directed,
+		Weighted:       req.Weighted,
+		Representation: req.Representation,
+	}, kind)
+	if err != nil {
+		return nil, err
+	}
+	return wrapGraph("", req.Name, core), nil
+}
+
+// 为 ds.Graph 附加服务端状态
+func wrapGraph[T any](id, name string, core *ds.Graph[T]) *Graph[T] {
+	graph := &Graph[T]{Graph: core}
+	graph.ID = id
+	graph.Name = name
+	graph.ElementType = core.Kind().Name
+	graph.recorder = &core.Recorder
+	graph.updateVisualizationData()
+	return graph
+}
+
+// 图的持久化快照，各顶点的出边按邻接表中的顺序保存
+type graphSnapshot[T any] struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"`
+	ds.GraphConfig
+	Vertices []T             `json:"vertices"`
+	Arcs     [][]ds.GraphArc `json:"arcs"`
+}
+
+// 生成图快照
+func snapshotGraph(res graphResource) any {
+	return res.snapshot()
+}
+
+func (graph *Graph[T]) snapshot() any {
+	return graphSnapshot[T]{
+		ID:          graph.ID,
+		Name:        graph.Name,
+		ElementType: graph.ElementType,
+		Version:     graph.Version,
+		GraphConfig: graph.Config(),
+		Vertices:    graph.Vertices,
+		Arcs:        graph.Saved(),
+	}
+}
+
+// 从快照恢复图，按快照中的元素类型分发
+func restoreGraph(data []byte) (graphResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreGraph(data)
+}
+
+// 从快照重建顶点类型为 T 的图
+func restoreTypedGraph[T any](data []byte, kind *ds.ElementType[T]) (*Graph[T], error) {
+	var snapshot graphSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreGraph(snapshot.GraphConfig, snapshot.Vertices, snapshot.Arcs, kind)
+	if err != nil {
+		return nil, err
+	}
+	graph := wrapGraph(snapshot.ID, snapshot.Name, core)
+	graph.Version = snapshot.Version
+	return graph, nil
+}
+
+func (graph *Graph[T]) header() *graphHeader {
+	return &graph.graphHeader
+}
+
+func (graph *Graph[T]) parseValue(s string) (any, error) {
+	return graph.Kind().Parse(s)
+}
+
+func (graph *Graph[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return graph.Kind().Decode(raw)
+}
+
+func (graph *Graph[T]) beginOperation() {
+	graph.Begin()
+}
+
+func (graph *Graph[T]) addVertexAny(value any) error {
+	return graph.AddVertex(valueOf[T](value))
+}
+
+func (graph *Graph[T]) removeVertexAny(value any) error {
+	return graph.RemoveVertex(valueOf[T](value))
+}
+
+func (graph *Graph[T]) addEdgeAny(from, to any, weight float64) error {
+	return graph.AddEdge(valueOf[T](from), valueOf[T](to), weight)
+}
+
+func (graph *Graph[T]) removeEdgeAny(from, to any) error {
+	return graph.RemoveEdge(valueOf[T](from), valueOf[T](to))
+}
+
+func (graph *Graph[T]) setRepresentation(representation string) error {
+	return graph.SetRepresentation(representation)
+}
+
+// 按名称运行图算法，start 为起点（不需要起点的算法忽略）
+func (graph *Graph[T]) run(algorithm string, start any) (any, error) {
+	switch algorithm {
+	case ds.GraphBFS:
+		return graph.BFS(valueOf[T](start))
+	case ds.GraphDFS:
+		return graph.DFS(valueOf[T](start))
+	case ds.GraphTopological:
+		return graph.TopologicalSort()
+	case ds.GraphDijkstra:
+		return graph.Dijkstra(valueOf[T](start))
+	case ds.GraphBellmanFord:
+		return graph.BellmanFord(valueOf[T](start))
+	case ds.GraphPrim:
+		return graph.Prim(valueOf[T](start))
+	case ds.GraphKruskal:
+		return graph.Kruskal()
+	case ds.GraphComponents:
+		return graph.Components()
+	default:
+		return nil, fmt.Errorf("未知的图算法: %s", algorithm)
+	}
+}
+
+// 生成边列表，以及当前存储方式对应的邻接表或邻接矩阵
+func (graph *Graph[T]) updateVisualizationData() {
+	graph.EdgeList = graph.Edges()
+	graph.AdjacencyList = nil
+	graph.AdjacencyMatrix = nil
+
+	if graph.Representation == ds.GraphMatrix {
+		graph.AdjacencyMatrix = graph.Matrix
+		return
+	}
+
+	graph.AdjacencyList = make([]*AdjacencyData[T], 0, len(graph.Vertices))
+	for u, vertex := range graph.Vertices {
+		adjacency := &AdjacencyData[T]{Vertex: vertex, Nodes: make([]*NodeData[GraphArcData[T]], 0)}
+		prefix := fmt.Sprintf("%s_adj_%d", graph.ID, u)
+		index := 0
+		for node := graph.Adjacency[u]; node != nil; node = node.Next {
+			nodeData := &NodeData[GraphArcData[T]]{
+				Value: GraphArcData[T]{To: graph.Vertices[node.Value.To], Weight: node.Value.Weight},
+				ID:    generateNodeID(prefix, index),
+			}
+			if node.Next != nil {
+				nodeData.NextID = generateNodeID(prefix, index+1)
+			}
+			adjacency.Nodes = append(adjacency.Nodes, nodeData)
+			index++
+		}
+		graph.AdjacencyList = append(graph.AdjacencyList, adjacency)
+	}
+}
+
+// 设置图相关路由
+func setupGraphRoutes(g *echo.Group) {
+	graphGroup := g.Group("/graphs")
+
+	// 创建图
+	graphGroup.POST("", createGraph)
+
+	// 获取所有图
+	graphGroup.GET("", getAllGraphs)
+
+	// 获取指定图
+	graphGroup.GET("/:id", getGraph)
+
+	// 删除图
+	graphGroup.DELETE("/:id", deleteGraph)
+
+	// 加入和删除顶点
+	graphGroup.POST("/:id/vertices", addGraphVertex)
+	graphGroup.DELETE("/:id/vertices/:value", removeGraphVertex)
+
+	// 加入和删除边
+	graphGroup.POST("/:id/edges", addGraphEdge)
+	graphGroup.DELETE("/:id/edges/:from/:to", removeGraphEdge)
+
+	// 切换存储方式（list、matrix）
+	graphGroup.PUT("/:id/representation", setGraphRepresentation)
+
+	// 需要起点的算法
+	for _, algorithm := range []string{ds.GraphBFS, ds.GraphDFS, ds.GraphDijkstra, ds.GraphBellmanFord, ds.GraphPrim} {
+		graphGroup.GET("/:id/"+algorithm+"/:start", runGraphAlgorithm(algorithm))
+	}
+
+	// 作用于整个图的算法
+	for _, algorithm := range []string{ds.GraphTopological, ds.GraphKruskal, ds.GraphComponents} {
+		graphGroup.GET("/:id/"+algorithm, runGraphAlgorithm(algorithm))
+	}
+}
+
+// 创建图
+func createGraph(c echo.Context) error {
+	var req GraphRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	req.ElementType = elementType
+
+	res, err := factory.newGraph(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	id, err := graphs.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, GraphResponse{
+			Success: false,
+			Message: "图ID生成失败",
+		})
+	}
+	graph := res.header()
+	graph.ID = id
+	res.updateVisualizationData()
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+
+	if err := commitGraph(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, GraphResponse{
+			Success: false,
+			Message: "图保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, GraphResponse{
+		Success: true,
+		Message: "图创建成功",
+		Graph:   res,
+	})
+}
+
+// 获取所有图
+func getAllGraphs(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的图
+	graphList := make([]json.RawMessage, 0)
+	for _, res := range graphs.List() {
+		graph := res.header()
+		graph.mu.Lock()
+		data, err := json.Marshal(res)
+		graph.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		graphList = append(graphList, data)
+	}
+
+	return c.JSON(http.StatusOK, GraphResponse{
+		Success: true,
+		Message: "获取图列表成功",
+		Data:    graphList,
+	})
+}
+
+// 获取指定图
+func getGraph(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockGraph(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, GraphResponse{
+			Success: false,
+			Message: "图不存在",
+		})
+	}
+	graph := res.header()
+	defer graph.mu.Unlock()
+
+	setETag(c, graph.Version)
+	return c.JSON(http.StatusOK, GraphResponse{
+		Success: true,
+		Message: "获取图成功",
+		Graph:   res,
+	})
+}
+
+// 删除图
+func deleteGraph(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockGraph(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, GraphResponse{
+			Success: false,
+			Message: "图不存在",
+		})
+	}
+	graph := res.header()
+	defer graph.mu.Unlock()
+
+	if _, err := graphs.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, GraphResponse{
+			Success: false,
+			Message: "图删除失败",
+		})
+	}
+	graph.removed = true
+
+	return c.JSON(http.StatusOK, GraphResponse{
+		Success: true,
+		Message: "图删除成功",
+	})
+}
+
+// 获取图并检查 If-Match，失败时已写好响应，返回的 handled 为 true
+func lockGraphForUpdate(c echo.Context) (graphResource, bool, error) {
+	res, exists := lockGraph(c.Param("id"))
+	if !exists {
+		return nil, true, c.JSON(http.StatusNotFound, GraphResponse{
+			Success: false,
+			Message: "图不存在",
+		})
+	}
+
+	graph := res.header()
+	if !ifMatchSatisfied(c, graph.Version) {
+		defer graph.mu.Unlock()
+		setETag(c, graph.Version)
+		return nil, true, c.JSON(http.StatusPreconditionFailed, GraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("图已被修改（当前版本%d），请刷新后重试", graph.Version),
+			Graph:   res,
+		})
+	}
+	return res, false, nil
+}
+
+// 提交修改并返回图和本次操作的追踪
+func respondGraphUpdate(c echo.Context, res graphResource, message string) error {
+	graph := res.header()
+	cost := graph.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitGraph(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, GraphResponse{
+			Success: false,
+			Message: "图保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, GraphResponse{
+		Success: true,
+		Message: message,
+		Graph:   res,
+		Cost:    &cost,
+		Trace:   graph.recorder.Trace(),
+	})
+}
+
+// 加入顶点
+func addGraphVertex(c echo.Context) error {
+	res, handled, err := lockGraphForUpdate(c)
+	if handled {
+		return err
+	}
+	defer res.header().mu.Unlock()
+
+	var req GraphVertexRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	res.beginOperation()
+	if err := res.addVertexAny(value); err != nil {
+		if errors.Is(err, ds.ErrDuplicate) {
+			return c.JSON(http.StatusBadRequest, GraphResponse{
+				Success: false,
+				Message: fmt.Sprintf("顶点%v已存在", value),
+			})
+		}
+		return err
+	}
+
+	return respondGraphUpdate(c, res, fmt.Sprintf("顶点%v已加入图", value))
+}
+
+// 删除顶点及与它相连的边
+func removeGraphVertex(c echo.Context) error {
+	res, handled, err := lockGraphForUpdate(c)
+	if handled {
+		return err
+	}
+	defer res.header().mu.Unlock()
+
+	value, err := res.parseValue(c.Param("value"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("顶点格式错误：%v", err),
+		})
+	}
+
+	res.beginOperation()
+	if err := res.removeVertexAny(value); err != nil {
+		if errors.Is(err, ds.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, GraphResponse{
+				Success: false,
+				Message: fmt.Sprintf("顶点%v不存在", value),
+			})
+		}
+		return err
+	}
+
+	return respondGraphUpdate(c, res, fmt.Sprintf("顶点%v及与它相连的边已删除", value))
+}
+
+// 加入边
+func addGraphEdge(c echo.Context) error {
+	res, handled, err := lockGraphForUpdate(c)
+	if handled {
+		return err
+	}
+	defer res.header().mu.Unlock()
+
+	var req GraphEdgeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	from, err := res.decodeValue(req.From)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("起点格式错误：%v", err),
+		})
+	}
+	to, err := res.decodeValue(req.To)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("终点格式错误：%v", err),
+		})
+	}
+
+	weight := 1.0
+	if req.Weight != nil {
+		weight = *req.Weight
+	}
+	res.beginOperation()
+	err = res.addEdgeAny(from, to, weight)
+	if errors.Is(err, ds.ErrDuplicate) {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("边%v-%v已存在", from, to),
+			Trace:   res.header().recorder.Trace(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return respondGraphUpdate(c, res, fmt.Sprintf("边%v-%v已加入图", from, to))
+}
+
+// 删除边
+func removeGraphEdge(c echo.Context) error {
+	res, handled, err := lockGraphForUpdate(c)
+	if handled {
+		return err
+	}
+	defer res.header().mu.Unlock()
+
+	from, err := res.parseValue(c.Param("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("起点格式错误：%v", err),
+		})
+	}
+	to, err := res.parseValue(c.Param("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("终点格式错误：%v", err),
+		})
+	}
+
+	res.beginOperation()
+	err = res.removeEdgeAny(from, to)
+	if errors.Is(err, ds.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, GraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("边%v-%v不存在", from, to),
+			Trace:   res.header().recorder.Trace(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return respondGraphUpdate(c, res, fmt.Sprintf("边%v-%v已删除", from, to))
+}
+
+// 切换存储方式
+func setGraphRepresentation(c echo.Context) error {
+	res, handled, err := lockGraphForUpdate(c)
+	if handled {
+		return err
+	}
+	defer res.header().mu.Unlock()
+
+	var req RepresentationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	res.beginOperation()
+	if err := res.setRepresentation(req.Representation); err != nil {
+		return c.JSON(http.StatusBadRequest, GraphResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return respondGraphUpdate(c, res, fmt.Sprintf("存储方式已切换为%s", req.Representation))
+}
+
+// 运行图算法的处理函数，需要起点的算法从路径参数 start 读取
+func runGraphAlgorithm(algorithm string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Param("id")
+		res, exists := lockGraph(id)
+		if !exists {
+			return c.JSON(http.StatusNotFound, GraphResponse{
+				Success: false,
+				Message: "图不存在",
+			})
+		}
+		graph := res.header()
+		defer graph.mu.Unlock()
+
+		var start any
+		if c.Param("start") != "" {
+			value, err := res.parseValue(c.Param("start"))
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, GraphResponse{
+					Success: false,
+					Message: fmt.Sprintf("起点格式错误：%v", err),
+				})
+			}
+			start = value
+		}
+
+		res.beginOperation()
+		result, err := res.run(algorithm, start)
+		cost := graph.recorder.Cost()
+		if errors.Is(err, ds.ErrCycle) || errors.Is(err, ds.ErrNegativeCycle) {
+			return c.JSON(http.StatusBadRequest, GraphResponse{
+				Success: false,
+				Message: err.Error(),
+				Data:    result,
+				Cost:    &cost,
+				Trace:   graph.recorder.Trace(),
+			})
+		}
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, GraphResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		return c.JSON(http.StatusOK, GraphResponse{
+			Success: true,
+			Message: fmt.Sprintf("%s执行完成", algorithm),
+			Graph:   res,
+			Data:    result,
+			Cost:    &cost,
+			Trace:   graph.recorder.Trace(),
+		})
+	}
+}
@@ -0,0 +1,694 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HashEntryData 拉链法桶中的键值对，值为任意 JSON
+type HashEntryData[T any] = ds.HashEntry[T, json.RawMessage]
+
+// BucketData 用于前端显示的桶（槽位）：拉链法给出链表节点，开放寻址给出槽位状态和其中的键值对
+type BucketData[T any] struct {
+	Index  int                           `json:"index"`
+	State  string                        `json:"state"`            // empty、occupied 或 tombstone（仅开放寻址）
+	Key    *T                            `json:"key,omitempty"`    // 仅开放寻址的占用槽位
+	Value  json.RawMessage               `json:"value,omitempty"`  // 仅开放寻址的占用槽位
+	Home   *int                          `json:"home,omitempty"`   // 仅开放寻址的占用槽位：键的初始槽位，与 index 不同说明发生过冲突
+	Length int                           `json:"length,omitempty"` // 仅拉链法：链长
+	Nodes  []*NodeData[HashEntryData[T]] `json:"nodes,omitempty"`  // 仅拉链法：与链表相同的节点表示
+}
+
+// hashTableHeader 哈希表中与键类型无关的服务端状态：标识、版本和锁
+type hashTableHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"` // 键的元素类型
+	Version     int64  `json:"version"`     // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	recorder *ds.Recorder // 最近一次操作的计数和追踪
+
+	mu      sync.Mutex // 串行化对同一哈希表的操作，不同哈希表之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// HashTable 哈希表结构体，操作由 ds.HashTable 实现，键的类型为 T，值为任意 JSON
+type HashTable[T any] struct {
+	hashTableHeader
+	*ds.HashTable[T, json.RawMessage]
+
+	LoadFactor  float64          `json:"loadFactor"`
+	LongestRun  int              `json:"longestRun"` // 拉链法为最长链长，开放寻址为最长的连续非空槽位（聚集）
+	BucketViews []*BucketData[T] `json:"buckets"`
+}
+
+// hashTableResource 与键类型无关的哈希表接口，处理函数通过它操作任意键类型的 HashTable[T]
+type hashTableResource interface {
+	header() *hashTableHeader
+	parseKey(s string) (any, error)
+	decodeKey(raw json.RawMessage) (any, error)
+	beginOperation()
+	putAny(key any, value json.RawMessage) bool
+	getAny(key any) (json.RawMessage, error)
+	deleteAny(key any) (json.RawMessage, error)
+	rehashTo(capacity int) error
+	probeSequence() []int
+	updateVisualizationData()
+	snapshot() any
+}
+
+// HashTableRequest 创建哈希表的请求
+type HashTableRequest struct {
+	Name          string  `json:"name"`
+	ElementType   string  `json:"elementType"`   // 键的元素类型
+	Strategy      string  `json:"strategy"`      // chaining（默认）、linear、quadratic 或 double
+	HashFunction  string  `json:"hashFunction"`  // division（默认）、multiplication 或 fnv1a
+	Capacity      int     `json:"capacity"`      // 向上取为素数，默认7
+	MaxLoadFactor float64 `json:"maxLoadFactor"` // 默认拉链法1.0，开放寻址0.5
+}
+
+// HashPutRequest 插入或更新请求，Key 按哈希表的键类型解码，Value 为任意 JSON
+type HashPutRequest struct {
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// RehashRequest 手动重建请求
+type RehashRequest struct {
+	Capacity int `json:"capacity"` // 0 表示保持原容量，只清除墓碑
+}
+
+// HashTableResponse 哈希表操作响应结构体
+type HashTableResponse struct {
+	Success   bool              `json:"success"`
+	Message   string            `json:"message"`
+	HashTable hashTableResource `json:"hashTable,omitempty"`
+	Data      interface{}       `json:"data,omitempty"`
+	Probes    []int             `json:"probes,omitempty"` // 被操作的键依次探查的桶（槽位）下标
+	Cost      *ds.OperationCost `json:"cost,omitempty"`
+	Trace     []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局哈希表存储，后端由 initStorage 根据配置选择
+var hashTables Storage[hashTableResource] = newMemoryStorage[hashTableResource]("hashtable")
+
+// 获取哈希表并加锁，调用方负责解锁；哈希表不存在或已被删除时返回 false
+func lockHashTable(id string) (hashTableResource, bool) {
+	res, exists := hashTables.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	table := res.header()
+	table.mu.Lock()
+	if table.removed {
+		table.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitHashTable(c echo.Context, res hashTableResource) error {
+	table := res.header()
+	table.Version++
+	if err := hashTables.Save(table.ID, res); err != nil {
+		return err
+	}
+	setETag(c, table.Version)
+	return nil
+}
+
+// 按请求创建键类型为 T 的空哈希表，参数无效时返回错误；ID 由调用方在校验通过后分配
+func newHashTable[T any](req HashTableRequest, kind *ds.ElementType[T]) (*HashTable[T], error) {
+	core, err := ds.NewHashTable[T, json.RawMessage](ds.HashConfig{
+		Strategy:      req.Strategy,
+		HashFunction:  req.HashFunction,
+		Capacity:      req.Capacity,
+		MaxLoadFactor: req.MaxLoadFactor,
+	}, kind)
+	if err != nil {
+		return nil, err
+	}
+	return wrapHashTable("", req.Name, core), nil
+}
+
+// 为 ds.HashTable 附加服务端状态
+func wrapHashTable[T any](id, name string, core *ds.HashTable[T, json.RawMessage]) *HashTable[T] {
+	table := &HashTable[T]{HashTable: core}
+	table.ID = id
+	table.Name = name
+	table.ElementType = core.Kind().Name
+	table.recorder = &core.Recorder
+	table.updateVisualizationData()
+	return table
+}
+
+// 哈希表的持久化快照，键值对和墓碑按位置保存，恢复后聚集情况不变
+type hashTableSnapshot[T any] struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"`
+	ds.HashConfig
+	Entries []ds.SavedHashEntry[T, json.RawMessage] `json:"entries"`
+}
+
+// 生成哈希表快照
+func snapshotHashTable(res hashTableResource) any {
+	return res.snapshot()
+}
+
+func (table *HashTable[T]) snapshot() any {
+	return hashTableSnapshot[T]{
+		ID:          table.ID,
+		Name:        table.Name,
+		ElementType: table.ElementType,
+		Version:     table.Version,
+		HashConfig:  table.Config(),
+		Entries:     table.Saved(),
+	}
+}
+
+// 从快照恢复哈希表，按快照中的元素类型分发
+func restoreHashTable(data []byte) (hashTableResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreHashTable(data)
+}
+
+// 从快照重建键类型为 T 的哈希表
+func restoreTypedHashTable[T any](data []byte, kind *ds.ElementType[T]) (*HashTable[T], error) {
+	var snapshot hashTableSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreHashTable(snapshot.HashConfig, snapshot.Entries, kind)
+	if err != nil {
+		return nil, err
+	}
+	table := wrapHashTable(snapshot.ID, snapshot.Name, core)
+	table.Version = snapshot.Version
+	return table, nil
+}
+
+func (table *HashTable[T]) header() *hashTableHeader {
+	return &table.hashTableHeader
+}
+
+func (table *HashTable[T]) parseKey(s string) (any, error) {
+	return table.Kind().Parse(s)
+}
+
+func (table *HashTable[T]) decodeKey(raw json.RawMessage) (any, error) {
+	return table.Kind().Decode(raw)
+}
+
+func (table *HashTable[T]) beginOperation() {
+	table.Begin()
+}
+
+func (table *HashTable[T]) putAny(key any, value json.RawMessage) bool {
+	return table.Put(valueOf[T](key), value)
+}
+
+func (table *HashTable[T]) getAny(key any) (json.RawMessage, error) {
+	return table.Get(valueOf[T](key))
+}
+
+func (table *HashTable[T]) deleteAny(key any) (json.RawMessage, error) {
+	return table.Delete(valueOf[T](key))
+}
+
+func (table *HashTable[T]) rehashTo(capacity int) error {
+	return table.Rehash(capacity)
+}
+
+func (table *HashTable[T]) probeSequence() []int {
+	return table.Probes()
+}
+
+// 按桶（槽位）顺序生成显示数据，并统计装载因子和最长的链或聚集
+func (table *HashTable[T]) updateVisualizationData() {
+	table.LoadFactor = table.HashTable.LoadFactor()
+	table.LongestRun = 0
+	table.BucketViews = make([]*BucketData[T], 0, table.Capacity)
+
+	if table.IsChaining() {
+		for index, head := range table.Buckets {
+			bucket := &BucketData[T]{Index: index, State: ds.SlotEmpty}
+			prefix := fmt.Sprintf("%s_bucket_%d", table.ID, index)
+			for node := head; node != nil; node = node.Next {
+				nodeData := &NodeData[HashEntryData[T]]{
+					Value: node.Value,
+					ID:    generateNodeID(prefix, bucket.Length),
+				}
+				if node.Next != nil {
+					nodeData.NextID = generateNodeID(prefix, bucket.Length+1)
+				}
+				bucket.Nodes = append(bucket.Nodes, nodeData)
+				bucket.Length++
+			}
+			if bucket.Length > 0 {
+				bucket.State = ds.SlotOccupied
+			}
+			table.LongestRun = max(table.LongestRun, bucket.Length)
+			table.BucketViews = append(table.BucketViews, bucket)
+		}
+		return
+	}
+
+	run := 0
+	for index, slot := range table.Slots {
+		bucket := &BucketData[T]{Index: index, State: slot.State}
+		if slot.State == ds.SlotOccupied {
+			key := slot.Entry.Key
+			home := table.Home(key)
+			bucket.Key = &key
+			bucket.Value = slot.Entry.Value
+			bucket.Home = &home
+		}
+		table.BucketViews = append(table.BucketViews, bucket)
+
+		// 墓碑同样会拉长探查序列，计入聚集
+		if slot.State == ds.SlotEmpty {
+			run = 0
+		} else {
+			run++
+			table.LongestRun = max(table.LongestRun, run)
+		}
+	}
+	// 探查序列会从末尾回绕到开头，首尾相连的聚集合并计算
+	if run > 0 && run < table.Capacity {
+		for _, slot := range table.Slots {
+			if slot.State == ds.SlotEmpty {
+				break
+			}
+			run++
+		}
+		table.LongestRun = max(table.LongestRun, run)
+	}
+}
+
+// 设置哈希表相关路由
+func setupHashTableRoutes(g *echo.Group) {
+	hashGroup := g.Group("/hashtables")
+
+	// 创建哈希表
+	hashGroup.POST("", createHashTable)
+
+	// 获取所有哈希表
+	hashGroup.GET("", getAllHashTables)
+
+	// 获取指定哈希表
+	hashGroup.GET("/:id", getHashTable)
+
+	// 删除哈希表
+	hashGroup.DELETE("/:id", deleteHashTable)
+
+	// 插入或更新键值对
+	hashGroup.POST("/:id/put", putHashEntry)
+
+	// 按键查找
+	hashGroup.GET("/:id/get/:key", getHashEntry)
+
+	// 按键删除
+	hashGroup.DELETE("/:id/key/:key", deleteHashEntry)
+
+	// 按指定容量重建，清除墓碑
+	hashGroup.POST("/:id/rehash", rehashHashTable)
+}
+
+// 创建哈希表
+func createHashTable(c echo.Context) error {
+	var req HashTableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	req.ElementType = elementType
+
+	res, err := factory.newHashTable(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	id, err := hashTables.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, HashTableResponse{
+			Success: false,
+			Message: "哈希表ID生成失败",
+		})
+	}
+	table := res.header()
+	table.ID = id
+	res.updateVisualizationData()
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	if err := commitHashTable(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, HashTableResponse{
+			Success: false,
+			Message: "哈希表保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, HashTableResponse{
+		Success:   true,
+		Message:   "哈希表创建成功",
+		HashTable: res,
+	})
+}
+
+// 获取所有哈希表
+func getAllHashTables(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的哈希表
+	tableList := make([]json.RawMessage, 0)
+	for _, res := range hashTables.List() {
+		table := res.header()
+		table.mu.Lock()
+		data, err := json.Marshal(res)
+		table.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		tableList = append(tableList, data)
+	}
+
+	return c.JSON(http.StatusOK, HashTableResponse{
+		Success: true,
+		Message: "获取哈希表列表成功",
+		Data:    tableList,
+	})
+}
+
+// 获取指定哈希表
+func getHashTable(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHashTable(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HashTableResponse{
+			Success: false,
+			Message: "哈希表不存在",
+		})
+	}
+	table := res.header()
+	defer table.mu.Unlock()
+
+	setETag(c, table.Version)
+	return c.JSON(http.StatusOK, HashTableResponse{
+		Success:   true,
+		Message:   "获取哈希表成功",
+		HashTable: res,
+	})
+}
+
+// 删除哈希表
+func deleteHashTable(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHashTable(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HashTableResponse{
+			Success: false,
+			Message: "哈希表不存在",
+		})
+	}
+	table := res.header()
+	defer table.mu.Unlock()
+
+	if _, err := hashTables.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, HashTableResponse{
+			Success: false,
+			Message: "哈希表删除失败",
+		})
+	}
+	table.removed = true
+
+	return c.JSON(http.StatusOK, HashTableResponse{
+		Success: true,
+		Message: "哈希表删除成功",
+	})
+}
+
+// 插入或更新键值对
+func putHashEntry(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHashTable(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HashTableResponse{
+			Success: false,
+			Message: "哈希表不存在",
+		})
+	}
+	table := res.header()
+	defer table.mu.Unlock()
+
+	if !ifMatchSatisfied(c, table.Version) {
+		setETag(c, table.Version)
+		return c.JSON(http.StatusPreconditionFailed, HashTableResponse{
+			Success:   false,
+			Message:   fmt.Sprintf("哈希表已被修改（当前版本%d），请刷新后重试", table.Version),
+			HashTable: res,
+		})
+	}
+
+	var req HashPutRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	key, err := res.decodeKey(req.Key)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	if len(req.Value) == 0 {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: ds.ErrMissingValue.Error(),
+		})
+	}
+
+	res.beginOperation()
+	updated := res.putAny(key, req.Value)
+	cost := table.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitHashTable(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, HashTableResponse{
+			Success: false,
+			Message: "哈希表保存失败",
+		})
+	}
+
+	message := fmt.Sprintf("键%v已插入哈希表", key)
+	if updated {
+		message = fmt.Sprintf("键%v的值已更新", key)
+	}
+	return c.JSON(http.StatusOK, HashTableResponse{
+		Success:   true,
+		Message:   message,
+		HashTable: res,
+		Probes:    res.probeSequence(),
+		Cost:      &cost,
+		Trace:     table.recorder.Trace(),
+	})
+}
+
+// 按键查找
+func getHashEntry(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHashTable(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HashTableResponse{
+			Success: false,
+			Message: "哈希表不存在",
+		})
+	}
+	table := res.header()
+	defer table.mu.Unlock()
+
+	key, err := res.parseKey(c.Param("key"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: fmt.Sprintf("键格式错误：%v", err),
+		})
+	}
+
+	res.beginOperation()
+	value, err := res.getAny(key)
+	cost := table.recorder.Cost()
+	if errors.Is(err, ds.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, HashTableResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到键%v", key),
+			Probes:  res.probeSequence(),
+			Cost:    &cost,
+			Trace:   table.recorder.Trace(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, HashTableResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("找到键%v，探查了%d个位置", key, len(res.probeSequence())),
+		HashTable: res,
+		Data:      value,
+		Probes:    res.probeSequence(),
+		Cost:      &cost,
+		Trace:     table.recorder.Trace(),
+	})
+}
+
+// 按键删除
+func deleteHashEntry(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHashTable(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HashTableResponse{
+			Success: false,
+			Message: "哈希表不存在",
+		})
+	}
+	table := res.header()
+	defer table.mu.Unlock()
+
+	if !ifMatchSatisfied(c, table.Version) {
+		setETag(c, table.Version)
+		return c.JSON(http.StatusPreconditionFailed, HashTableResponse{
+			Success:   false,
+			Message:   fmt.Sprintf("哈希表已被修改（当前版本%d），请刷新后重试", table.Version),
+			HashTable: res,
+		})
+	}
+
+	key, err := res.parseKey(c.Param("key"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: fmt.Sprintf("键格式错误：%v", err),
+		})
+	}
+
+	res.beginOperation()
+	value, err := res.deleteAny(key)
+	if errors.Is(err, ds.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, HashTableResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到键%v", key),
+			Probes:  res.probeSequence(),
+			Trace:   table.recorder.Trace(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := table.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitHashTable(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, HashTableResponse{
+			Success: false,
+			Message: "哈希表保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, HashTableResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("成功删除键%v", key),
+		HashTable: res,
+		Data:      value,
+		Probes:    res.probeSequence(),
+		Cost:      &cost,
+		Trace:     table.recorder.Trace(),
+	})
+}
+
+// 按指定容量重建
+func rehashHashTable(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHashTable(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HashTableResponse{
+			Success: false,
+			Message: "哈希表不存在",
+		})
+	}
+	table := res.header()
+	defer table.mu.Unlock()
+
+	if !ifMatchSatisfied(c, table.Version) {
+		setETag(c, table.Version)
+		return c.JSON(http.StatusPreconditionFailed, HashTableResponse{
+			Success:   false,
+			Message:   fmt.Sprintf("哈希表已被修改（当前版本%d），请刷新后重试", table.Version),
+			HashTable: res,
+		})
+	}
+
+	var req RehashRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	res.beginOperation()
+	if err := res.rehashTo(req.Capacity); err != nil {
+		return c.JSON(http.StatusBadRequest, HashTableResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	cost := table.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitHashTable(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, HashTableResponse{
+			Success: false,
+			Message: "哈希表保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, HashTableResponse{
+		Success:   true,
+		Message:   "哈希表重建完成",
+		HashTable: res,
+		Cost:      &cost,
+		Trace:     table.recorder.Trace(),
+	})
+}
@@ -0,0 +1,751 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// heapHeader 堆中与元素类型无关的服务端状态：标识、版本和锁
+type heapHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	recorder *ds.Recorder // 底层数组最近一次操作的计数和追踪
+
+	mu      sync.Mutex // 串行化对同一堆的操作，不同堆之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// Heap 二叉堆结构体，操作由 ds.Heap 实现；同时给出数组布局和树形视图
+type Heap[T any] struct {
+	heapHeader
+	*ds.Heap[T]
+
+	Size   int           `json:"size"`
+	Top    *T            `json:"top"`   // 空堆为 null
+	Layout *ds.Array[T]  `json:"array"` // 数组布局：容量和按层序排列的元素
+	Tree   []HeapNode[T] `json:"tree"`  // 树形视图，与数组下标一一对应
+}
+
+// HeapNode 树形视图中的节点，父子关系以数组下标表示
+type HeapNode[T any] struct {
+	Index  int  `json:"index"`
+	Value  T    `json:"value"`
+	Depth  int  `json:"depth"`  // 根节点为0
+	Parent *int `json:"parent"` // 根节点为 null
+	Left   *int `json:"left"`   // 没有左子节点时为 null
+	Right  *int `json:"right"`  // 没有右子节点时为 null
+}
+
+// HeapSortResult 堆排序结果
+type HeapSortResult struct {
+	Order    string `json:"order"` // 大顶堆为 asc，小顶堆为 desc
+	Elements any    `json:"elements"`
+}
+
+// heapResource 与元素类型无关的堆接口，处理函数通过它操作任意元素类型的 Heap[T]
+type heapResource interface {
+	header() *heapHeader
+	decodeValue(raw json.RawMessage) (any, error)
+	beginOperation()
+	insertAny(value any) (*ds.ResizeEvent, error)
+	extractAny() (any, *ds.ResizeEvent, error)
+	peekAny() (any, error)
+	decreaseKeyAny(index int, value any) (any, error)
+	sortElements() (*HeapSortResult, *ds.Recorder)
+	updateVisualizationData()
+	snapshot() any
+}
+
+// HeapRequest 创建空堆的请求，可以指定底层数组的容量和扩缩容参数
+type HeapRequest struct {
+	Name            string  `json:"name"`
+	ElementType     string  `json:"elementType"`
+	Type            string  `json:"type"` // min（默认）或 max
+	Capacity        int     `json:"capacity"`
+	GrowthStrategy  string  `json:"growthStrategy"`
+	GrowthIncrement int     `json:"growthIncrement"`
+	ShrinkFactor    float64 `json:"shrinkFactor"`
+}
+
+// HeapifyRequest 从已有动态数组建堆的请求，元素类型和数组参数沿用源数组，源数组本身不被修改
+type HeapifyRequest struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"` // min（默认）或 max
+	ArrayID string `json:"arrayId"`
+}
+
+// HeapValueRequest 插入请求，Value 按堆的元素类型解码
+type HeapValueRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// HeapKeyRequest 修改键值请求：把数组下标 Index 处的元素改为 Value
+type HeapKeyRequest struct {
+	Index int             `json:"index"`
+	Value json.RawMessage `json:"value"`
+}
+
+// HeapResponse 堆操作响应结构体
+type HeapResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Heap    heapResource      `json:"heap,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Resize  *ds.ResizeEvent   `json:"resize,omitempty"`
+	Cost    *ds.OperationCost `json:"cost,omitempty"`
+	Trace   []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局堆存储，后端由 initStorage 根据配置选择
+var heaps Storage[heapResource] = newMemoryStorage[heapResource]("heap")
+
+// 获取堆并加锁，调用方负责解锁；堆不存在或已被删除时返回 false
+func lockHeap(id string) (heapResource, bool) {
+	res, exists := heaps.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	heap := res.header()
+	heap.mu.Lock()
+	if heap.removed {
+		heap.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitHeap(c echo.Context, res heapResource) error {
+	heap := res.header()
+	heap.Version++
+	if err := heaps.Save(heap.ID, res); err != nil {
+		return err
+	}
+	setETag(c, heap.Version)
+	return nil
+}
+
+// 底层数组的配置
+func (req HeapRequest) arrayConfig() ds.ArrayConfig {
+	return ds.ArrayConfig{
+		Capacity:        req.Capacity,
+		GrowthStrategy:  req.GrowthStrategy,
+		GrowthIncrement: req.GrowthIncrement,
+		ShrinkFactor:    req.ShrinkFactor,
+	}
+}
+
+// 按请求创建元素类型为 T 的空堆，堆类型或数组参数无效时返回错误；ID 由调用方在校验通过后分配
+func newHeap[T any](req HeapRequest, kind *ds.ElementType[T]) (*Heap[T], error) {
+	core, err := ds.NewHeap(req.Type, req.arrayConfig(), kind)
+	if err != nil {
+		return nil, err
+	}
+	return wrapHeap("", req.Name, core), nil
+}
+
+// 复制动态数组的元素并建堆，建堆过程的计数和追踪留在堆的 Recorder 中
+func heapifyArray[T any](req HeapifyRequest, source *DynamicArray[T]) (*Heap[T], error) {
+	core, err := ds.Heapify(req.Type, source.Config(), source.Values(), source.Kind())
+	if err != nil {
+		return nil, err
+	}
+	return wrapHeap("", req.Name, core), nil
+}
+
+// 为 ds.Heap 附加服务端状态
+func wrapHeap[T any](id, name string, core *ds.Heap[T]) *Heap[T] {
+	heap := &Heap[T]{Heap: core}
+	heap.ID = id
+	heap.Name = name
+	heap.ElementType = core.Kind().Name
+	heap.recorder = core.Recorder()
+	heap.updateVisualizationData()
+	return heap
+}
+
+// 堆的持久化快照，元素按层序保存
+type heapSnapshot[T any] struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	ElementType string         `json:"elementType"`
+	Version     int64          `json:"version"`
+	Type        string         `json:"type"`
+	Array       ds.ArrayConfig `json:"array"` // 底层数组的当前容量和扩缩容参数
+	Values      []T            `json:"values"`
+}
+
+// 生成堆快照
+func snapshotHeap(res heapResource) any {
+	return res.snapshot()
+}
+
+func (heap *Heap[T]) snapshot() any {
+	return heapSnapshot[T]{
+		ID:          heap.ID,
+		Name:        heap.Name,
+		ElementType: heap.ElementType,
+		Version:     heap.Version,
+		Type:        heap.Type,
+		Array:       heap.Array().Config(),
+		Values:      heap.Values(),
+	}
+}
+
+// 从快照恢复堆，按快照中的元素类型分发
+func restoreHeap(data []byte) (heapResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreHeap(data)
+}
+
+// 从快照重建元素类型为 T 的堆
+func restoreTypedHeap[T any](data []byte, kind *ds.ElementType[T]) (*Heap[T], error) {
+	var snapshot heapSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreHeap(snapshot.Type, snapshot.Array, snapshot.Values, kind)
+	if err != nil {
+		return nil, err
+	}
+	heap := wrapHeap(snapshot.ID, snapshot.Name, core)
+	heap.Version = snapshot.Version
+	return heap, nil
+}
+
+func (heap *Heap[T]) header() *heapHeader {
+	return &heap.heapHeader
+}
+
+func (heap *Heap[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return heap.Kind().Decode(raw)
+}
+
+func (heap *Heap[T]) beginOperation() {
+	heap.Begin()
+}
+
+func (heap *Heap[T]) insertAny(value any) (*ds.ResizeEvent, error) {
+	return heap.Insert(valueOf[T](value))
+}
+
+func (heap *Heap[T]) extractAny() (any, *ds.ResizeEvent, error) {
+	return heap.Extract()
+}
+
+func (heap *Heap[T]) peekAny() (any, error) {
+	return heap.Peek()
+}
+
+func (heap *Heap[T]) decreaseKeyAny(index int, value any) (any, error) {
+	return heap.DecreaseKey(index, valueOf[T](value))
+}
+
+func (heap *Heap[T]) sortElements() (*HeapSortResult, *ds.Recorder) {
+	elements, recorder := heap.Sort()
+	order := ds.OrderDesc
+	if heap.Type == ds.HeapMax {
+		order = ds.OrderAsc
+	}
+	return &HeapSortResult{Order: order, Elements: elements}, recorder
+}
+
+// 更新堆顶、数组布局和树形视图
+func (heap *Heap[T]) updateVisualizationData() {
+	values := heap.Values()
+	heap.Size = len(values)
+	heap.Layout = heap.Array()
+
+	heap.Top = nil
+	if heap.Size > 0 {
+		heap.Top = &values[0]
+	}
+
+	heap.Tree = make([]HeapNode[T], heap.Size)
+	for i, value := range values {
+		node := HeapNode[T]{Index: i, Value: value}
+		for j := i; j > 0; j = (j - 1) / 2 {
+			node.Depth++
+		}
+		if i > 0 {
+			parent := (i - 1) / 2
+			node.Parent = &parent
+		}
+		if left := 2*i + 1; left < heap.Size {
+			node.Left = &left
+		}
+		if right := 2*i + 2; right < heap.Size {
+			node.Right = &right
+		}
+		heap.Tree[i] = node
+	}
+}
+
+// 设置堆相关路由
+func setupHeapRoutes(g *echo.Group) {
+	heapGroup := g.Group("/heaps")
+
+	// 创建空堆
+	heapGroup.POST("", createHeap)
+
+	// 从已有动态数组建堆
+	heapGroup.POST("/heapify", heapifyFromArray)
+
+	// 获取所有堆
+	heapGroup.GET("", getAllHeaps)
+
+	// 获取指定堆
+	heapGroup.GET("/:id", getHeap)
+
+	// 删除堆
+	heapGroup.DELETE("/:id", deleteHeap)
+
+	// 插入元素
+	heapGroup.POST("/:id/insert", insertHeap)
+
+	// 取出堆顶
+	heapGroup.POST("/:id/extract", extractHeap)
+
+	// 查看堆顶
+	heapGroup.GET("/:id/peek", peekHeap)
+
+	// 提高元素优先级（小顶堆减小键值，大顶堆增大键值）
+	heapGroup.POST("/:id/decrease_key", decreaseHeapKey)
+
+	// 堆排序（在副本上进行，不修改堆）
+	heapGroup.POST("/:id/sort", sortHeap)
+}
+
+// 保存新建的堆：分配ID、持久化并返回创建结果，建堆过程的追踪一并返回
+func saveNewHeap(c echo.Context, res heapResource, message string) error {
+	id, err := heaps.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, HeapResponse{
+			Success: false,
+			Message: "堆ID生成失败",
+		})
+	}
+	heap := res.header()
+	heap.ID = id
+	res.updateVisualizationData()
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	heap.mu.Lock()
+	defer heap.mu.Unlock()
+
+	if err := commitHeap(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, HeapResponse{
+			Success: false,
+			Message: "堆保存失败",
+		})
+	}
+
+	cost := heap.recorder.Cost()
+	return c.JSON(http.StatusCreated, HeapResponse{
+		Success: true,
+		Message: message,
+		Heap:    res,
+		Cost:    &cost,
+		Trace:   heap.recorder.Trace(),
+	})
+}
+
+// 创建空堆
+func createHeap(c echo.Context) error {
+	var req HeapRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	req.ElementType = elementType
+
+	res, err := factory.newHeap(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return saveNewHeap(c, res, "堆创建成功")
+}
+
+// 从已有动态数组建堆
+func heapifyFromArray(c echo.Context) error {
+	var req HeapifyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	source, exists := lockArray(req.ArrayID)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "数组不存在",
+		})
+	}
+	factory, _, err := elementFactoryFor(source.header().ElementType)
+	if err != nil {
+		source.header().mu.Unlock()
+		return err
+	}
+	res, err := factory.heapifyArray(req, source)
+	source.header().mu.Unlock()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return saveNewHeap(c, res, fmt.Sprintf("已从数组%s建堆", req.ArrayID))
+}
+
+// 获取所有堆
+func getAllHeaps(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的堆
+	heapList := make([]json.RawMessage, 0)
+	for _, res := range heaps.List() {
+		heap := res.header()
+		heap.mu.Lock()
+		data, err := json.Marshal(res)
+		heap.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		heapList = append(heapList, data)
+	}
+
+	return c.JSON(http.StatusOK, HeapResponse{
+		Success: true,
+		Message: "获取堆列表成功",
+		Data:    heapList,
+	})
+}
+
+// 获取指定堆
+func getHeap(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHeap(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "堆不存在",
+		})
+	}
+	heap := res.header()
+	defer heap.mu.Unlock()
+
+	setETag(c, heap.Version)
+	return c.JSON(http.StatusOK, HeapResponse{
+		Success: true,
+		Message: "获取堆成功",
+		Heap:    res,
+	})
+}
+
+// 删除堆
+func deleteHeap(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHeap(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "堆不存在",
+		})
+	}
+	heap := res.header()
+	defer heap.mu.Unlock()
+
+	if _, err := heaps.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, HeapResponse{
+			Success: false,
+			Message: "堆删除失败",
+		})
+	}
+	heap.removed = true
+
+	return c.JSON(http.StatusOK, HeapResponse{
+		Success: true,
+		Message: "堆删除成功",
+	})
+}
+
+// 插入元素
+func insertHeap(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHeap(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "堆不存在",
+		})
+	}
+	heap := res.header()
+	defer heap.mu.Unlock()
+
+	if !ifMatchSatisfied(c, heap.Version) {
+		setETag(c, heap.Version)
+		return c.JSON(http.StatusPreconditionFailed, HeapResponse{
+			Success: false,
+			Message: fmt.Sprintf("堆已被修改（当前版本%d），请刷新后重试", heap.Version),
+			Heap:    res,
+		})
+	}
+
+	var req HeapValueRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	res.beginOperation()
+	resize, err := res.insertAny(value)
+	if errors.Is(err, ds.ErrFull) {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: "堆已满，无法插入",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := heap.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitHeap(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, HeapResponse{
+			Success: false,
+			Message: "堆保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, HeapResponse{
+		Success: true,
+		Message: withResizeMessage(fmt.Sprintf("%v已插入堆", value), resize),
+		Heap:    res,
+		Resize:  resize,
+		Cost:    &cost,
+		Trace:   heap.recorder.Trace(),
+	})
+}
+
+// 取出堆顶元素
+func extractHeap(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHeap(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "堆不存在",
+		})
+	}
+	heap := res.header()
+	defer heap.mu.Unlock()
+
+	if !ifMatchSatisfied(c, heap.Version) {
+		setETag(c, heap.Version)
+		return c.JSON(http.StatusPreconditionFailed, HeapResponse{
+			Success: false,
+			Message: fmt.Sprintf("堆已被修改（当前版本%d），请刷新后重试", heap.Version),
+			Heap:    res,
+		})
+	}
+
+	res.beginOperation()
+	value, resize, err := res.extractAny()
+	if errors.Is(err, ds.ErrEmpty) {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: "堆为空，无法取出堆顶",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := heap.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitHeap(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, HeapResponse{
+			Success: false,
+			Message: "堆保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, HeapResponse{
+		Success: true,
+		Message: withResizeMessage(fmt.Sprintf("堆顶元素%v已取出", value), resize),
+		Heap:    res,
+		Data:    value,
+		Resize:  resize,
+		Cost:    &cost,
+		Trace:   heap.recorder.Trace(),
+	})
+}
+
+// 查看堆顶元素
+func peekHeap(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHeap(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "堆不存在",
+		})
+	}
+	heap := res.header()
+	defer heap.mu.Unlock()
+
+	res.beginOperation()
+	value, err := res.peekAny()
+	if errors.Is(err, ds.ErrEmpty) {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "堆为空",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := heap.recorder.Cost()
+
+	return c.JSON(http.StatusOK, HeapResponse{
+		Success: true,
+		Message: fmt.Sprintf("堆顶元素为%v", value),
+		Heap:    res,
+		Data:    value,
+		Cost:    &cost,
+		Trace:   heap.recorder.Trace(),
+	})
+}
+
+// 修改键值以提高元素优先级，随后上浮
+func decreaseHeapKey(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHeap(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "堆不存在",
+		})
+	}
+	heap := res.header()
+	defer heap.mu.Unlock()
+
+	if !ifMatchSatisfied(c, heap.Version) {
+		setETag(c, heap.Version)
+		return c.JSON(http.StatusPreconditionFailed, HeapResponse{
+			Success: false,
+			Message: fmt.Sprintf("堆已被修改（当前版本%d），请刷新后重试", heap.Version),
+			Heap:    res,
+		})
+	}
+
+	var req HeapKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	res.beginOperation()
+	oldValue, err := res.decreaseKeyAny(req.Index, value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, HeapResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	cost := heap.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitHeap(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, HeapResponse{
+			Success: false,
+			Message: "堆保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, HeapResponse{
+		Success: true,
+		Message: fmt.Sprintf("索引%d处的键值已从%v改为%v", req.Index, oldValue, value),
+		Heap:    res,
+		Data:    oldValue,
+		Cost:    &cost,
+		Trace:   heap.recorder.Trace(),
+	})
+}
+
+// 堆排序：在副本上反复取出堆顶，返回有序元素和过程，不修改堆
+func sortHeap(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockHeap(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, HeapResponse{
+			Success: false,
+			Message: "堆不存在",
+		})
+	}
+	heap := res.header()
+	defer heap.mu.Unlock()
+
+	result, recorder := res.sortElements()
+	cost := recorder.Cost()
+
+	return c.JSON(http.StatusOK, HeapResponse{
+		Success: true,
+		Message: "堆排序完成",
+		Heap:    res,
+		Data:    result,
+		Cost:    &cost,
+		Trace:   recorder.Trace(),
+	})
+}
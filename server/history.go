@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// 每个数据结构最多保留的历史记录数
+const maxHistoryEntries = 100
+
+// 历史记录的命令类型
+const (
+	CommandInsert  = "insert"  // 在 Index 处插入 Value
+	CommandDelete  = "delete"  // 删除 Index 处的 Value
+	CommandUpdate  = "update"  // 将 Index 处的 OldValue 改为 Value
+	CommandReverse = "reverse" // 反转整个结构，再执行一次即可撤销
+	CommandReplace = "replace" // 将全部元素从 Before 替换为 After，如排序
+)
+
+var (
+	errNothingToUndo = errors.New("没有可撤销的操作")
+	errNothingToRedo = errors.New("没有可重做的操作")
+)
+
+// HistoryEntry 一条可逆的修改命令，T 为数据结构的元素类型
+type HistoryEntry[T any] struct {
+	Operation string    `json:"operation"` // 触发修改的接口，如 append、delete_value
+	Command   string    `json:"command"`   // insert、delete、update、reverse 或 replace
+	Index     int       `json:"index"`
+	Value     T         `json:"value"`
+	OldValue  T         `json:"oldValue,omitempty"`
+	Before    []T       `json:"before,omitempty"` // replace 命令执行前的全部元素
+	After     []T       `json:"after,omitempty"`  // replace 命令执行后的全部元素
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// History 撤销/重做历史，Cursor 之前的命令已生效，之后的命令已撤销、可以重做
+type History[T any] struct {
+	Entries []HistoryEntry[T] `json:"entries"`
+	Cursor  int               `json:"cursor"`
+}
+
+// HistoryView 历史记录的展示数据
+type HistoryView[T any] struct {
+	Entries []HistoryEntry[T] `json:"entries"`
+	Cursor  int               `json:"cursor"`
+	CanUndo bool              `json:"canUndo"`
+	CanRedo bool              `json:"canRedo"`
+}
+
+// 与元素类型无关的历史命令，供撤销/重做的处理函数使用
+type historyCommand interface {
+	operation() string
+	sizeDelta(undo bool) int
+}
+
+// 可以执行历史命令的数据结构
+type editable[T any] interface {
+	insertValue(index int, value T) error
+	removeValue(index int)
+	setValue(index int, value T)
+}
+
+// 支持原地反转的数据结构
+type reversible interface {
+	reverseValues()
+}
+
+// 支持整体替换元素的数据结构
+type replaceable[T any] interface {
+	replaceValues(values []T)
+}
+
+var (
+	errNotReversible  = errors.New("该数据结构不支持反转")
+	errNotReplaceable = errors.New("该数据结构不支持整体替换")
+)
+
+// 执行反转命令
+func applyReverse[T any](target editable[T]) error {
+	r, ok := target.(reversible)
+	if !ok {
+		return errNotReversible
+	}
+	r.reverseValues()
+	return nil
+}
+
+// 执行整体替换命令
+func applyReplace[T any](target editable[T], values []T) error {
+	r, ok := target.(replaceable[T])
+	if !ok {
+		return errNotReplaceable
+	}
+	r.replaceValues(values)
+	return nil
+}
+
+// 记录一条新命令，并丢弃所有已撤销的命令
+func (h *History[T]) record(operation, command string, index int, value, oldValue T) {
+	h.Entries = append(h.Entries[:h.Cursor], HistoryEntry[T]{
+		Operation: operation,
+		Command:   command,
+		Index:     index,
+		Value:     value,
+		OldValue:  oldValue,
+		Timestamp: time.Now(),
+	})
+	if len(h.Entries) > maxHistoryEntries {
+		h.Entries = h.Entries[len(h.Entries)-maxHistoryEntries:]
+	}
+	h.Cursor = len(h.Entries)
+}
+
+// 记录一条整体替换命令
+func (h *History[T]) recordReplace(operation string, before, after []T) {
+	var zero T
+	h.record(operation, CommandReplace, 0, zero, zero)
+	entry := &h.Entries[len(h.Entries)-1]
+	entry.Before = before
+	entry.After = after
+}
+
+// 撤销最近一条生效的命令
+func (h *History[T]) undo(target editable[T]) (*HistoryEntry[T], error) {
+	if h.Cursor == 0 {
+		return nil, errNothingToUndo
+	}
+
+	entry := h.Entries[h.Cursor-1]
+	switch entry.Command {
+	case CommandInsert:
+		target.removeValue(entry.Index)
+	case CommandDelete:
+		if err := target.insertValue(entry.Index, entry.Value); err != nil {
+			return nil, err
+		}
+	case CommandUpdate:
+		target.setValue(entry.Index, entry.OldValue)
+	case CommandReverse:
+		if err := applyReverse(target); err != nil {
+			return nil, err
+		}
+	case CommandReplace:
+		if err := applyReplace(target, entry.Before); err != nil {
+			return nil, err
+		}
+	}
+
+	h.Cursor--
+	return &entry, nil
+}
+
+// 重做最近一条被撤销的命令
+func (h *History[T]) redo(target editable[T]) (*HistoryEntry[T], error) {
+	if h.Cursor == len(h.Entries) {
+		return nil, errNothingToRedo
+	}
+
+	entry := h.Entries[h.Cursor]
+	switch entry.Command {
+	case CommandInsert:
+		if err := target.insertValue(entry.Index, entry.Value); err != nil {
+			return nil, err
+		}
+	case CommandDelete:
+		target.removeValue(entry.Index)
+	case CommandUpdate:
+		target.setValue(entry.Index, entry.Value)
+	case CommandReverse:
+		if err := applyReverse(target); err != nil {
+			return nil, err
+		}
+	case CommandReplace:
+		if err := applyReplace(target, entry.After); err != nil {
+			return nil, err
+		}
+	}
+
+	h.Cursor++
+	return &entry, nil
+}
+
+// 生成历史记录的展示数据
+func (h *History[T]) view() HistoryView[T] {
+	entries := h.Entries
+	if entries == nil {
+		entries = make([]HistoryEntry[T], 0)
+	}
+
+	return HistoryView[T]{
+		Entries: entries,
+		Cursor:  h.Cursor,
+		CanUndo: h.Cursor > 0,
+		CanRedo: h.Cursor < len(h.Entries),
+	}
+}
+
+// 触发命令的接口名
+func (e *HistoryEntry[T]) operation() string {
+	return e.Operation
+}
+
+// 命令执行后数据结构的元素数变化，undo 为真时取反
+func (e *HistoryEntry[T]) sizeDelta(undo bool) int {
+	delta := 0
+	switch e.Command {
+	case CommandInsert:
+		delta = 1
+	case CommandDelete:
+		delta = -1
+	}
+	if undo {
+		delta = -delta
+	}
+	return delta
+}
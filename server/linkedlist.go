@@ -1,67 +1,194 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
+
+	"echo/ds"
 
 	"github.com/labstack/echo/v4"
 )
 
-// Node 链表节点结构体
-type Node struct {
-	Value int   `json:"value"`
-	Next  *Node `json:"next,omitempty"`
-	Prev  *Node `json:"prev,omitempty"`
-}
-
 // NodeData 用于前端显示的节点数据
-type NodeData struct {
-	Value  int    `json:"value"`
+type NodeData[T any] struct {
+	Value  T      `json:"value"`
 	NextID string `json:"nextId,omitempty"`
 	PrevID string `json:"prevId,omitempty"`
 	ID     string `json:"id"`
 }
 
-// LinkedList 链表结构体
-type LinkedList struct {
-	ID    string      `json:"id"`
-	Name  string      `json:"name"`
-	Type  string      `json:"type"` // "single", "double", "circular"
-	Head  *Node       `json:"-"`
-	Tail  *Node       `json:"-"`
-	Size  int         `json:"size"`
-	Nodes []*NodeData `json:"nodes"`
+// listHeader 链表中与元素类型无关的服务端状态：标识、版本和锁
+type listHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	state *ds.ListState // 指向链表的类型、长度和执行追踪
+
+	mu      sync.Mutex // 串行化对同一链表的操作，不同链表之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// LinkedList 链表结构体，数据结构操作由 ds.LinkedList 实现，T 为创建时选定的元素类型
+type LinkedList[T any] struct {
+	listHeader
+	*ds.LinkedList[T]
+	Nodes []*NodeData[T] `json:"nodes"`
+
+	History History[T] `json:"-"` // 撤销/重做历史
+}
+
+// listResource 与元素类型无关的链表接口，处理函数通过它操作任意元素类型的 LinkedList[T]
+type listResource interface {
+	header() *listHeader
+	parseValue(s string) (any, error)
+	decodeValue(raw json.RawMessage) (any, error)
+	beginOperation()
+	insertAny(index int, value any) error
+	removeAny(index int) (any, error)
+	setAny(index int, value any) (any, error)
+	indexOfAny(value any) int
+	reverse(mode string) error
+	updateVisualizationData()
+	recordHistory(operation, command string, index int, value, oldValue any)
+	replayHistory(undo bool) (historyCommand, error)
+	historyView() any
+	snapshot() any
 }
 
 // LinkedListRequest 链表操作请求结构体
 type LinkedListRequest struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	ElementType string `json:"elementType"` // int（默认）、float、string、bool 或 object
+}
+
+// ReverseRequest 反转链表请求结构体
+type ReverseRequest struct {
+	Mode string `json:"mode"` // "iterative"（默认）或 "recursive"
 }
 
-// NodeRequest 节点操作请求结构体
+// NodeRequest 节点操作请求结构体，Value 按链表的元素类型解码
 type NodeRequest struct {
-	Value int `json:"value"`
-	Index int `json:"index"`
+	Value json.RawMessage `json:"value"`
+	Index int             `json:"index"`
 }
 
 // LinkedListResponse 链表操作响应结构体
 type LinkedListResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	List    *LinkedList `json:"list,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	List    listResource   `json:"list,omitempty"`
+	Data    interface{}    `json:"data,omitempty"`
+	Trace   []ds.TraceStep `json:"trace,omitempty"`
 }
 
-// 全局链表存储
-var linkedLists = make(map[string]*LinkedList)
-var listCounter = 0
+// 全局链表存储，后端由 initStorage 根据配置选择
+var linkedLists Storage[listResource] = newMemoryStorage[listResource]("list")
 
-// 生成链表ID
-func generateListID() string {
-	listCounter++
-	return fmt.Sprintf("list_%d", listCounter)
+// 获取链表并加锁，调用方负责解锁；链表不存在或已被删除时返回 false
+func lockList(id string) (listResource, bool) {
+	res, exists := linkedLists.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	list := res.header()
+	list.mu.Lock()
+	if list.removed {
+		list.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitList(c echo.Context, res listResource) error {
+	list := res.header()
+	list.Version++
+	if err := linkedLists.Save(list.ID, res); err != nil {
+		return err
+	}
+	setETag(c, list.Version)
+	return nil
+}
+
+// 按请求创建元素类型为 T 的空链表，链表类型无效时返回错误；ID 由调用方在校验通过后分配
+func newLinkedList[T any](req LinkedListRequest, kind *ds.ElementType[T]) (*LinkedList[T], error) {
+	core, err := ds.NewLinkedList(req.Type, kind)
+	if err != nil {
+		return nil, err
+	}
+	return wrapLinkedList("", req.Name, core), nil
+}
+
+// 为 ds.LinkedList 附加服务端状态
+func wrapLinkedList[T any](id, name string, core *ds.LinkedList[T]) *LinkedList[T] {
+	list := &LinkedList[T]{
+		LinkedList: core,
+		Nodes:      make([]*NodeData[T], 0),
+	}
+	list.ID = id
+	list.Name = name
+	list.ElementType = core.Kind().Name
+	list.state = &core.ListState
+	return list
+}
+
+// 链表的持久化快照，节点按从头到尾的顺序保存为值序列
+type listSnapshot[T any] struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	ElementType string     `json:"elementType"`
+	Version     int64      `json:"version"`
+	Values      []T        `json:"values"`
+	History     History[T] `json:"history"`
+}
+
+// 生成链表快照
+func snapshotList(res listResource) any {
+	return res.snapshot()
+}
+
+func (list *LinkedList[T]) snapshot() any {
+	return listSnapshot[T]{
+		ID:          list.ID,
+		Name:        list.Name,
+		Type:        list.Type,
+		ElementType: list.ElementType,
+		Version:     list.Version,
+		Values:      list.Values(),
+		History:     list.History,
+	}
+}
+
+// 从快照恢复链表，按快照中的元素类型分发
+func restoreList(data []byte) (listResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreList(data)
+}
+
+// 从快照重建元素类型为 T 的链表的节点和指针
+func restoreTypedList[T any](data []byte, kind *ds.ElementType[T]) (*LinkedList[T], error) {
+	var snapshot listSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	list := wrapLinkedList(snapshot.ID, snapshot.Name, ds.RestoreLinkedList(snapshot.Type, snapshot.Values, kind))
+	list.Version = snapshot.Version
+	list.History = snapshot.History
+
+	list.updateVisualizationData()
+	return list, nil
 }
 
 // 生成节点ID
@@ -105,43 +232,153 @@ func setupLinkedListRoutes(g *echo.Group) {
 
 	// 修改节点
 	listGroup.PUT("/:id/index/:index", updateNode)
+
+	// 原地反转链表
+	listGroup.POST("/:id/reverse", reverseLinkedList)
+
+	// 撤销最近一次修改
+	listGroup.POST("/:id/undo", undoList)
+
+	// 重做最近一次撤销的修改
+	listGroup.POST("/:id/redo", redoList)
+
+	// 获取修改历史
+	listGroup.GET("/:id/history", getListHistory)
+
+	// 以 SSE 回放操作步骤
+	listGroup.GET("/:id/ops/stream", streamListOperations)
+
+	// 控制回放（暂停、继续、单步、调速）
+	listGroup.POST("/:id/ops/control", controlListPlayback)
+}
+
+// 结束一次操作：推送追踪供回放
+func (list *listHeader) endOperation(op string) {
+	publishTrace("lists", list.ID, op, list.trace())
+}
+
+// 最近一次操作的执行追踪
+func (list *listHeader) trace() []ds.TraceStep {
+	return list.state.Trace()
+}
+
+// 历史命令使用的反转接口
+func (list *LinkedList[T]) reverseValues() {
+	list.Reverse(ds.ReverseIterative)
+}
+
+// 历史命令使用的插入接口
+func (list *LinkedList[T]) insertValue(index int, value T) error {
+	return list.InsertAt(index, value)
+}
+
+// 历史命令使用的删除接口
+func (list *LinkedList[T]) removeValue(index int) {
+	list.RemoveAt(index)
+}
+
+// 历史命令使用的修改接口
+func (list *LinkedList[T]) setValue(index int, value T) {
+	list.SetAt(index, value)
+}
+
+func (list *LinkedList[T]) header() *listHeader {
+	return &list.listHeader
+}
+
+func (list *LinkedList[T]) parseValue(s string) (any, error) {
+	return list.Kind().Parse(s)
+}
+
+func (list *LinkedList[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return list.Kind().Decode(raw)
+}
+
+// 开始一次操作：清空追踪并按当前位置为每个节点编号
+func (list *LinkedList[T]) beginOperation() {
+	list.Begin()
+}
+
+func (list *LinkedList[T]) insertAny(index int, value any) error {
+	return list.InsertAt(index, valueOf[T](value))
+}
+
+func (list *LinkedList[T]) removeAny(index int) (any, error) {
+	return list.RemoveAt(index)
+}
+
+func (list *LinkedList[T]) setAny(index int, value any) (any, error) {
+	return list.SetAt(index, valueOf[T](value))
+}
+
+func (list *LinkedList[T]) indexOfAny(value any) int {
+	return list.IndexOf(valueOf[T](value))
+}
+
+func (list *LinkedList[T]) reverse(mode string) error {
+	return list.Reverse(mode)
+}
+
+func (list *LinkedList[T]) recordHistory(operation, command string, index int, value, oldValue any) {
+	list.History.record(operation, command, index, valueOf[T](value), valueOf[T](oldValue))
+}
+
+func (list *LinkedList[T]) replayHistory(undo bool) (historyCommand, error) {
+	var entry *HistoryEntry[T]
+	var err error
+	if undo {
+		entry, err = list.History.undo(list)
+	} else {
+		entry, err = list.History.redo(list)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (list *LinkedList[T]) historyView() any {
+	return list.History.view()
 }
 
 // 更新链表的可视化数据
-func (list *LinkedList) updateVisualizationData() {
-	list.Nodes = make([]*NodeData, 0, list.Size)
+func (list *LinkedList[T]) updateVisualizationData() {
+	list.Nodes = nodeViews(list.ID, list.LinkedList)
+}
+
+// 从头节点开始生成各节点的显示数据，节点ID以 prefix 为前缀
+func nodeViews[T any](prefix string, list *ds.LinkedList[T]) []*NodeData[T] {
+	nodes := make([]*NodeData[T], 0, list.Size)
 
 	if list.Head == nil {
-		return
+		return nodes
 	}
 
 	current := list.Head
 	index := 0
 
 	for current != nil {
-		nodeData := &NodeData{
+		nodeData := &NodeData[T]{
 			Value: current.Value,
-			ID:    generateNodeID(list.ID, index),
+			ID:    generateNodeID(prefix, index),
 		}
 
 		// 设置下一个节点的ID
 		if current.Next != nil {
-			nodeData.NextID = generateNodeID(list.ID, index+1)
+			nodeData.NextID = generateNodeID(prefix, index+1)
 		}
 
-		// 设置前一个节点的ID（双向链表）
-		if list.Type == "double" && current.Prev != nil {
-			if index > 0 {
-				nodeData.PrevID = generateNodeID(list.ID, index-1)
-			}
+		// 设置前一个节点的ID（双向链表；双向循环链表的头节点指回尾节点）
+		if list.IsDoubly() && current.Prev != nil {
+			nodeData.PrevID = generateNodeID(prefix, (index-1+list.Size)%list.Size)
 		}
 
-		list.Nodes = append(list.Nodes, nodeData)
+		nodes = append(nodes, nodeData)
 
-		// 防止循环链表无限循环
-		if list.Type == "circular" && current.Next == list.Head && index > 0 {
+		// 防止循环链表无限循环（只有一个节点时它指向自身）
+		if list.IsCircular() && current.Next == list.Head {
 			// 设置循环连接
-			nodeData.NextID = generateNodeID(list.ID, 0)
+			nodeData.NextID = generateNodeID(prefix, 0)
 			break
 		}
 
@@ -153,6 +390,8 @@ func (list *LinkedList) updateVisualizationData() {
 			break
 		}
 	}
+
+	return nodes
 }
 
 // 创建链表
@@ -165,43 +404,65 @@ func createLinkedList(c echo.Context) error {
 		})
 	}
 
-	if req.Type == "" {
-		req.Type = "single"
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
 	}
+	req.ElementType = elementType
 
-	if req.Type != "single" && req.Type != "double" && req.Type != "circular" {
+	res, err := factory.newList(req)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, LinkedListResponse{
 			Success: false,
-			Message: "链表类型必须是single、double或circular",
+			Message: err.Error(),
 		})
 	}
 
-	id := generateListID()
-	list := &LinkedList{
-		ID:    id,
-		Name:  req.Name,
-		Type:  req.Type,
-		Head:  nil,
-		Tail:  nil,
-		Size:  0,
-		Nodes: make([]*NodeData, 0),
+	id, err := linkedLists.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表ID生成失败",
+		})
 	}
+	list := res.header()
+	list.ID = id
 
-	linkedLists[id] = list
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	if err := commitList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表保存失败",
+		})
+	}
 
 	return c.JSON(http.StatusCreated, LinkedListResponse{
 		Success: true,
 		Message: "链表创建成功",
-		List:    list,
+		List:    res,
 	})
 }
 
 // 获取所有链表
 func getAllLinkedLists(c echo.Context) error {
-	listArray := make([]*LinkedList, 0, len(linkedLists))
-	for _, list := range linkedLists {
-		list.updateVisualizationData()
-		listArray = append(listArray, list)
+	// 逐个加锁序列化，避免读到正在修改的链表
+	listArray := make([]json.RawMessage, 0)
+	for _, res := range linkedLists.List() {
+		list := res.header()
+		list.mu.Lock()
+		res.updateVisualizationData()
+		data, err := json.Marshal(res)
+		list.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		listArray = append(listArray, data)
 	}
 
 	return c.JSON(http.StatusOK, LinkedListResponse{
@@ -214,35 +475,47 @@ func getAllLinkedLists(c echo.Context) error {
 // 获取指定链表
 func getLinkedList(c echo.Context) error {
 	id := c.Param("id")
-	list, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
 
-	list.updateVisualizationData()
+	res.updateVisualizationData()
 
+	setETag(c, list.Version)
 	return c.JSON(http.StatusOK, LinkedListResponse{
 		Success: true,
 		Message: "获取链表成功",
-		List:    list,
+		List:    res,
 	})
 }
 
 // 删除链表
 func deleteLinkedList(c echo.Context) error {
 	id := c.Param("id")
-	_, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
 
-	delete(linkedLists, id)
+	if _, err := linkedLists.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表删除失败",
+		})
+	}
+	list.removed = true
+	closePlayer("lists/" + id)
 
 	return c.JSON(http.StatusOK, LinkedListResponse{
 		Success: true,
@@ -253,101 +526,57 @@ func deleteLinkedList(c echo.Context) error {
 // 在指定位置插入节点
 func insertNode(c echo.Context) error {
 	id := c.Param("id")
-	list, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
 
-	var req NodeRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+	if !ifMatchSatisfied(c, list.Version) {
+		setETag(c, list.Version)
+		return c.JSON(http.StatusPreconditionFailed, LinkedListResponse{
 			Success: false,
-			Message: "请求参数格式错误",
+			Message: fmt.Sprintf("链表已被修改（当前版本%d），请刷新后重试", list.Version),
+			List:    res,
 		})
 	}
 
-	if req.Index < 0 || req.Index > list.Size {
+	var req NodeRequest
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, LinkedListResponse{
 			Success: false,
-			Message: "插入位置无效",
+			Message: "请求参数格式错误",
 		})
 	}
 
-	newNode := &Node{Value: req.Value}
-
-	if req.Index == 0 {
-		// 在头部插入
-		if list.Head == nil {
-			list.Head = newNode
-			list.Tail = newNode
-			if list.Type == "circular" {
-				newNode.Next = newNode
-			}
-		} else {
-			newNode.Next = list.Head
-			if list.Type == "double" {
-				list.Head.Prev = newNode
-			}
-			list.Head = newNode
-
-			if list.Type == "circular" {
-				list.Tail.Next = newNode
-			}
-		}
-	} else if req.Index == list.Size {
-		// 在尾部插入
-		if list.Tail != nil {
-			list.Tail.Next = newNode
-			if list.Type == "double" {
-				newNode.Prev = list.Tail
-			}
-			list.Tail = newNode
-
-			if list.Type == "circular" {
-				newNode.Next = list.Head
-			}
-		}
-	} else {
-		// 在中间插入
-		current := list.Head
-		for i := 0; i < req.Index-1; i++ {
-			current = current.Next
-		}
-
-		newNode.Next = current.Next
-		current.Next = newNode
-
-		if list.Type == "double" {
-			newNode.Prev = current
-			if newNode.Next != nil {
-				newNode.Next.Prev = newNode
-			}
-		}
-	}
-
-	list.Size++
-	list.updateVisualizationData()
-
-	return c.JSON(http.StatusOK, LinkedListResponse{
-		Success: true,
-		Message: "节点插入成功",
-		List:    list,
-	})
+	return insertNodeAt(c, res, "insert", req.Index, req.Value)
 }
 
 // 在头部插入节点
 func prependNode(c echo.Context) error {
 	id := c.Param("id")
-	_, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	if !ifMatchSatisfied(c, list.Version) {
+		setETag(c, list.Version)
+		return c.JSON(http.StatusPreconditionFailed, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("链表已被修改（当前版本%d），请刷新后重试", list.Version),
+			List:    res,
+		})
+	}
 
 	var req NodeRequest
 	if err := c.Bind(&req); err != nil {
@@ -357,20 +586,30 @@ func prependNode(c echo.Context) error {
 		})
 	}
 
-	req.Index = 0
-	return insertNode(c)
+	return insertNodeAt(c, res, "prepend", 0, req.Value)
 }
 
 // 在尾部追加节点
 func appendNode(c echo.Context) error {
 	id := c.Param("id")
-	list, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	if !ifMatchSatisfied(c, list.Version) {
+		setETag(c, list.Version)
+		return c.JSON(http.StatusPreconditionFailed, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("链表已被修改（当前版本%d），请刷新后重试", list.Version),
+			List:    res,
+		})
+	}
 
 	var req NodeRequest
 	if err := c.Bind(&req); err != nil {
@@ -380,212 +619,240 @@ func appendNode(c echo.Context) error {
 		})
 	}
 
-	req.Index = list.Size
-	return insertNode(c)
+	return insertNodeAt(c, res, "append", list.state.Size, req.Value)
+}
+
+// 解码节点值、校验插入位置并插入节点，供三种插入接口共用
+func insertNodeAt(c echo.Context, res listResource, operation string, index int, raw json.RawMessage) error {
+	list := res.header()
+
+	value, err := res.decodeValue(raw)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	res.beginOperation()
+	if err := res.insertAny(index, value); err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: "插入位置无效",
+		})
+	}
+	list.endOperation(operation)
+	res.recordHistory(operation, CommandInsert, index, value, nil)
+	res.updateVisualizationData()
+
+	if err := commitList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, LinkedListResponse{
+		Success: true,
+		Message: "节点插入成功",
+		List:    res,
+		Trace:   list.trace(),
+	})
 }
 
 // 按索引删除节点
 func deleteNodeByIndex(c echo.Context) error {
 	id := c.Param("id")
-	list, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	if !ifMatchSatisfied(c, list.Version) {
+		setETag(c, list.Version)
+		return c.JSON(http.StatusPreconditionFailed, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("链表已被修改（当前版本%d），请刷新后重试", list.Version),
+			List:    res,
+		})
+	}
 
 	indexStr := c.Param("index")
 	index, err := strconv.Atoi(indexStr)
-	if err != nil || index < 0 || index >= list.Size {
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, LinkedListResponse{
 			Success: false,
 			Message: "索引无效",
 		})
 	}
 
-	var deletedValue int
-
-	if index == 0 {
-		// 删除头节点
-		deletedValue = list.Head.Value
-		if list.Size == 1 {
-			list.Head = nil
-			list.Tail = nil
-		} else {
-			list.Head = list.Head.Next
-			if list.Type == "double" && list.Head != nil {
-				list.Head.Prev = nil
-			}
-			if list.Type == "circular" {
-				list.Tail.Next = list.Head
-			}
-		}
-	} else {
-		// 删除其他位置的节点
-		current := list.Head
-		for i := 0; i < index; i++ {
-			current = current.Next
-		}
-
-		deletedValue = current.Value
-
-		if current == list.Tail {
-			// 删除尾节点
-			prev := current.Prev
-			if list.Type == "double" {
-				prev.Next = nil
-				list.Tail = prev
-			} else {
-				// 单链表需要找到前一个节点
-				prev = list.Head
-				for prev.Next != current {
-					prev = prev.Next
-				}
-				prev.Next = nil
-				list.Tail = prev
-			}
-
-			if list.Type == "circular" {
-				list.Tail.Next = list.Head
-			}
-		} else {
-			// 删除中间节点
-			if list.Type == "double" {
-				current.Prev.Next = current.Next
-				current.Next.Prev = current.Prev
-			} else {
-				prev := list.Head
-				for prev.Next != current {
-					prev = prev.Next
-				}
-				prev.Next = current.Next
-			}
-		}
+	res.beginOperation()
+	deletedValue, err := res.removeAny(index)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: "索引无效",
+		})
 	}
+	list.endOperation("delete_index")
+	res.recordHistory("delete_index", CommandDelete, index, deletedValue, nil)
+	res.updateVisualizationData()
 
-	list.Size--
-	list.updateVisualizationData()
+	if err := commitList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表保存失败",
+		})
+	}
 
 	return c.JSON(http.StatusOK, LinkedListResponse{
 		Success: true,
-		Message: fmt.Sprintf("成功删除索引%d处的节点，值为%d", index, deletedValue),
-		List:    list,
+		Message: fmt.Sprintf("成功删除索引%d处的节点，值为%v", index, deletedValue),
+		List:    res,
 		Data:    deletedValue,
+		Trace:   list.trace(),
 	})
 }
 
 // 按值删除节点
 func deleteNodeByValue(c echo.Context) error {
 	id := c.Param("id")
-	list, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	if !ifMatchSatisfied(c, list.Version) {
+		setETag(c, list.Version)
+		return c.JSON(http.StatusPreconditionFailed, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("链表已被修改（当前版本%d），请刷新后重试", list.Version),
+			List:    res,
+		})
+	}
 
-	valueStr := c.Param("value")
-	value, err := strconv.Atoi(valueStr)
+	value, err := res.parseValue(c.Param("value"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, LinkedListResponse{
 			Success: false,
-			Message: "值格式错误",
+			Message: fmt.Sprintf("值格式错误：%v", err),
 		})
 	}
 
-	// 查找要删除的节点
-	current := list.Head
-	index := 0
-
-	for current != nil {
-		if current.Value == value {
-			// 找到要删除的节点，调用按索引删除
-			indexStr := strconv.Itoa(index)
-			c.SetParamNames("id", "index")
-			c.SetParamValues(id, indexStr)
-			return deleteNodeByIndex(c)
-		}
+	// 先查找要删除的节点，再按索引删除
+	res.beginOperation()
+	index := res.indexOfAny(value)
+	if index < 0 {
+		list.endOperation("delete_value")
+		return c.JSON(http.StatusNotFound, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到值为%v的节点", value),
+			Trace:   list.trace(),
+		})
+	}
 
-		current = current.Next
-		index++
+	deletedValue, err := res.removeAny(index)
+	if err != nil {
+		return err
+	}
+	list.endOperation("delete_value")
+	res.recordHistory("delete_value", CommandDelete, index, deletedValue, nil)
+	res.updateVisualizationData()
 
-		// 防止循环链表无限循环
-		if list.Type == "circular" && current == list.Head && index > 0 {
-			break
-		}
+	if err := commitList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表保存失败",
+		})
 	}
 
-	return c.JSON(http.StatusNotFound, LinkedListResponse{
-		Success: false,
-		Message: fmt.Sprintf("未找到值为%d的节点", value),
+	return c.JSON(http.StatusOK, LinkedListResponse{
+		Success: true,
+		Message: fmt.Sprintf("成功删除索引%d处的节点，值为%v", index, deletedValue),
+		List:    res,
+		Data:    deletedValue,
+		Trace:   list.trace(),
 	})
 }
 
 // 查找节点
 func findNode(c echo.Context) error {
 	id := c.Param("id")
-	list, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
 
-	valueStr := c.Param("value")
-	value, err := strconv.Atoi(valueStr)
+	value, err := res.parseValue(c.Param("value"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, LinkedListResponse{
 			Success: false,
-			Message: "值格式错误",
+			Message: fmt.Sprintf("值格式错误：%v", err),
 		})
 	}
 
-	current := list.Head
-	index := 0
-
-	for current != nil {
-		if current.Value == value {
-			list.updateVisualizationData()
-			return c.JSON(http.StatusOK, LinkedListResponse{
-				Success: true,
-				Message: fmt.Sprintf("找到值为%d的节点，位于索引%d", value, index),
-				List:    list,
-				Data:    index,
-			})
-		}
-
-		current = current.Next
-		index++
-
-		// 防止循环链表无限循环
-		if list.Type == "circular" && current == list.Head && index > 0 {
-			break
-		}
+	res.beginOperation()
+	index := res.indexOfAny(value)
+	list.endOperation("find")
+	if index < 0 {
+		return c.JSON(http.StatusNotFound, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到值为%v的节点", value),
+			Trace:   list.trace(),
+		})
 	}
 
-	return c.JSON(http.StatusNotFound, LinkedListResponse{
-		Success: false,
-		Message: fmt.Sprintf("未找到值为%d的节点", value),
+	res.updateVisualizationData()
+
+	return c.JSON(http.StatusOK, LinkedListResponse{
+		Success: true,
+		Message: fmt.Sprintf("找到值为%v的节点，位于索引%d", value, index),
+		List:    res,
+		Data:    index,
+		Trace:   list.trace(),
 	})
 }
 
 // 修改节点
 func updateNode(c echo.Context) error {
 	id := c.Param("id")
-	list, exists := linkedLists[id]
+	res, exists := lockList(id)
 	if !exists {
 		return c.JSON(http.StatusNotFound, LinkedListResponse{
 			Success: false,
 			Message: "链表不存在",
 		})
 	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	if !ifMatchSatisfied(c, list.Version) {
+		setETag(c, list.Version)
+		return c.JSON(http.StatusPreconditionFailed, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("链表已被修改（当前版本%d），请刷新后重试", list.Version),
+			List:    res,
+		})
+	}
 
 	indexStr := c.Param("index")
 	index, err := strconv.Atoi(indexStr)
-	if err != nil || index < 0 || index >= list.Size {
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, LinkedListResponse{
 			Success: false,
 			Message: "索引无效",
@@ -600,20 +867,255 @@ func updateNode(c echo.Context) error {
 		})
 	}
 
-	current := list.Head
-	for i := 0; i < index; i++ {
-		current = current.Next
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
 	}
 
-	oldValue := current.Value
-	current.Value = req.Value
+	res.beginOperation()
+	oldValue, err := res.setAny(index, value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: "索引无效",
+		})
+	}
+	list.endOperation("update")
+	res.recordHistory("update", CommandUpdate, index, value, oldValue)
+	res.updateVisualizationData()
 
-	list.updateVisualizationData()
+	if err := commitList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表保存失败",
+		})
+	}
 
 	return c.JSON(http.StatusOK, LinkedListResponse{
 		Success: true,
-		Message: fmt.Sprintf("成功将索引%d处的节点值从%d修改为%d", index, oldValue, req.Value),
-		List:    list,
+		Message: fmt.Sprintf("成功将索引%d处的节点值从%v修改为%v", index, oldValue, value),
+		List:    res,
 		Data:    oldValue,
+		Trace:   list.trace(),
+	})
+}
+
+// 原地反转链表
+func reverseLinkedList(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockList(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, LinkedListResponse{
+			Success: false,
+			Message: "链表不存在",
+		})
+	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	if !ifMatchSatisfied(c, list.Version) {
+		setETag(c, list.Version)
+		return c.JSON(http.StatusPreconditionFailed, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("链表已被修改（当前版本%d），请刷新后重试", list.Version),
+			List:    res,
+		})
+	}
+
+	var req ReverseRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+	if req.Mode == "" {
+		req.Mode = ds.ReverseIterative
+	}
+
+	res.beginOperation()
+	if err := res.reverse(req.Mode); err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	list.endOperation("reverse")
+	res.recordHistory("reverse", CommandReverse, 0, nil, nil)
+	res.updateVisualizationData()
+
+	if err := commitList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, LinkedListResponse{
+		Success: true,
+		Message: "链表反转成功",
+		List:    res,
+		Data:    req.Mode,
+		Trace:   list.trace(),
+	})
+}
+
+// 在链表锁内取得回放器：删除链表时在同一把锁内关闭回放器，
+// 因此这里取得（或新建）的回放器一定会在链表删除时被关闭，不会遗留
+func listPlayer(id string) (*opPlayer, bool) {
+	res, exists := lockList(id)
+	if !exists {
+		return nil, false
+	}
+	defer res.header().mu.Unlock()
+
+	return playerFor("lists/" + id), true
+}
+
+// 以 SSE 回放链表的操作步骤
+func streamListOperations(c echo.Context) error {
+	id := c.Param("id")
+	player, exists := listPlayer(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, LinkedListResponse{
+			Success: false,
+			Message: "链表不存在",
+		})
+	}
+
+	if delayStr := c.QueryParam("delay"); delayStr != "" {
+		delay, err := strconv.Atoi(delayStr)
+		if err == nil {
+			err = player.control(PlaybackControlRequest{Action: PlaybackSpeed, Delay: delay})
+		}
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, LinkedListResponse{
+				Success: false,
+				Message: "回放间隔无效",
+			})
+		}
+	}
+
+	return streamOperations(c, player, id)
+}
+
+// 控制链表操作的回放
+func controlListPlayback(c echo.Context) error {
+	id := c.Param("id")
+	player, exists := listPlayer(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, LinkedListResponse{
+			Success: false,
+			Message: "链表不存在",
+		})
+	}
+
+	var req PlaybackControlRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	if err := player.control(req); err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, LinkedListResponse{
+		Success: true,
+		Message: "回放控制已生效",
+		Data:    player.state(),
+	})
+}
+
+// 撤销链表最近一次修改
+func undoList(c echo.Context) error {
+	return replayListHistory(c, true)
+}
+
+// 重做链表最近一次撤销的修改
+func redoList(c echo.Context) error {
+	return replayListHistory(c, false)
+}
+
+// 执行撤销或重做，本身也作为一次修改递增版本号并返回执行追踪
+func replayListHistory(c echo.Context, undo bool) error {
+	id := c.Param("id")
+	res, exists := lockList(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, LinkedListResponse{
+			Success: false,
+			Message: "链表不存在",
+		})
+	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	if !ifMatchSatisfied(c, list.Version) {
+		setETag(c, list.Version)
+		return c.JSON(http.StatusPreconditionFailed, LinkedListResponse{
+			Success: false,
+			Message: fmt.Sprintf("链表已被修改（当前版本%d），请刷新后重试", list.Version),
+			List:    res,
+		})
+	}
+
+	op, action := "redo", "重做"
+	if undo {
+		op, action = "undo", "撤销"
+	}
+
+	res.beginOperation()
+	entry, err := res.replayHistory(undo)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, LinkedListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	list.endOperation(op)
+	res.updateVisualizationData()
+
+	if err := commitList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, LinkedListResponse{
+			Success: false,
+			Message: "链表保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, LinkedListResponse{
+		Success: true,
+		Message: fmt.Sprintf("已%s%s操作", action, entry.operation()),
+		List:    res,
+		Data:    entry,
+		Trace:   list.trace(),
+	})
+}
+
+// 获取链表的修改历史
+func getListHistory(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockList(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, LinkedListResponse{
+			Success: false,
+			Message: "链表不存在",
+		})
+	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	return c.JSON(http.StatusOK, LinkedListResponse{
+		Success: true,
+		Message: "获取修改历史成功",
+		Data:    res.historyView(),
 	})
 }
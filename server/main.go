@@ -21,9 +21,9 @@ func main() {
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, HeaderIfMatch},
 		AllowCredentials: true,
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", HeaderETag},
 	}))
 
 	// 健康检查端点
@@ -34,6 +34,11 @@ func main() {
 		})
 	})
 
+	// 初始化存储后端
+	if err := initStorage(); err != nil {
+		e.Logger.Fatal(err)
+	}
+
 	// API路由组
 	api := e.Group("/api")
 
@@ -43,6 +48,37 @@ func main() {
 	// 链表管理路由
 	setupLinkedListRoutes(api)
 
+	// 栈管理路由
+	setupStackRoutes(api)
+
+	// 队列和双端队列管理路由
+	setupQueueRoutes(api)
+	setupDequeRoutes(api)
+
+	// 堆管理路由
+	setupHeapRoutes(api)
+
+	// 树管理路由
+	setupTreeRoutes(api)
+
+	// 哈希表管理路由
+	setupHashTableRoutes(api)
+
+	// 图管理路由
+	setupGraphRoutes(api)
+
+	// 字典树管理路由
+	setupTrieRoutes(api)
+
+	// 并查集管理路由
+	setupDisjointSetRoutes(api)
+
+	// 线段树与树状数组管理路由
+	setupRangeTreeRoutes(api)
+
+	// 跳表管理路由
+	setupSkipListRoutes(api)
+
 	// 静态文件服务（用于生产环境）
 	// 检查是否存在前端构建文件
 	if _, err := os.Stat("web/dist"); err == nil {
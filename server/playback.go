@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 回放控制参数
+const (
+	defaultPlaybackDelay = 500 * time.Millisecond
+	maxPlaybackDelay     = 10 * time.Second
+	playbackQueueSize    = 64
+)
+
+// 回放控制动作
+const (
+	PlaybackPause  = "pause"
+	PlaybackResume = "resume"
+	PlaybackStep   = "step"
+	PlaybackSpeed  = "speed"
+)
+
+// OperationRecord 一次已执行操作的追踪记录
+type OperationRecord struct {
+	Structure string         `json:"structure"`
+	Operation string         `json:"operation"`
+	Steps     []ds.TraceStep `json:"steps"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// PlaybackControlRequest 回放控制请求结构体
+type PlaybackControlRequest struct {
+	Action string `json:"action"` // pause、resume、step、speed
+	Delay  int    `json:"delay"`  // 每步间隔（毫秒），用于 speed
+}
+
+// PlaybackState 回放器当前状态
+type PlaybackState struct {
+	Delay       int  `json:"delay"`
+	Paused      bool `json:"paused"`
+	Subscribers int  `json:"subscribers"`
+}
+
+// 单个 SSE 连接的订阅
+type playbackSubscriber struct {
+	ops      chan *OperationRecord
+	stepSeen int
+}
+
+// 某个数据结构的操作回放器，由所有观看该结构的连接共享，教师的控制对所有连接生效
+type opPlayer struct {
+	mu          sync.Mutex
+	delay       time.Duration
+	paused      bool
+	stepSeq     int           // 单步指令计数
+	changed     chan struct{} // 状态变化时关闭并替换，用于唤醒等待中的连接
+	closed      chan struct{} // 数据结构被删除时关闭
+	subscribers map[*playbackSubscriber]struct{}
+	last        *OperationRecord
+}
+
+// 数据结构已删除，回放结束
+var errPlayerClosed = errors.New("数据结构已删除")
+
+// 全局回放器，键为 "arrays/<id>" 或 "lists/<id>"
+var players = make(map[string]*opPlayer)
+var playersMu sync.Mutex
+
+// 获取（必要时创建）数据结构对应的回放器。调用方需持有数据结构的锁并确认它未被删除，
+// 否则删除后才新建的回放器再也不会被 closePlayer 关闭
+func playerFor(key string) *opPlayer {
+	playersMu.Lock()
+	defer playersMu.Unlock()
+
+	player, ok := players[key]
+	if !ok {
+		player = &opPlayer{
+			delay:       defaultPlaybackDelay,
+			changed:     make(chan struct{}),
+			closed:      make(chan struct{}),
+			subscribers: make(map[*playbackSubscriber]struct{}),
+		}
+		players[key] = player
+	}
+	return player
+}
+
+// 数据结构被删除时关闭其回放器，结束所有观看连接
+func closePlayer(key string) {
+	playersMu.Lock()
+	defer playersMu.Unlock()
+
+	if player, ok := players[key]; ok {
+		close(player.closed)
+		delete(players, key)
+	}
+}
+
+// 发布一次操作的追踪，推送给所有正在观看的连接
+func publishTrace(kind, id, operation string, steps []ds.TraceStep) {
+	record := &OperationRecord{
+		Structure: id,
+		Operation: operation,
+		Steps:     steps,
+		Timestamp: time.Now(),
+	}
+
+	player := playerFor(kind + "/" + id)
+	player.mu.Lock()
+	defer player.mu.Unlock()
+
+	player.last = record
+	for sub := range player.subscribers {
+		select {
+		case sub.ops <- record:
+		default:
+			// 观看端积压过多时丢弃，避免阻塞数据结构操作
+		}
+	}
+}
+
+// 注册一个观看连接，replay 为真时先回放最近一次操作
+func (p *opPlayer) subscribe(replay bool) *playbackSubscriber {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sub := &playbackSubscriber{
+		ops:      make(chan *OperationRecord, playbackQueueSize),
+		stepSeen: p.stepSeq,
+	}
+	if replay && p.last != nil {
+		sub.ops <- p.last
+	}
+	p.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// 注销观看连接
+func (p *opPlayer) unsubscribe(sub *playbackSubscriber) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.subscribers, sub)
+}
+
+// 通知所有等待中的连接状态已变化（调用方需持有锁）
+func (p *opPlayer) notify() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// 应用一条控制指令
+func (p *opPlayer) control(req PlaybackControlRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch req.Action {
+	case PlaybackPause:
+		p.paused = true
+	case PlaybackResume:
+		p.paused = false
+	case PlaybackStep:
+		p.paused = true
+		p.stepSeq++
+	case PlaybackSpeed:
+		delay := time.Duration(req.Delay) * time.Millisecond
+		if delay < 0 || delay > maxPlaybackDelay {
+			return fmt.Errorf("间隔必须在0到%d毫秒之间", maxPlaybackDelay.Milliseconds())
+		}
+		p.delay = delay
+	default:
+		return errors.New("控制动作必须是pause、resume、step或speed")
+	}
+
+	p.notify()
+	return nil
+}
+
+// 当前回放状态
+func (p *opPlayer) state() PlaybackState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return PlaybackState{
+		Delay:       int(p.delay.Milliseconds()),
+		Paused:      p.paused,
+		Subscribers: len(p.subscribers),
+	}
+}
+
+// 等待推送下一步的时机：运行时按间隔推进，暂停时等待单步指令
+func (p *opPlayer) waitTurn(ctx context.Context, sub *playbackSubscriber) error {
+	for {
+		p.mu.Lock()
+		if p.stepSeq > sub.stepSeen {
+			sub.stepSeen++
+			p.mu.Unlock()
+			return nil
+		}
+		paused, delay, changed := p.paused, p.delay, p.changed
+		p.mu.Unlock()
+
+		var timer *time.Timer
+		var tick <-chan time.Time
+		if !paused {
+			timer = time.NewTimer(delay)
+			tick = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.closed:
+			return errPlayerClosed
+		case <-changed:
+			// 状态变化后重新判断
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-tick:
+			// 运行中积累的单步指令不再保留
+			p.mu.Lock()
+			sub.stepSeen = p.stepSeq
+			p.mu.Unlock()
+			return nil
+		}
+	}
+}
+
+// 写出一条 SSE 事件
+func writeEvent(c echo.Context, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	c.Response().Flush()
+	return nil
+}
+
+// 以 SSE 方式按回放速度推送数据结构的操作步骤，调用方需已确认数据结构存在
+func streamOperations(c echo.Context, player *opPlayer, id string) error {
+	sub := player.subscribe(c.QueryParam("replay") != "false")
+	defer player.unsubscribe(sub)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+
+	if err := writeEvent(c, "ready", player.state()); err != nil {
+		return nil
+	}
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-player.closed:
+			writeEvent(c, "closed", map[string]string{"structure": id})
+			return nil
+		case record := <-sub.ops:
+			if err := writeEvent(c, "operation", map[string]interface{}{
+				"structure":  record.Structure,
+				"operation":  record.Operation,
+				"totalSteps": len(record.Steps),
+				"timestamp":  record.Timestamp,
+			}); err != nil {
+				return nil
+			}
+
+			for i, step := range record.Steps {
+				if err := player.waitTurn(ctx, sub); err != nil {
+					if errors.Is(err, errPlayerClosed) {
+						writeEvent(c, "closed", map[string]string{"structure": id})
+					}
+					return nil
+				}
+				if err := writeEvent(c, "step", map[string]interface{}{
+					"operation": record.Operation,
+					"index":     i,
+					"total":     len(record.Steps),
+					"step":      step,
+				}); err != nil {
+					return nil
+				}
+			}
+
+			if err := writeEvent(c, "done", map[string]string{"operation": record.Operation}); err != nil {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,558 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RangeNodeData 用于前端显示的线段树或树状数组节点，[Low, High] 为节点覆盖的数组区间
+type RangeNodeData struct {
+	ID       string   `json:"id"`
+	Index    int      `json:"index"` // 节点在存储中的下标，从1开始
+	Low      int      `json:"lo"`
+	High     int      `json:"hi"`
+	Value    float64  `json:"value"`
+	Pending  *float64 `json:"pending,omitempty"` // 尚未下传的懒标记
+	ParentID string   `json:"parentId,omitempty"`
+	LeftID   string   `json:"leftId,omitempty"`
+	RightID  string   `json:"rightId,omitempty"`
+}
+
+// RangeTree 建立在数组上的线段树或树状数组，操作由 ds.RangeTree 实现；
+// ArrayID 记录来源数组，修改数组元素时可以选择同步到由它建立的树
+type RangeTree struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ArrayID      string `json:"arrayId"`
+	ArrayVersion int64  `json:"arrayVersion"` // 建树或上次同步时来源数组的版本，数组此后有其他修改时不再同步
+	Version      int64  `json:"version"`      // 每次修改递增，作为 ETag 用于乐观并发控制
+	*ds.RangeTree
+
+	RootID   string           `json:"rootId"`
+	NodeList []*RangeNodeData `json:"nodes"` // 线段树按先序排列，树状数组按下标排列
+
+	mu      sync.Mutex // 串行化对同一棵树的操作，不同树之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// RangeTreeRequest 从数组建立区间树的请求
+type RangeTreeRequest struct {
+	Name      string `json:"name"`
+	ArrayID   string `json:"arrayId"`
+	Type      string `json:"type"`      // segment（默认）或 fenwick
+	Operation string `json:"operation"` // sum（默认）、min 或 max，树状数组只支持 sum
+	Lazy      bool   `json:"lazy"`      // 线段树的区间更新是否使用懒标记
+}
+
+// RangePointUpdateRequest 单点修改的请求
+type RangePointUpdateRequest struct {
+	Index int     `json:"index"`
+	Value float64 `json:"value"`
+}
+
+// RangeUpdateRequest 区间加的请求，[Left, Right] 为闭区间
+type RangeUpdateRequest struct {
+	Left  int     `json:"left"`
+	Right int     `json:"right"`
+	Delta float64 `json:"delta"`
+}
+
+// RangeTreeResponse 区间树操作响应结构体
+type RangeTreeResponse struct {
+	Success   bool              `json:"success"`
+	Message   string            `json:"message"`
+	RangeTree *RangeTree        `json:"rangeTree,omitempty"`
+	Data      interface{}       `json:"data,omitempty"`
+	Cost      *ds.OperationCost `json:"cost,omitempty"`
+	Trace     []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// RangeTreePropagation 数组元素修改同步到一棵区间树的结果
+type RangeTreePropagation struct {
+	RangeTreeID string            `json:"rangeTreeId"`
+	Success     bool              `json:"success"`
+	Message     string            `json:"message"`
+	Version     int64             `json:"version"`
+	Visited     []int             `json:"visited,omitempty"`
+	Cost        *ds.OperationCost `json:"cost,omitempty"`
+	Trace       []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局区间树存储，后端由 initStorage 根据配置选择
+var rangeTrees Storage[*RangeTree] = newMemoryStorage[*RangeTree]("rangetree")
+
+// 获取区间树并加锁，调用方负责解锁；树不存在或已被删除时返回 false
+func lockRangeTree(id string) (*RangeTree, bool) {
+	tree, exists := rangeTrees.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	tree.mu.Lock()
+	if tree.removed {
+		tree.mu.Unlock()
+		return nil, false
+	}
+	return tree, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitRangeTree(c echo.Context, tree *RangeTree) error {
+	tree.Version++
+	if err := rangeTrees.Save(tree.ID, tree); err != nil {
+		return err
+	}
+	setETag(c, tree.Version)
+	return nil
+}
+
+// 为 ds.RangeTree 附加服务端状态
+func wrapRangeTree(id, name, arrayID string, arrayVersion int64, core *ds.RangeTree) *RangeTree {
+	tree := &RangeTree{ID: id, Name: name, ArrayID: arrayID, ArrayVersion: arrayVersion, RangeTree: core}
+	tree.updateVisualizationData()
+	return tree
+}
+
+// 区间树的持久化快照，只保存参数和数组值，恢复时重新建树
+type rangeTreeSnapshot struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	ArrayID      string             `json:"arrayId"`
+	ArrayVersion int64              `json:"arrayVersion"`
+	Version      int64              `json:"version"`
+	Config       ds.RangeTreeConfig `json:"config"`
+	Values       []float64          `json:"values"`
+}
+
+// 生成区间树快照
+func snapshotRangeTree(tree *RangeTree) any {
+	return rangeTreeSnapshot{
+		ID:           tree.ID,
+		Name:         tree.Name,
+		ArrayID:      tree.ArrayID,
+		ArrayVersion: tree.ArrayVersion,
+		Version:      tree.Version,
+		Config:       tree.Config(),
+		Values:       tree.Values,
+	}
+}
+
+// 从快照恢复区间树
+func restoreRangeTree(data []byte) (*RangeTree, error) {
+	var snapshot rangeTreeSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreRangeTree(snapshot.Config, snapshot.Values)
+	if err != nil {
+		return nil, err
+	}
+	tree := wrapRangeTree(snapshot.ID, snapshot.Name, snapshot.ArrayID, snapshot.ArrayVersion, core)
+	tree.Version = snapshot.Version
+	return tree, nil
+}
+
+// 按 ds.RangeTree 的节点布局生成显示用的节点，节点ID按存储下标编号
+func (tree *RangeTree) updateVisualizationData() {
+	layout := tree.Layout()
+	tree.NodeList = make([]*RangeNodeData, 0, len(layout))
+	for _, node := range layout {
+		nodeData := &RangeNodeData{
+			ID:      generateNodeID(tree.ID, node.Index),
+			Index:   node.Index,
+			Low:     node.Low,
+			High:    node.High,
+			Value:   node.Value,
+			Pending: node.Pending,
+		}
+		if node.Parent > 0 {
+			nodeData.ParentID = generateNodeID(tree.ID, node.Parent)
+		}
+		if node.Left > 0 {
+			nodeData.LeftID = generateNodeID(tree.ID, node.Left)
+			nodeData.RightID = generateNodeID(tree.ID, node.Right)
+		}
+		tree.NodeList = append(tree.NodeList, nodeData)
+	}
+
+	// 树状数组没有单一的根，以覆盖最长前缀的节点作为显示的起点
+	tree.RootID = generateNodeID(tree.ID, 1)
+	if tree.Type == ds.RangeFenwick {
+		root := 1
+		for root*2 <= tree.Size {
+			root *= 2
+		}
+		tree.RootID = generateNodeID(tree.ID, root)
+	}
+}
+
+// 设置区间树相关路由
+func setupRangeTreeRoutes(g *echo.Group) {
+	rangeTreeGroup := g.Group("/rangetrees")
+
+	// 从数组建立线段树或树状数组
+	rangeTreeGroup.POST("", createRangeTree)
+
+	// 获取所有区间树
+	rangeTreeGroup.GET("", getAllRangeTrees)
+
+	// 获取指定区间树
+	rangeTreeGroup.GET("/:id", getRangeTree)
+
+	// 删除区间树
+	rangeTreeGroup.DELETE("/:id", deleteRangeTree)
+
+	// 单点修改
+	rangeTreeGroup.POST("/:id/update", updateRangeTreePoint)
+
+	// 区间加
+	rangeTreeGroup.POST("/:id/range_update", updateRangeTreeRange)
+
+	// 区间查询
+	rangeTreeGroup.GET("/:id/query/:left/:right", queryRangeTree)
+}
+
+// 从数组建立区间树
+func createRangeTree(c echo.Context) error {
+	var req RangeTreeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	// 只在读取元素时持有数组的锁，建树期间数组可以继续被修改
+	source, exists := lockArray(req.ArrayID)
+	if !exists {
+		return c.JSON(http.StatusNotFound, RangeTreeResponse{
+			Success: false,
+			Message: "源数组不存在",
+		})
+	}
+	values, err := source.numbers()
+	arrayVersion := source.header().Version
+	source.header().mu.Unlock()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	core, err := ds.NewRangeTree(ds.RangeTreeConfig{
+		Type:      req.Type,
+		Operation: req.Operation,
+		Lazy:      req.Lazy,
+	}, values)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	id, err := rangeTrees.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, RangeTreeResponse{
+			Success: false,
+			Message: "区间树ID生成失败",
+		})
+	}
+	tree := wrapRangeTree(id, req.Name, req.ArrayID, arrayVersion, core)
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	if err := commitRangeTree(c, tree); err != nil {
+		return c.JSON(http.StatusInternalServerError, RangeTreeResponse{
+			Success: false,
+			Message: "区间树保存失败",
+		})
+	}
+
+	cost := tree.Cost()
+	return c.JSON(http.StatusCreated, RangeTreeResponse{
+		Success:   true,
+		Message:   "区间树创建成功",
+		RangeTree: tree,
+		Cost:      &cost,
+		Trace:     tree.Trace(),
+	})
+}
+
+// 获取所有区间树
+func getAllRangeTrees(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的树
+	treeList := make([]json.RawMessage, 0)
+	for _, tree := range rangeTrees.List() {
+		tree.mu.Lock()
+		data, err := json.Marshal(tree)
+		tree.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		treeList = append(treeList, data)
+	}
+
+	return c.JSON(http.StatusOK, RangeTreeResponse{
+		Success: true,
+		Message: "获取区间树列表成功",
+		Data:    treeList,
+	})
+}
+
+// 获取指定区间树
+func getRangeTree(c echo.Context) error {
+	tree, exists := lockRangeTree(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, RangeTreeResponse{
+			Success: false,
+			Message: "区间树不存在",
+		})
+	}
+	defer tree.mu.Unlock()
+
+	setETag(c, tree.Version)
+	return c.JSON(http.StatusOK, RangeTreeResponse{
+		Success:   true,
+		Message:   "获取区间树成功",
+		RangeTree: tree,
+	})
+}
+
+// 删除区间树
+func deleteRangeTree(c echo.Context) error {
+	id := c.Param("id")
+	tree, exists := lockRangeTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, RangeTreeResponse{
+			Success: false,
+			Message: "区间树不存在",
+		})
+	}
+	defer tree.mu.Unlock()
+
+	if _, err := rangeTrees.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, RangeTreeResponse{
+			Success: false,
+			Message: "区间树删除失败",
+		})
+	}
+	tree.removed = true
+
+	return c.JSON(http.StatusOK, RangeTreeResponse{
+		Success: true,
+		Message: "区间树删除成功",
+	})
+}
+
+// 获取区间树并检查 If-Match，失败时已写好响应，返回的 handled 为 true
+func lockRangeTreeForUpdate(c echo.Context) (*RangeTree, bool, error) {
+	tree, exists := lockRangeTree(c.Param("id"))
+	if !exists {
+		return nil, true, c.JSON(http.StatusNotFound, RangeTreeResponse{
+			Success: false,
+			Message: "区间树不存在",
+		})
+	}
+
+	if !ifMatchSatisfied(c, tree.Version) {
+		defer tree.mu.Unlock()
+		setETag(c, tree.Version)
+		return nil, true, c.JSON(http.StatusPreconditionFailed, RangeTreeResponse{
+			Success:   false,
+			Message:   fmt.Sprintf("区间树已被修改（当前版本%d），请刷新后重试", tree.Version),
+			RangeTree: tree,
+		})
+	}
+	return tree, false, nil
+}
+
+// 提交修改并返回区间树、访问过的节点和本次操作的追踪
+func respondRangeTreeUpdate(c echo.Context, tree *RangeTree, message string) error {
+	cost := tree.Cost()
+	tree.updateVisualizationData()
+
+	if err := commitRangeTree(c, tree); err != nil {
+		return c.JSON(http.StatusInternalServerError, RangeTreeResponse{
+			Success: false,
+			Message: "区间树保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, RangeTreeResponse{
+		Success:   true,
+		Message:   message,
+		RangeTree: tree,
+		Data: map[string]interface{}{
+			"visited": tree.Visited(),
+		},
+		Cost:  &cost,
+		Trace: tree.Trace(),
+	})
+}
+
+// 单点修改
+func updateRangeTreePoint(c echo.Context) error {
+	tree, handled, err := lockRangeTreeForUpdate(c)
+	if handled {
+		return err
+	}
+	defer tree.mu.Unlock()
+
+	var req RangePointUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	tree.Begin()
+	if err := tree.Update(req.Index, req.Value); err != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: "索引无效",
+		})
+	}
+
+	return respondRangeTreeUpdate(c, tree, fmt.Sprintf("成功将索引%d处的值修改为%g", req.Index, req.Value))
+}
+
+// 区间加
+func updateRangeTreeRange(c echo.Context) error {
+	tree, handled, err := lockRangeTreeForUpdate(c)
+	if handled {
+		return err
+	}
+	defer tree.mu.Unlock()
+
+	var req RangeUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	tree.Begin()
+	if err := tree.RangeUpdate(req.Left, req.Right, req.Delta); err != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: "区间无效",
+		})
+	}
+
+	return respondRangeTreeUpdate(c, tree, fmt.Sprintf("成功将区间[%d, %d]内的值各加%g", req.Left, req.Right, req.Delta))
+}
+
+// 区间查询；带懒标记的线段树在查询时会下传标记，但不改变任何区间的结果，因此不递增版本
+func queryRangeTree(c echo.Context) error {
+	tree, exists := lockRangeTree(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, RangeTreeResponse{
+			Success: false,
+			Message: "区间树不存在",
+		})
+	}
+	defer tree.mu.Unlock()
+
+	left, errLeft := strconv.Atoi(c.Param("left"))
+	right, errRight := strconv.Atoi(c.Param("right"))
+	if errLeft != nil || errRight != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: "区间无效",
+		})
+	}
+
+	tree.Begin()
+	value, err := tree.Query(left, right)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, RangeTreeResponse{
+			Success: false,
+			Message: "区间无效",
+		})
+	}
+	tree.updateVisualizationData()
+
+	cost := tree.Cost()
+	setETag(c, tree.Version)
+	return c.JSON(http.StatusOK, RangeTreeResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("区间[%d, %d]的%s为%g", left, right, tree.Operation, value),
+		RangeTree: tree,
+		Data: map[string]interface{}{
+			"left":      left,
+			"right":     right,
+			"operation": tree.Operation,
+			"value":     value,
+			"visited":   tree.Visited(),
+		},
+		Cost:  &cost,
+		Trace: tree.Trace(),
+	})
+}
+
+// 把数组在 index 处的新值同步到所有由该数组建立的区间树；调用方持有数组的锁，
+// previous 为本次修改前数组的版本，current 为修改后的版本。
+// 树记录的数组版本不等于 previous 时，说明数组在此之间有过插入、删除、排序等其他修改，
+// 下标已经不再对应，只报告树已过期而不写入。
+// 区间树的处理函数从不锁数组，因此先锁数组再锁树不会死锁。
+// 这里不设置 ETag，响应头中保留数组的版本
+func propagateToRangeTrees(arrayID string, previous, current int64, index int, values []float64) []RangeTreePropagation {
+	results := make([]RangeTreePropagation, 0)
+	for _, candidate := range rangeTrees.List() {
+		if candidate.ArrayID != arrayID {
+			continue
+		}
+		tree, exists := lockRangeTree(candidate.ID)
+		if !exists {
+			continue
+		}
+		results = append(results, tree.propagate(previous, current, index, values[index]))
+		tree.mu.Unlock()
+	}
+	return results
+}
+
+// 把数组的一次单点修改同步到这棵树，调用方持有树的锁
+func (tree *RangeTree) propagate(previous, current int64, index int, value float64) RangeTreePropagation {
+	result := RangeTreePropagation{RangeTreeID: tree.ID, Version: tree.Version}
+	if tree.ArrayVersion != previous {
+		result.Message = fmt.Sprintf("区间树与数组版本%d同步，数组此后另有修改（本次修改前为版本%d），树已过期，请重新建树", tree.ArrayVersion, previous)
+		return result
+	}
+
+	tree.Begin()
+	if err := tree.Update(index, value); err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	cost := tree.Cost()
+	tree.updateVisualizationData()
+	tree.ArrayVersion = current
+	tree.Version++
+	if err := rangeTrees.Save(tree.ID, tree); err != nil {
+		result.Message = "区间树保存失败"
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("已将索引%d处的值%g同步到区间树", index, value)
+	result.Version = tree.Version
+	result.Visited = tree.Visited()
+	result.Cost = &cost
+	result.Trace = tree.Trace()
+	return result
+}
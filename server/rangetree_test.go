@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newRangeTreeTestServer() *echo.Echo {
+	e := newTestServer()
+	setupRangeTreeRoutes(e.Group("/api"))
+	return e
+}
+
+// 创建按顺序追加了 values 的整数数组，返回数组ID
+func createTestArray(t *testing.T, e *echo.Echo, values ...int) string {
+	t.Helper()
+
+	rec := doRequest(e, http.MethodPost, "/api/arrays", `{"name":"source"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("创建数组失败: %d %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Array struct {
+			ID string `json:"id"`
+		} `json:"array"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range values {
+		if rec := doRequest(e, http.MethodPost, "/api/arrays/"+resp.Array.ID+"/append", fmt.Sprintf(`{"value":%d}`, v)); rec.Code != http.StatusOK {
+			t.Fatalf("追加元素失败: %d %s", rec.Code, rec.Body.String())
+		}
+	}
+	return resp.Array.ID
+}
+
+// 从数组建立区间和线段树，返回树ID
+func createTestRangeTree(t *testing.T, e *echo.Echo, arrayID string) string {
+	t.Helper()
+
+	rec := doRequest(e, http.MethodPost, "/api/rangetrees", fmt.Sprintf(`{"arrayId":%q}`, arrayID))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("建立区间树失败: %d %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		RangeTree struct {
+			ID string `json:"id"`
+		} `json:"rangeTree"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.RangeTree.ID
+}
+
+// 带 propagate 修改数组元素，返回各棵树的同步结果
+func updateWithPropagate(t *testing.T, e *echo.Echo, arrayID string, index, value int) []RangeTreePropagation {
+	t.Helper()
+
+	path := fmt.Sprintf("/api/arrays/%s/index/%d?propagate=true", arrayID, index)
+	rec := doRequest(e, http.MethodPut, path, fmt.Sprintf(`{"value":%d}`, value))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("修改元素失败: %d %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Propagated []RangeTreePropagation `json:"propagated"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Propagated
+}
+
+func queryTestRangeTree(t *testing.T, e *echo.Echo, treeID string, left, right int) float64 {
+	t.Helper()
+
+	rec := doRequest(e, http.MethodGet, fmt.Sprintf("/api/rangetrees/%s/query/%d/%d", treeID, left, right), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("区间查询失败: %d %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			Value float64 `json:"value"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data.Value
+}
+
+func TestRangeTreePropagate(t *testing.T) {
+	e := newRangeTreeTestServer()
+	arrayID := createTestArray(t, e, 1, 2, 3, 4)
+	treeID := createTestRangeTree(t, e, arrayID)
+
+	results := updateWithPropagate(t, e, arrayID, 2, 30)
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("同步结果 %+v，期望成功", results)
+	}
+	if got := queryTestRangeTree(t, e, treeID, 0, 3); got != 37 {
+		t.Fatalf("sum[0, 3] = %g，期望37", got)
+	}
+
+	// 连续的同步修改都能写入
+	updateWithPropagate(t, e, arrayID, 0, 10)
+	if got := queryTestRangeTree(t, e, treeID, 0, 1); got != 12 {
+		t.Fatalf("sum[0, 1] = %g，期望12", got)
+	}
+}
+
+func TestRangeTreePropagateAfterInsertIsStale(t *testing.T) {
+	e := newRangeTreeTestServer()
+	arrayID := createTestArray(t, e, 1, 2, 3, 4)
+	treeID := createTestRangeTree(t, e, arrayID)
+
+	// 在开头插入后，数组的下标整体后移一位，不再与树的下标对应
+	rec := doRequest(e, http.MethodPost, "/api/arrays/"+arrayID+"/insert", `{"index":0,"value":100}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("插入元素失败: %d %s", rec.Code, rec.Body.String())
+	}
+
+	results := updateWithPropagate(t, e, arrayID, 2, 30)
+	if len(results) != 1 || results[0].Success {
+		t.Fatalf("同步结果 %+v，期望因树已过期而失败", results)
+	}
+	if got := queryTestRangeTree(t, e, treeID, 0, 3); got != 10 {
+		t.Fatalf("sum[0, 3] = %g，过期的树不应被修改，期望10", got)
+	}
+
+	// 重新建树后可以继续同步
+	rebuilt := createTestRangeTree(t, e, arrayID)
+	results = updateWithPropagate(t, e, arrayID, 4, 40)
+	for _, result := range results {
+		if result.RangeTreeID == rebuilt && !result.Success {
+			t.Fatalf("重新建立的树同步失败: %s", result.Message)
+		}
+	}
+	if got := queryTestRangeTree(t, e, rebuilt, 0, 4); got != 100+1+30+3+40 {
+		t.Fatalf("sum[0, 4] = %g，期望174", got)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 使用指定算法查找元素
+func searchElement(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockArray(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: "数组不存在",
+		})
+	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	value, err := res.parseValue(c.Param("value"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("值格式错误：%v", err),
+		})
+	}
+
+	name := c.QueryParam("algorithm")
+	if name == "" {
+		name = ds.SearchBinary
+	}
+
+	array.beginOperation()
+	result, err := res.searchAny(name, value)
+	if err != nil {
+		var unsorted *ds.UnsortedError
+		if errors.As(err, &unsorted) {
+			return c.JSON(http.StatusBadRequest, ArrayResponse{
+				Success: false,
+				Message: err.Error(),
+				Data:    unsorted.Index,
+			})
+		}
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	cost := array.endOperation("search", 0, 0)
+
+	if name == ds.SearchLowerBound || name == ds.SearchUpperBound {
+		return c.JSON(http.StatusOK, ArrayResponse{
+			Success: true,
+			Message: fmt.Sprintf("%s(%v)位于索引%d，共探查%d次", name, value, result.Index, len(result.Probes)),
+			Array:   res,
+			Data:    result,
+			Cost:    cost,
+			Trace:   array.trace(),
+		})
+	}
+
+	if !result.Found {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到值为%v的元素，共探查%d次", value, len(result.Probes)),
+			Data:    result,
+			Cost:    cost,
+			Trace:   array.trace(),
+		})
+	}
+
+	message := fmt.Sprintf("找到值为%v的元素，位于索引%d，共探查%d次", value, result.Index, len(result.Probes))
+	if name == ds.SearchAll {
+		message = fmt.Sprintf("找到%d个值为%v的元素，位于索引%s", len(result.Indices), value, formatIndices(result.Indices))
+	}
+
+	return c.JSON(http.StatusOK, ArrayResponse{
+		Success: true,
+		Message: message,
+		Array:   res,
+		Data:    result,
+		Cost:    cost,
+		Trace:   array.trace(),
+	})
+}
+
+// 将索引列表格式化为 "1、3、5"
+func formatIndices(indices []int) string {
+	parts := make([]string, len(indices))
+	for i, index := range indices {
+		parts[i] = strconv.Itoa(index)
+	}
+	return strings.Join(parts, "、")
+}
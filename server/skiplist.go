@@ -0,0 +1,602 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SkipListNodeData 用于前端显示的跳表节点，与链表的 NodeData 一样以节点ID表示指针；
+// Forward[i] 为第 i 层（第0层包含全部节点）的后继，空字符串表示该层到此结束
+type SkipListNodeData[T any] struct {
+	ID      string   `json:"id"`
+	Value   T        `json:"value"`
+	Level   int      `json:"level"` // 节点的层数，等于 Forward 的长度
+	Forward []string `json:"forward"`
+}
+
+// SkipListPathData 查找路径上的一步：在第 Level 层到达节点 NodeID
+type SkipListPathData struct {
+	Level  int    `json:"level"`
+	NodeID string `json:"nodeId"`
+}
+
+// skipListHeader 跳表中与元素类型无关的服务端状态：标识、版本和锁
+type skipListHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	recorder *ds.Recorder // 最近一次操作的计数和追踪
+
+	mu      sync.Mutex // 串行化对同一个跳表的操作，不同跳表之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// SkipList 跳表结构体，操作由 ds.SkipList 实现
+type SkipList[T any] struct {
+	skipListHeader
+	*ds.SkipList[T]
+
+	HeadID      string                 `json:"headId"`
+	HeadForward []string               `json:"headForward"` // 头节点在当前各层的后继
+	Nodes       []*SkipListNodeData[T] `json:"nodes"`       // 按第0层的顺序排列
+}
+
+// skipListResource 与元素类型无关的跳表接口，处理函数通过它操作任意元素类型的 SkipList[T]
+type skipListResource interface {
+	header() *skipListHeader
+	parseValue(s string) (any, error)
+	decodeValue(raw json.RawMessage) (any, error)
+	beginOperation()
+	insertAny(value any) (int, error)
+	deleteAny(value any) error
+	searchAny(value any) (bool, []SkipListPathData)
+	updateVisualizationData()
+	snapshot() any
+}
+
+// SkipListRequest 创建跳表的请求
+type SkipListRequest struct {
+	Name        string            `json:"name"`
+	ElementType string            `json:"elementType"`
+	MaxLevel    int               `json:"maxLevel"`    // 默认16
+	Probability float64           `json:"probability"` // 每提升一层的概率，默认0.5
+	Seed        int64             `json:"seed"`        // 随机数种子，相同种子和相同操作序列得到相同的结构
+	Values      []json.RawMessage `json:"values"`      // 可选的初始元素，按顺序插入
+}
+
+// SkipListValueRequest 插入请求，Value 按跳表的元素类型解码
+type SkipListValueRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// SkipListResponse 跳表操作响应结构体
+type SkipListResponse struct {
+	Success  bool              `json:"success"`
+	Message  string            `json:"message"`
+	SkipList skipListResource  `json:"skipList,omitempty"`
+	Data     interface{}       `json:"data,omitempty"`
+	Cost     *ds.OperationCost `json:"cost,omitempty"`
+	Trace    []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局跳表存储，后端由 initStorage 根据配置选择
+var skipLists Storage[skipListResource] = newMemoryStorage[skipListResource]("skiplist")
+
+// 获取跳表并加锁，调用方负责解锁；跳表不存在或已被删除时返回 false
+func lockSkipList(id string) (skipListResource, bool) {
+	res, exists := skipLists.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	list := res.header()
+	list.mu.Lock()
+	if list.removed {
+		list.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitSkipList(c echo.Context, res skipListResource) error {
+	list := res.header()
+	list.Version++
+	if err := skipLists.Save(list.ID, res); err != nil {
+		return err
+	}
+	setETag(c, list.Version)
+	return nil
+}
+
+// 按请求创建元素类型为 T 的跳表并依次插入初始元素，参数或元素无效时返回错误；ID 由调用方在校验通过后分配
+func newSkipList[T any](req SkipListRequest, kind *ds.ElementType[T]) (*SkipList[T], error) {
+	core, err := ds.NewSkipList(ds.SkipListConfig{
+		MaxLevel:    req.MaxLevel,
+		Probability: req.Probability,
+		Seed:        req.Seed,
+	}, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, raw := range req.Values {
+		value, err := kind.Decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := core.Insert(value); err != nil && !errors.Is(err, ds.ErrDuplicate) {
+			return nil, err
+		}
+	}
+	core.Begin()
+	return wrapSkipList("", req.Name, core), nil
+}
+
+// 为 ds.SkipList 附加服务端状态
+func wrapSkipList[T any](id, name string, core *ds.SkipList[T]) *SkipList[T] {
+	list := &SkipList[T]{SkipList: core}
+	list.ID = id
+	list.Name = name
+	list.ElementType = core.Kind().Name
+	list.recorder = &core.Recorder
+	list.updateVisualizationData()
+	return list
+}
+
+// 跳表的持久化快照，节点按第0层的顺序保存各自的层数，另存已抽取的随机数个数以便继续同一个随机数序列
+type skipListSnapshot[T any] struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"`
+	ds.SkipListConfig
+	Draws int                       `json:"draws"`
+	Nodes []ds.SavedSkipListNode[T] `json:"nodes"`
+}
+
+// 生成跳表快照
+func snapshotSkipList(res skipListResource) any {
+	return res.snapshot()
+}
+
+func (list *SkipList[T]) snapshot() any {
+	return skipListSnapshot[T]{
+		ID:             list.ID,
+		Name:           list.Name,
+		ElementType:    list.ElementType,
+		Version:        list.Version,
+		SkipListConfig: list.Config(),
+		Draws:          list.Draws,
+		Nodes:          list.Saved(),
+	}
+}
+
+// 从快照恢复跳表，按快照中的元素类型分发
+func restoreSkipList(data []byte) (skipListResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreSkipList(data)
+}
+
+// 从快照重建元素类型为 T 的跳表
+func restoreTypedSkipList[T any](data []byte, kind *ds.ElementType[T]) (*SkipList[T], error) {
+	var snapshot skipListSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreSkipList(snapshot.SkipListConfig, snapshot.Draws, snapshot.Nodes, kind)
+	if err != nil {
+		return nil, err
+	}
+	list := wrapSkipList(snapshot.ID, snapshot.Name, core)
+	list.Version = snapshot.Version
+	return list, nil
+}
+
+func (list *SkipList[T]) header() *skipListHeader {
+	return &list.skipListHeader
+}
+
+func (list *SkipList[T]) parseValue(s string) (any, error) {
+	return list.Kind().Parse(s)
+}
+
+func (list *SkipList[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return list.Kind().Decode(raw)
+}
+
+func (list *SkipList[T]) beginOperation() {
+	list.Begin()
+}
+
+func (list *SkipList[T]) insertAny(value any) (int, error) {
+	return list.Insert(valueOf[T](value))
+}
+
+func (list *SkipList[T]) deleteAny(value any) error {
+	return list.Delete(valueOf[T](value))
+}
+
+// 查找并把路径上的节点换成前端显示的节点ID
+func (list *SkipList[T]) searchAny(value any) (bool, []SkipListPathData) {
+	found, steps := list.Search(valueOf[T](value))
+	path := make([]SkipListPathData, len(steps))
+	for i, step := range steps {
+		path[i] = SkipListPathData{Level: step.Level, NodeID: list.nodeID(step.Node)}
+	}
+	return found, path
+}
+
+// 前端显示的节点ID，头节点使用单独的ID
+func (list *SkipList[T]) nodeID(id int) string {
+	if id < 0 {
+		return list.ID + "_head"
+	}
+	return generateNodeID(list.ID, id)
+}
+
+// 前端显示的后继ID，nil 为空字符串
+func (list *SkipList[T]) forwardID(node *ds.SkipListNode[T]) string {
+	if node == nil {
+		return ""
+	}
+	return list.nodeID(node.ID)
+}
+
+// 按第0层的顺序生成节点视图，每个节点给出它在各层的后继
+func (list *SkipList[T]) updateVisualizationData() {
+	list.HeadID = list.nodeID(list.Head.ID)
+	list.HeadForward = make([]string, list.Level)
+	for level := range list.Level {
+		list.HeadForward[level] = list.forwardID(list.Head.Next[level])
+	}
+
+	list.Nodes = make([]*SkipListNodeData[T], 0, list.Size)
+	for node := list.Head.Next[0]; node != nil; node = node.Next[0] {
+		nodeData := &SkipListNodeData[T]{
+			ID:      list.nodeID(node.ID),
+			Value:   node.Value,
+			Level:   len(node.Next),
+			Forward: make([]string, len(node.Next)),
+		}
+		for level, next := range node.Next {
+			nodeData.Forward[level] = list.forwardID(next)
+		}
+		list.Nodes = append(list.Nodes, nodeData)
+	}
+}
+
+// 设置跳表相关路由
+func setupSkipListRoutes(g *echo.Group) {
+	skipListGroup := g.Group("/skiplists")
+
+	// 创建跳表
+	skipListGroup.POST("", createSkipList)
+
+	// 获取所有跳表
+	skipListGroup.GET("", getAllSkipLists)
+
+	// 获取指定跳表
+	skipListGroup.GET("/:id", getSkipList)
+
+	// 删除跳表
+	skipListGroup.DELETE("/:id", deleteSkipList)
+
+	// 插入元素
+	skipListGroup.POST("/:id/insert", insertSkipListValue)
+
+	// 按值删除元素
+	skipListGroup.DELETE("/:id/value/:value", deleteSkipListValue)
+
+	// 查找元素，返回跨层的查找路径
+	skipListGroup.GET("/:id/search/:value", searchSkipList)
+}
+
+// 创建跳表
+func createSkipList(c echo.Context) error {
+	var req SkipListRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, SkipListResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, SkipListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	req.ElementType = elementType
+
+	res, err := factory.newSkipList(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, SkipListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	id, err := skipLists.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, SkipListResponse{
+			Success: false,
+			Message: "跳表ID生成失败",
+		})
+	}
+	list := res.header()
+	list.ID = id
+	res.updateVisualizationData()
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	if err := commitSkipList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, SkipListResponse{
+			Success: false,
+			Message: "跳表保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, SkipListResponse{
+		Success:  true,
+		Message:  "跳表创建成功",
+		SkipList: res,
+	})
+}
+
+// 获取所有跳表
+func getAllSkipLists(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的跳表
+	skipListList := make([]json.RawMessage, 0)
+	for _, res := range skipLists.List() {
+		list := res.header()
+		list.mu.Lock()
+		data, err := json.Marshal(res)
+		list.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		skipListList = append(skipListList, data)
+	}
+
+	return c.JSON(http.StatusOK, SkipListResponse{
+		Success: true,
+		Message: "获取跳表列表成功",
+		Data:    skipListList,
+	})
+}
+
+// 获取指定跳表
+func getSkipList(c echo.Context) error {
+	res, exists := lockSkipList(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, SkipListResponse{
+			Success: false,
+			Message: "跳表不存在",
+		})
+	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	setETag(c, list.Version)
+	return c.JSON(http.StatusOK, SkipListResponse{
+		Success:  true,
+		Message:  "获取跳表成功",
+		SkipList: res,
+	})
+}
+
+// 删除跳表
+func deleteSkipList(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockSkipList(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, SkipListResponse{
+			Success: false,
+			Message: "跳表不存在",
+		})
+	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	if _, err := skipLists.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, SkipListResponse{
+			Success: false,
+			Message: "跳表删除失败",
+		})
+	}
+	list.removed = true
+
+	return c.JSON(http.StatusOK, SkipListResponse{
+		Success: true,
+		Message: "跳表删除成功",
+	})
+}
+
+// 获取跳表并检查 If-Match，失败时已写好响应，返回的 handled 为 true
+func lockSkipListForUpdate(c echo.Context) (skipListResource, bool, error) {
+	res, exists := lockSkipList(c.Param("id"))
+	if !exists {
+		return nil, true, c.JSON(http.StatusNotFound, SkipListResponse{
+			Success: false,
+			Message: "跳表不存在",
+		})
+	}
+
+	list := res.header()
+	if !ifMatchSatisfied(c, list.Version) {
+		defer list.mu.Unlock()
+		setETag(c, list.Version)
+		return nil, true, c.JSON(http.StatusPreconditionFailed, SkipListResponse{
+			Success:  false,
+			Message:  fmt.Sprintf("跳表已被修改（当前版本%d），请刷新后重试", list.Version),
+			SkipList: res,
+		})
+	}
+	return res, false, nil
+}
+
+// 提交修改并返回跳表和本次操作的追踪
+func respondSkipListUpdate(c echo.Context, res skipListResource, message string, data any) error {
+	list := res.header()
+	cost := list.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitSkipList(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, SkipListResponse{
+			Success: false,
+			Message: "跳表保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, SkipListResponse{
+		Success:  true,
+		Message:  message,
+		SkipList: res,
+		Data:     data,
+		Cost:     &cost,
+		Trace:    list.recorder.Trace(),
+	})
+}
+
+// 插入元素
+func insertSkipListValue(c echo.Context) error {
+	res, handled, err := lockSkipListForUpdate(c)
+	if handled {
+		return err
+	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	var req SkipListValueRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, SkipListResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, SkipListResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	res.beginOperation()
+	level, err := res.insertAny(value)
+	if errors.Is(err, ds.ErrDuplicate) {
+		return c.JSON(http.StatusBadRequest, SkipListResponse{
+			Success: false,
+			Message: fmt.Sprintf("元素%v已存在", value),
+			Trace:   list.recorder.Trace(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return respondSkipListUpdate(c, res, fmt.Sprintf("%v已插入跳表，新节点共%d层", value, level), map[string]interface{}{
+		"value": value,
+		"level": level,
+	})
+}
+
+// 按值删除元素
+func deleteSkipListValue(c echo.Context) error {
+	res, handled, err := lockSkipListForUpdate(c)
+	if handled {
+		return err
+	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	value, err := res.parseValue(c.Param("value"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, SkipListResponse{
+			Success: false,
+			Message: fmt.Sprintf("值格式错误：%v", err),
+		})
+	}
+
+	res.beginOperation()
+	err = res.deleteAny(value)
+	if errors.Is(err, ds.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, SkipListResponse{
+			Success: false,
+			Message: fmt.Sprintf("元素%v不存在", value),
+			Trace:   list.recorder.Trace(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return respondSkipListUpdate(c, res, fmt.Sprintf("成功删除元素%v", value), value)
+}
+
+// 查找元素，未找到时同样返回查找路径
+func searchSkipList(c echo.Context) error {
+	res, exists := lockSkipList(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, SkipListResponse{
+			Success: false,
+			Message: "跳表不存在",
+		})
+	}
+	list := res.header()
+	defer list.mu.Unlock()
+
+	value, err := res.parseValue(c.Param("value"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, SkipListResponse{
+			Success: false,
+			Message: fmt.Sprintf("值格式错误：%v", err),
+		})
+	}
+
+	res.beginOperation()
+	found, path := res.searchAny(value)
+	cost := list.recorder.Cost()
+	data := map[string]interface{}{
+		"value": value,
+		"found": found,
+		"path":  path,
+	}
+	if !found {
+		return c.JSON(http.StatusNotFound, SkipListResponse{
+			Success: false,
+			Message: fmt.Sprintf("元素%v不存在", value),
+			Data:    data,
+			Cost:    &cost,
+			Trace:   list.recorder.Trace(),
+		})
+	}
+
+	setETag(c, list.Version)
+	return c.JSON(http.StatusOK, SkipListResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("找到元素%v，比较了%d次", value, cost.Comparisons),
+		SkipList: res,
+		Data:     data,
+		Cost:     &cost,
+		Trace:    list.recorder.Trace(),
+	})
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SortRequest 排序请求结构体
+type SortRequest struct {
+	ds.SortOptions
+	DryRun bool `json:"dryRun"` // 只在副本上排序并返回过程，不修改数组，便于对比不同算法
+}
+
+// SortResult 排序结果
+type SortResult struct {
+	ds.SortInfo
+	DryRun   bool `json:"dryRun"`
+	Elements any  `json:"elements"`
+}
+
+// 按请求排序，试运行时在不带计数的副本上排序；返回记录本次代价和追踪的 Recorder
+func (array *DynamicArray[T]) sortElements(req SortRequest) (*SortResult, *ds.Recorder, error) {
+	target := array.Array
+	if req.DryRun {
+		target = array.Clone()
+		target.Begin()
+	}
+
+	info, err := target.Sort(req.SortOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &SortResult{SortInfo: *info, DryRun: req.DryRun, Elements: target.Values()}, &target.Recorder, nil
+}
+
+// 对数组排序
+func sortArray(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockArray(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, ArrayResponse{
+			Success: false,
+			Message: "数组不存在",
+		})
+	}
+	array := res.header()
+	defer array.mu.Unlock()
+
+	if !ifMatchSatisfied(c, array.Version) {
+		setETag(c, array.Version)
+		return c.JSON(http.StatusPreconditionFailed, ArrayResponse{
+			Success: false,
+			Message: fmt.Sprintf("数组已被修改（当前版本%d），请刷新后重试", array.Version),
+			Array:   res,
+		})
+	}
+
+	var req SortRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	// 试运行：在副本上排序，不计入数组的统计和历史，也不递增版本号
+	if req.DryRun {
+		result, recorder, err := res.sortElements(req)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ArrayResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+		cost := recorder.Cost()
+
+		setETag(c, array.Version)
+		return c.JSON(http.StatusOK, ArrayResponse{
+			Success: true,
+			Message: fmt.Sprintf("试运行%s排序完成，比较%d次，交换%d次", req.Algorithm, cost.Comparisons, cost.Swaps),
+			Array:   res,
+			Data:    result,
+			Cost:    &cost,
+			Trace:   recorder.Trace(),
+		})
+	}
+
+	before := res.cloneElements()
+	array.beginOperation()
+	result, _, err := res.sortElements(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ArrayResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	cost := array.endOperation("sort", 0, 0)
+	res.recordReplace("sort", before)
+
+	if err := commitArray(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, ArrayResponse{
+			Success: false,
+			Message: "数组保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, ArrayResponse{
+		Success: true,
+		Message: fmt.Sprintf("%s排序完成，比较%d次，交换%d次", req.Algorithm, cost.Comparisons, cost.Swaps),
+		Array:   res,
+		Data:    result,
+		Cost:    cost,
+		Trace:   array.trace(),
+	})
+}
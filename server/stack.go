@@ -0,0 +1,635 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// stackHeader 栈中与元素类型无关的服务端状态：标识、版本和锁
+type stackHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	recorder *ds.Recorder // 底层结构最近一次操作的计数和追踪
+
+	mu      sync.Mutex // 串行化对同一栈的操作，不同栈之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// Stack 栈结构体，操作由 ds.Stack 实现；同时给出抽象的栈视图和底层存储视图
+type Stack[T any] struct {
+	stackHeader
+	*ds.Stack[T]
+
+	// 抽象视图
+	Size  int `json:"size"`
+	Items []T `json:"items"` // 自底向顶
+	Top   *T  `json:"top"`   // 空栈为 null
+
+	Storage StackStorage[T] `json:"storage"`
+}
+
+// StackStorage 栈的底层存储视图，按底层结构只填写其中一项
+type StackStorage[T any] struct {
+	Array *ds.Array[T]    `json:"array,omitempty"` // 末尾元素为栈顶
+	List  *StorageList[T] `json:"list,omitempty"`  // 头节点为栈顶
+}
+
+// StorageList 作为底层存储的链表视图
+type StorageList[T any] struct {
+	Type  string         `json:"type"`
+	Size  int            `json:"size"`
+	Nodes []*NodeData[T] `json:"nodes"`
+}
+
+// stackResource 与元素类型无关的栈接口，处理函数通过它操作任意元素类型的 Stack[T]
+type stackResource interface {
+	header() *stackHeader
+	decodeValue(raw json.RawMessage) (any, error)
+	beginOperation()
+	pushAny(value any) (*ds.ResizeEvent, error)
+	popAny() (any, *ds.ResizeEvent, error)
+	peekAny() (any, error)
+	clearAll()
+	length() int
+	updateVisualizationData()
+	snapshot() any
+}
+
+// StackRequest 创建栈的请求，backing 为 array 时可以指定底层数组的容量和扩缩容参数
+type StackRequest struct {
+	Name            string  `json:"name"`
+	ElementType     string  `json:"elementType"`
+	Backing         string  `json:"backing"` // array（默认）或 list
+	Capacity        int     `json:"capacity"`
+	GrowthStrategy  string  `json:"growthStrategy"`
+	GrowthIncrement int     `json:"growthIncrement"`
+	ShrinkFactor    float64 `json:"shrinkFactor"`
+}
+
+// StackValueRequest 压栈请求，Value 按栈的元素类型解码
+type StackValueRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// StackResponse 栈操作响应结构体
+type StackResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Stack   stackResource     `json:"stack,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Resize  *ds.ResizeEvent   `json:"resize,omitempty"`
+	Cost    *ds.OperationCost `json:"cost,omitempty"`
+	Trace   []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局栈存储，后端由 initStorage 根据配置选择
+var stacks Storage[stackResource] = newMemoryStorage[stackResource]("stack")
+
+// 获取栈并加锁，调用方负责解锁；栈不存在或已被删除时返回 false
+func lockStack(id string) (stackResource, bool) {
+	res, exists := stacks.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	stack := res.header()
+	stack.mu.Lock()
+	if stack.removed {
+		stack.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitStack(c echo.Context, res stackResource) error {
+	stack := res.header()
+	stack.Version++
+	if err := stacks.Save(stack.ID, res); err != nil {
+		return err
+	}
+	setETag(c, stack.Version)
+	return nil
+}
+
+// 底层数组的配置
+func (req StackRequest) arrayConfig() ds.ArrayConfig {
+	return ds.ArrayConfig{
+		Capacity:        req.Capacity,
+		GrowthStrategy:  req.GrowthStrategy,
+		GrowthIncrement: req.GrowthIncrement,
+		ShrinkFactor:    req.ShrinkFactor,
+	}
+}
+
+// 按请求创建元素类型为 T 的空栈，底层结构或数组参数无效时返回错误；ID 由调用方在校验通过后分配
+func newStack[T any](req StackRequest, kind *ds.ElementType[T]) (*Stack[T], error) {
+	core, err := ds.NewStack(req.Backing, req.arrayConfig(), kind)
+	if err != nil {
+		return nil, err
+	}
+	return wrapStack("", req.Name, core), nil
+}
+
+// 为 ds.Stack 附加服务端状态
+func wrapStack[T any](id, name string, core *ds.Stack[T]) *Stack[T] {
+	stack := &Stack[T]{Stack: core}
+	stack.ID = id
+	stack.Name = name
+	stack.ElementType = core.Kind().Name
+	stack.recorder = core.Recorder()
+	stack.updateVisualizationData()
+	return stack
+}
+
+// 栈的持久化快照，元素按自底向顶的顺序保存
+type stackSnapshot[T any] struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	ElementType string         `json:"elementType"`
+	Version     int64          `json:"version"`
+	Backing     string         `json:"backing"`
+	Array       ds.ArrayConfig `json:"array"` // 底层数组的当前容量和扩缩容参数
+	Values      []T            `json:"values"`
+}
+
+// 生成栈快照
+func snapshotStack(res stackResource) any {
+	return res.snapshot()
+}
+
+func (stack *Stack[T]) snapshot() any {
+	snapshot := stackSnapshot[T]{
+		ID:          stack.ID,
+		Name:        stack.Name,
+		ElementType: stack.ElementType,
+		Version:     stack.Version,
+		Backing:     stack.Backing,
+		Values:      stack.Values(),
+	}
+	if array := stack.Array(); array != nil {
+		snapshot.Array = array.Config()
+	}
+	return snapshot
+}
+
+// 从快照恢复栈，按快照中的元素类型分发
+func restoreStack(data []byte) (stackResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreStack(data)
+}
+
+// 从快照重建元素类型为 T 的栈
+func restoreTypedStack[T any](data []byte, kind *ds.ElementType[T]) (*Stack[T], error) {
+	var snapshot stackSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreStack(snapshot.Backing, snapshot.Array, snapshot.Values, kind)
+	if err != nil {
+		return nil, err
+	}
+	stack := wrapStack(snapshot.ID, snapshot.Name, core)
+	stack.Version = snapshot.Version
+	return stack, nil
+}
+
+func (stack *Stack[T]) header() *stackHeader {
+	return &stack.stackHeader
+}
+
+func (stack *Stack[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return stack.Kind().Decode(raw)
+}
+
+func (stack *Stack[T]) beginOperation() {
+	stack.Begin()
+}
+
+func (stack *Stack[T]) pushAny(value any) (*ds.ResizeEvent, error) {
+	return stack.Push(valueOf[T](value))
+}
+
+func (stack *Stack[T]) popAny() (any, *ds.ResizeEvent, error) {
+	return stack.Pop()
+}
+
+func (stack *Stack[T]) peekAny() (any, error) {
+	return stack.Peek()
+}
+
+func (stack *Stack[T]) clearAll() {
+	stack.Clear()
+}
+
+func (stack *Stack[T]) length() int {
+	return stack.Len()
+}
+
+// 更新栈的抽象视图和底层存储视图
+func (stack *Stack[T]) updateVisualizationData() {
+	stack.Items = stack.Values()
+	stack.Size = len(stack.Items)
+	stack.Top = nil
+	if stack.Size > 0 {
+		stack.Top = &stack.Items[stack.Size-1]
+	}
+
+	stack.Storage = StackStorage[T]{Array: stack.Array()}
+	if list := stack.List(); list != nil {
+		stack.Storage.List = &StorageList[T]{
+			Type:  list.Type,
+			Size:  list.Size,
+			Nodes: nodeViews(stack.ID, list),
+		}
+	}
+}
+
+// 设置栈相关路由
+func setupStackRoutes(g *echo.Group) {
+	stackGroup := g.Group("/stacks")
+
+	// 创建栈
+	stackGroup.POST("", createStack)
+
+	// 获取所有栈
+	stackGroup.GET("", getAllStacks)
+
+	// 获取指定栈
+	stackGroup.GET("/:id", getStack)
+
+	// 删除栈
+	stackGroup.DELETE("/:id", deleteStack)
+
+	// 压栈
+	stackGroup.POST("/:id/push", pushStack)
+
+	// 出栈
+	stackGroup.POST("/:id/pop", popStack)
+
+	// 查看栈顶
+	stackGroup.GET("/:id/peek", peekStack)
+
+	// 获取元素个数
+	stackGroup.GET("/:id/size", getStackSize)
+
+	// 清空栈
+	stackGroup.POST("/:id/clear", clearStack)
+}
+
+// 创建栈
+func createStack(c echo.Context) error {
+	var req StackRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, StackResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, StackResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	req.ElementType = elementType
+
+	res, err := factory.newStack(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, StackResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	id, err := stacks.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, StackResponse{
+			Success: false,
+			Message: "栈ID生成失败",
+		})
+	}
+	stack := res.header()
+	stack.ID = id
+	res.updateVisualizationData()
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	stack.mu.Lock()
+	defer stack.mu.Unlock()
+
+	if err := commitStack(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, StackResponse{
+			Success: false,
+			Message: "栈保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, StackResponse{
+		Success: true,
+		Message: "栈创建成功",
+		Stack:   res,
+	})
+}
+
+// 获取所有栈
+func getAllStacks(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的栈
+	stackList := make([]json.RawMessage, 0)
+	for _, res := range stacks.List() {
+		stack := res.header()
+		stack.mu.Lock()
+		data, err := json.Marshal(res)
+		stack.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		stackList = append(stackList, data)
+	}
+
+	return c.JSON(http.StatusOK, StackResponse{
+		Success: true,
+		Message: "获取栈列表成功",
+		Data:    stackList,
+	})
+}
+
+// 获取指定栈
+func getStack(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockStack(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, StackResponse{
+			Success: false,
+			Message: "栈不存在",
+		})
+	}
+	stack := res.header()
+	defer stack.mu.Unlock()
+
+	setETag(c, stack.Version)
+	return c.JSON(http.StatusOK, StackResponse{
+		Success: true,
+		Message: "获取栈成功",
+		Stack:   res,
+	})
+}
+
+// 删除栈
+func deleteStack(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockStack(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, StackResponse{
+			Success: false,
+			Message: "栈不存在",
+		})
+	}
+	stack := res.header()
+	defer stack.mu.Unlock()
+
+	if _, err := stacks.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, StackResponse{
+			Success: false,
+			Message: "栈删除失败",
+		})
+	}
+	stack.removed = true
+
+	return c.JSON(http.StatusOK, StackResponse{
+		Success: true,
+		Message: "栈删除成功",
+	})
+}
+
+// 压栈
+func pushStack(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockStack(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, StackResponse{
+			Success: false,
+			Message: "栈不存在",
+		})
+	}
+	stack := res.header()
+	defer stack.mu.Unlock()
+
+	if !ifMatchSatisfied(c, stack.Version) {
+		setETag(c, stack.Version)
+		return c.JSON(http.StatusPreconditionFailed, StackResponse{
+			Success: false,
+			Message: fmt.Sprintf("栈已被修改（当前版本%d），请刷新后重试", stack.Version),
+			Stack:   res,
+		})
+	}
+
+	var req StackValueRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, StackResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, StackResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	res.beginOperation()
+	resize, err := res.pushAny(value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, StackResponse{
+			Success: false,
+			Message: "栈已满，无法压栈",
+		})
+	}
+	cost := stack.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitStack(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, StackResponse{
+			Success: false,
+			Message: "栈保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, StackResponse{
+		Success: true,
+		Message: withResizeMessage(fmt.Sprintf("%v已压栈", value), resize),
+		Stack:   res,
+		Resize:  resize,
+		Cost:    &cost,
+		Trace:   stack.recorder.Trace(),
+	})
+}
+
+// 出栈
+func popStack(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockStack(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, StackResponse{
+			Success: false,
+			Message: "栈不存在",
+		})
+	}
+	stack := res.header()
+	defer stack.mu.Unlock()
+
+	if !ifMatchSatisfied(c, stack.Version) {
+		setETag(c, stack.Version)
+		return c.JSON(http.StatusPreconditionFailed, StackResponse{
+			Success: false,
+			Message: fmt.Sprintf("栈已被修改（当前版本%d），请刷新后重试", stack.Version),
+			Stack:   res,
+		})
+	}
+
+	res.beginOperation()
+	value, resize, err := res.popAny()
+	if errors.Is(err, ds.ErrEmpty) {
+		return c.JSON(http.StatusBadRequest, StackResponse{
+			Success: false,
+			Message: "栈为空，无法出栈",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := stack.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitStack(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, StackResponse{
+			Success: false,
+			Message: "栈保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, StackResponse{
+		Success: true,
+		Message: withResizeMessage(fmt.Sprintf("栈顶元素%v已出栈", value), resize),
+		Stack:   res,
+		Data:    value,
+		Resize:  resize,
+		Cost:    &cost,
+		Trace:   stack.recorder.Trace(),
+	})
+}
+
+// 查看栈顶元素
+func peekStack(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockStack(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, StackResponse{
+			Success: false,
+			Message: "栈不存在",
+		})
+	}
+	stack := res.header()
+	defer stack.mu.Unlock()
+
+	res.beginOperation()
+	value, err := res.peekAny()
+	if errors.Is(err, ds.ErrEmpty) {
+		return c.JSON(http.StatusNotFound, StackResponse{
+			Success: false,
+			Message: "栈为空",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	cost := stack.recorder.Cost()
+
+	return c.JSON(http.StatusOK, StackResponse{
+		Success: true,
+		Message: fmt.Sprintf("栈顶元素为%v", value),
+		Stack:   res,
+		Data:    value,
+		Cost:    &cost,
+		Trace:   stack.recorder.Trace(),
+	})
+}
+
+// 获取栈的元素个数
+func getStackSize(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockStack(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, StackResponse{
+			Success: false,
+			Message: "栈不存在",
+		})
+	}
+	defer res.header().mu.Unlock()
+
+	size := res.length()
+	return c.JSON(http.StatusOK, StackResponse{
+		Success: true,
+		Message: fmt.Sprintf("栈中有%d个元素", size),
+		Data:    size,
+	})
+}
+
+// 清空栈
+func clearStack(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockStack(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, StackResponse{
+			Success: false,
+			Message: "栈不存在",
+		})
+	}
+	stack := res.header()
+	defer stack.mu.Unlock()
+
+	if !ifMatchSatisfied(c, stack.Version) {
+		setETag(c, stack.Version)
+		return c.JSON(http.StatusPreconditionFailed, StackResponse{
+			Success: false,
+			Message: fmt.Sprintf("栈已被修改（当前版本%d），请刷新后重试", stack.Version),
+			Stack:   res,
+		})
+	}
+
+	res.beginOperation()
+	res.clearAll()
+	res.updateVisualizationData()
+
+	if err := commitStack(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, StackResponse{
+			Success: false,
+			Message: "栈保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, StackResponse{
+		Success: true,
+		Message: "栈已清空",
+		Stack:   res,
+		Trace:   stack.recorder.Trace(),
+	})
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 存储后端类型
+const (
+	StorageMemory = "memory"
+	StorageFile   = "file"
+)
+
+// 默认的文件存储目录
+const defaultDataDir = "data"
+
+// Storage 数据结构存储接口，每种数据结构各用一个实例；实现需保证并发安全，
+// 但只保护索引本身，单个数据结构的读写由其自身的锁串行化
+type Storage[T any] interface {
+	// NextID 生成一个新的数据结构ID
+	NextID() (string, error)
+	// Get 按ID获取数据结构
+	Get(id string) (T, bool)
+	// Save 保存（新建或更新）数据结构
+	Save(id string, item T) error
+	// Delete 删除数据结构，不存在时返回 false
+	Delete(id string) (bool, error)
+	// List 返回所有数据结构
+	List() []T
+}
+
+// 内存存储：进程重启后数据丢失
+type memoryStorage[T any] struct {
+	mu      sync.RWMutex
+	prefix  string
+	counter int
+	items   map[string]T
+}
+
+// 创建内存存储，prefix 为生成ID的前缀
+func newMemoryStorage[T any](prefix string) *memoryStorage[T] {
+	return &memoryStorage[T]{
+		prefix: prefix,
+		items:  make(map[string]T),
+	}
+}
+
+func (s *memoryStorage[T]) NextID() (string, error) {
+	id, _ := s.nextID()
+	return id, nil
+}
+
+// 递增计数器，返回新ID及其编号
+func (s *memoryStorage[T]) nextID() (string, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counter++
+	return fmt.Sprintf("%s_%d", s.prefix, s.counter), s.counter
+}
+
+func (s *memoryStorage[T]) Get(id string) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, ok := s.items[id]
+	return item, ok
+}
+
+func (s *memoryStorage[T]) Save(id string, item T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[id] = item
+	return nil
+}
+
+func (s *memoryStorage[T]) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[id]; !ok {
+		return false, nil
+	}
+	delete(s.items, id)
+	return true, nil
+}
+
+func (s *memoryStorage[T]) List() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]T, 0, len(s.items))
+	for _, item := range s.items {
+		list = append(list, item)
+	}
+	return list
+}
+
+// 文件存储：内存中保留一份副本，每个数据结构以一个 JSON 文件保存在 dir 下，启动时全部载入
+type fileStorage[T any] struct {
+	*memoryStorage[T]
+	counterMu sync.Mutex // 串行化计数器文件的写入
+	dir       string
+	encode    func(T) any
+	decode    func([]byte) (T, error)
+}
+
+// 计数器文件名，记录已分配的最大ID编号，避免重启后复用已删除的ID
+const counterFile = "counter"
+
+// 创建文件存储并载入目录中已有的数据，encode/decode 负责数据结构与快照之间的转换
+func newFileStorage[T any](prefix, dir string, encode func(T) any, decode func([]byte) (T, error)) (*fileStorage[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &fileStorage[T]{
+		memoryStorage: newMemoryStorage[T](prefix),
+		dir:           dir,
+		encode:        encode,
+		decode:        decode,
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, counterFile)); err == nil {
+		s.counter, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		item, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("载入%s失败: %w", name, err)
+		}
+
+		id := strings.TrimSuffix(name, ".json")
+		s.items[id] = item
+
+		// 兼容计数器文件缺失的情况
+		if n, err := strconv.Atoi(strings.TrimPrefix(id, prefix+"_")); err == nil && n > s.counter {
+			s.counter = n
+		}
+	}
+
+	return s, nil
+}
+
+func (s *fileStorage[T]) NextID() (string, error) {
+	s.counterMu.Lock()
+	defer s.counterMu.Unlock()
+
+	id, counter := s.nextID()
+	if err := writeFileAtomic(filepath.Join(s.dir, counterFile), []byte(strconv.Itoa(counter))); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *fileStorage[T]) Save(id string, item T) error {
+	data, err := json.Marshal(s.encode(item))
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path(id), data); err != nil {
+		return err
+	}
+	return s.memoryStorage.Save(id, item)
+}
+
+func (s *fileStorage[T]) Delete(id string) (bool, error) {
+	ok, _ := s.memoryStorage.Delete(id)
+	if !ok {
+		return false, nil
+	}
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return true, err
+	}
+	return true, nil
+}
+
+// 数据结构对应的文件路径
+func (s *fileStorage[T]) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// 先写临时文件再重命名，避免进程中途退出留下不完整的文件
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// 根据环境变量选择存储后端：LINERA_STORAGE 为 memory（默认）或 file，LINERA_DATA_DIR 指定文件存储目录
+func initStorage() error {
+	backend := os.Getenv("LINERA_STORAGE")
+	if backend == "" {
+		backend = StorageMemory
+	}
+
+	switch backend {
+	case StorageMemory:
+		arrays = newMemoryStorage[arrayResource]("array")
+		linkedLists = newMemoryStorage[listResource]("list")
+		stacks = newMemoryStorage[stackResource]("stack")
+		queues = newMemoryStorage[dequeResource]("queue")
+		deques = newMemoryStorage[dequeResource]("deque")
+		heaps = newMemoryStorage[heapResource]("heap")
+		trees = newMemoryStorage[treeResource]("tree")
+		hashTables = newMemoryStorage[hashTableResource]("hashtable")
+		graphs = newMemoryStorage[graphResource]("graph")
+		tries = newMemoryStorage[*Trie]("trie")
+		disjointSets = newMemoryStorage[disjointSetResource]("disjointset")
+		rangeTrees = newMemoryStorage[*RangeTree]("rangetree")
+		skipLists = newMemoryStorage[skipListResource]("skiplist")
+	case StorageFile:
+		dataDir := os.Getenv("LINERA_DATA_DIR")
+		if dataDir == "" {
+			dataDir = defaultDataDir
+		}
+
+		arrayStorage, err := newFileStorage("array", filepath.Join(dataDir, "arrays"), snapshotArray, restoreArray)
+		if err != nil {
+			return err
+		}
+		listStorage, err := newFileStorage("list", filepath.Join(dataDir, "lists"), snapshotList, restoreList)
+		if err != nil {
+			return err
+		}
+		stackStorage, err := newFileStorage("stack", filepath.Join(dataDir, "stacks"), snapshotStack, restoreStack)
+		if err != nil {
+			return err
+		}
+		queueStorage, err := newFileStorage("queue", filepath.Join(dataDir, "queues"), snapshotDeque, restoreDeque)
+		if err != nil {
+			return err
+		}
+		dequeStorage, err := newFileStorage("deque", filepath.Join(dataDir, "deques"), snapshotDeque, restoreDeque)
+		if err != nil {
+			return err
+		}
+		heapStorage, err := newFileStorage("heap", filepath.Join(dataDir, "heaps"), snapshotHeap, restoreHeap)
+		if err != nil {
+			return err
+		}
+		treeStorage, err := newFileStorage("tree", filepath.Join(dataDir, "trees"), snapshotTree, restoreTree)
+		if err != nil {
+			return err
+		}
+		hashTableStorage, err := newFileStorage("hashtable", filepath.Join(dataDir, "hashtables"), snapshotHashTable, restoreHashTable)
+		if err != nil {
+			return err
+		}
+		graphStorage, err := newFileStorage("graph", filepath.Join(dataDir, "graphs"), snapshotGraph, restoreGraph)
+		if err != nil {
+			return err
+		}
+		trieStorage, err := newFileStorage("trie", filepath.Join(dataDir, "tries"), snapshotTrie, restoreTrie)
+		if err != nil {
+			return err
+		}
+		disjointSetStorage, err := newFileStorage("disjointset", filepath.Join(dataDir, "disjointsets"), snapshotDisjointSet, restoreDisjointSet)
+		if err != nil {
+			return err
+		}
+		rangeTreeStorage, err := newFileStorage("rangetree", filepath.Join(dataDir, "rangetrees"), snapshotRangeTree, restoreRangeTree)
+		if err != nil {
+			return err
+		}
+		skipListStorage, err := newFileStorage("skiplist", filepath.Join(dataDir, "skiplists"), snapshotSkipList, restoreSkipList)
+		if err != nil {
+			return err
+		}
+		arrays, linkedLists, stacks = arrayStorage, listStorage, stackStorage
+		queues, deques, heaps, trees = queueStorage, dequeStorage, heapStorage, treeStorage
+		hashTables, graphs, tries, disjointSets = hashTableStorage, graphStorage, trieStorage, disjointSetStorage
+		rangeTrees, skipLists = rangeTreeStorage, skipListStorage
+	default:
+		return fmt.Errorf("未知的存储后端: %s（可选 memory 或 file）", backend)
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 并发压力测试，建议配合 go test -race 运行
+const (
+	stressWorkers = 8
+	stressOps     = 200
+)
+
+func newTestServer() *echo.Echo {
+	e := echo.New()
+	api := e.Group("/api")
+	setupArrayRoutes(api)
+	setupLinkedListRoutes(api)
+	return e
+}
+
+func doRequest(e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func createList(t *testing.T, e *echo.Echo, listType string) string {
+	t.Helper()
+
+	rec := doRequest(e, http.MethodPost, "/api/lists", fmt.Sprintf(`{"name":"stress","type":%q}`, listType))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("创建链表失败: %d %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		List struct {
+			ID string `json:"id"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.List.ID
+}
+
+// 沿 Next 和 Prev 指针检查链表结构与 Size 一致
+func checkListIntegrity[T any](t *testing.T, list *LinkedList[T]) {
+	t.Helper()
+
+	if list.Size == 0 {
+		if list.Head != nil || list.Tail != nil {
+			t.Fatalf("空链表的 Head/Tail 应为 nil")
+		}
+		return
+	}
+
+	current := list.Head
+	for i := 1; i < list.Size; i++ {
+		if current.Next == nil {
+			t.Fatalf("第%d个节点后链表提前结束，Size=%d", i, list.Size)
+		}
+		if list.IsDoubly() && current.Next.Prev != current {
+			t.Fatalf("第%d个节点的 Prev 指针错误", i)
+		}
+		current = current.Next
+	}
+
+	if current != list.Tail {
+		t.Fatalf("第%d个节点不是 Tail", list.Size)
+	}
+	if list.IsCircular() && list.Tail.Next != list.Head {
+		t.Fatalf("循环链表的 Tail.Next 应指向 Head")
+	}
+	if !list.IsCircular() && list.Tail.Next != nil {
+		t.Fatalf("非循环链表的 Tail.Next 应为 nil")
+	}
+	if list.IsDoubly() && list.IsCircular() && list.Head.Prev != list.Tail {
+		t.Fatalf("双向循环链表的 Head.Prev 应指向 Tail")
+	}
+	if list.IsDoubly() && !list.IsCircular() && list.Head.Prev != nil {
+		t.Fatalf("双向链表的 Head.Prev 应为 nil")
+	}
+}
+
+func TestConcurrentListInsertDelete(t *testing.T) {
+	e := newTestServer()
+
+	for _, listType := range []string{ds.ListSingle, ds.ListDouble, ds.ListCircular, ds.ListDoubleCircular} {
+		t.Run(listType, func(t *testing.T) {
+			id := createList(t, e, listType)
+
+			var inserted, deleted atomic.Int64
+			var wg sync.WaitGroup
+			for w := 0; w < stressWorkers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < stressOps; i++ {
+						var rec *httptest.ResponseRecorder
+						switch i % 4 {
+						case 0:
+							rec = doRequest(e, http.MethodPost, "/api/lists/"+id+"/append", fmt.Sprintf(`{"value":%d}`, i))
+						case 1:
+							rec = doRequest(e, http.MethodPost, "/api/lists/"+id+"/insert", fmt.Sprintf(`{"value":%d,"index":1}`, w))
+						case 2:
+							rec = doRequest(e, http.MethodDelete, "/api/lists/"+id+"/index/0", "")
+						case 3:
+							rec = doRequest(e, http.MethodGet, "/api/lists/"+id, "")
+							continue
+						}
+
+						if rec.Code != http.StatusOK {
+							continue
+						}
+						if i%4 == 2 {
+							deleted.Add(1)
+						} else {
+							inserted.Add(1)
+						}
+					}
+				}(w)
+			}
+			wg.Wait()
+
+			res, ok := linkedLists.Get(id)
+			if !ok {
+				t.Fatalf("链表%s不存在", id)
+			}
+			list := res.(*LinkedList[int])
+			if want := int(inserted.Load() - deleted.Load()); list.Size != want {
+				t.Fatalf("Size=%d，期望%d", list.Size, want)
+			}
+			checkListIntegrity(t, list)
+		})
+	}
+}
+
+func TestConcurrentArrayInsertDelete(t *testing.T) {
+	e := newTestServer()
+
+	rec := doRequest(e, http.MethodPost, "/api/arrays", `{"name":"stress","capacity":1,"shrinkFactor":0.25}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("创建数组失败: %d %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Array struct {
+			ID string `json:"id"`
+		} `json:"array"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	id := resp.Array.ID
+
+	var inserted, deleted atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < stressWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < stressOps; i++ {
+				switch i % 4 {
+				case 0, 1:
+					if doRequest(e, http.MethodPost, "/api/arrays/"+id+"/append", `{"value":1}`).Code == http.StatusOK {
+						inserted.Add(1)
+					}
+				case 2:
+					if doRequest(e, http.MethodDelete, "/api/arrays/"+id+"/index/0", "").Code == http.StatusOK {
+						deleted.Add(1)
+					}
+				case 3:
+					doRequest(e, http.MethodGet, "/api/arrays", "")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	res, ok := arrays.Get(id)
+	if !ok {
+		t.Fatalf("数组%s不存在", id)
+	}
+	array := res.(*DynamicArray[int])
+	if want := int(inserted.Load() - deleted.Load()); array.Size != want || len(array.Elements) != want {
+		t.Fatalf("Size=%d，len=%d，期望%d", array.Size, len(array.Elements), want)
+	}
+	if array.Capacity < array.Size || cap(array.Elements) != array.Capacity {
+		t.Fatalf("容量不一致: Capacity=%d，cap=%d，Size=%d", array.Capacity, cap(array.Elements), array.Size)
+	}
+}
+
+func TestConcurrentCreateUniqueIDs(t *testing.T) {
+	e := newTestServer()
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < stressWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < stressOps/4; i++ {
+				rec := doRequest(e, http.MethodPost, "/api/arrays", `{}`)
+				var resp struct {
+					Array struct {
+						ID string `json:"id"`
+					} `json:"array"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Error(err)
+					return
+				}
+
+				mu.Lock()
+				if seen[resp.Array.ID] {
+					t.Errorf("重复的数组ID: %s", resp.Array.ID)
+				}
+				seen[resp.Array.ID] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// 观看连接在数组删除后结束，且不会遗留回放器
+func TestStreamEndsWhenArrayDeleted(t *testing.T) {
+	e := newTestServer()
+	id := createTestArray(t, e)
+	key := "arrays/" + id
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- doRequest(e, http.MethodGet, "/api/arrays/"+id+"/ops/stream", "")
+	}()
+
+	// 等待观看连接订阅回放器
+	for subscribed := false; !subscribed; {
+		playersMu.Lock()
+		if player, ok := players[key]; ok {
+			player.mu.Lock()
+			subscribed = len(player.subscribers) > 0
+			player.mu.Unlock()
+		}
+		playersMu.Unlock()
+		runtime.Gosched()
+	}
+
+	if rec := doRequest(e, http.MethodDelete, "/api/arrays/"+id, ""); rec.Code != http.StatusOK {
+		t.Fatalf("删除数组失败: %d %s", rec.Code, rec.Body.String())
+	}
+
+	select {
+	case rec := <-done:
+		if !strings.Contains(rec.Body.String(), "event: closed") {
+			t.Fatalf("观看连接没有收到 closed 事件: %s", rec.Body.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("数组删除后观看连接没有结束")
+	}
+
+	// 删除后再观看或控制回放都返回404，不会重新创建回放器
+	if rec := doRequest(e, http.MethodGet, "/api/arrays/"+id+"/ops/stream", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("观看已删除的数组返回 %d，期望404", rec.Code)
+	}
+	if rec := doRequest(e, http.MethodPost, "/api/arrays/"+id+"/ops/control", `{"action":"pause"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("控制已删除数组的回放返回 %d，期望404", rec.Code)
+	}
+	playersMu.Lock()
+	_, leaked := players[key]
+	playersMu.Unlock()
+	if leaked {
+		t.Fatalf("数组删除后仍遗留回放器 %s", key)
+	}
+}
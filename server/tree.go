@@ -0,0 +1,764 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TreeNodeData 用于前端显示的树节点数据，与链表的 NodeData 一样以节点ID表示指针
+type TreeNodeData[T any] struct {
+	ID       string `json:"id"`
+	Value    T      `json:"value"`
+	LeftID   string `json:"leftId,omitempty"`
+	RightID  string `json:"rightId,omitempty"`
+	ParentID string `json:"parentId,omitempty"`
+	Height   int    `json:"height,omitempty"`  // 仅 AVL 树：子树高度
+	Balance  *int   `json:"balance,omitempty"` // 仅 AVL 树：左子树高度减右子树高度
+	Color    string `json:"color,omitempty"`   // 仅红黑树：red 或 black
+}
+
+// treeHeader 树中与元素类型无关的服务端状态：标识、版本和锁
+type treeHeader struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Version     int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+
+	recorder *ds.Recorder // 最近一次操作的计数和追踪
+
+	mu      sync.Mutex // 串行化对同一棵树的操作，不同树之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// Tree 树结构体，操作由 ds.Tree 实现
+type Tree[T any] struct {
+	treeHeader
+	*ds.Tree[T]
+
+	RootID string             `json:"rootId,omitempty"`
+	Height int                `json:"height"` // 空树为0
+	Nodes  []*TreeNodeData[T] `json:"nodes"`  // 按层序排列
+}
+
+// treeResource 与元素类型无关的树接口，处理函数通过它操作任意元素类型的 Tree[T]
+type treeResource interface {
+	header() *treeHeader
+	parseValue(s string) (any, error)
+	decodeValue(raw json.RawMessage) (any, error)
+	beginOperation()
+	insertAny(value any) error
+	deleteAny(value any, replacement string) error
+	containsAny(value any) bool
+	minAny() (any, error)
+	maxAny() (any, error)
+	floorAny(value any) (any, error)
+	ceilingAny(value any) (any, error)
+	traverse(order string) (any, error)
+	updateVisualizationData()
+	snapshot() any
+}
+
+// TreeRequest 创建树的请求
+type TreeRequest struct {
+	Name        string `json:"name"`
+	ElementType string `json:"elementType"`
+	Type        string `json:"type"` // bst（默认）、avl 或 red_black
+}
+
+// TreeValueRequest 插入请求，Value 按树的元素类型解码
+type TreeValueRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// TreeResponse 树操作响应结构体
+type TreeResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Tree    treeResource      `json:"tree,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Cost    *ds.OperationCost `json:"cost,omitempty"`
+	Trace   []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局树存储，后端由 initStorage 根据配置选择
+var trees Storage[treeResource] = newMemoryStorage[treeResource]("tree")
+
+// 获取树并加锁，调用方负责解锁；树不存在或已被删除时返回 false
+func lockTree(id string) (treeResource, bool) {
+	res, exists := trees.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	tree := res.header()
+	tree.mu.Lock()
+	if tree.removed {
+		tree.mu.Unlock()
+		return nil, false
+	}
+	return res, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitTree(c echo.Context, res treeResource) error {
+	tree := res.header()
+	tree.Version++
+	if err := trees.Save(tree.ID, res); err != nil {
+		return err
+	}
+	setETag(c, tree.Version)
+	return nil
+}
+
+// 按请求创建元素类型为 T 的空树，树类型无效时返回错误；ID 由调用方在校验通过后分配
+func newTree[T any](req TreeRequest, kind *ds.ElementType[T]) (*Tree[T], error) {
+	core, err := ds.NewTree(req.Type, kind)
+	if err != nil {
+		return nil, err
+	}
+	return wrapTree("", req.Name, core), nil
+}
+
+// 为 ds.Tree 附加服务端状态
+func wrapTree[T any](id, name string, core *ds.Tree[T]) *Tree[T] {
+	tree := &Tree[T]{Tree: core}
+	tree.ID = id
+	tree.Name = name
+	tree.ElementType = core.Kind().Name
+	tree.recorder = &core.Recorder
+	tree.updateVisualizationData()
+	return tree
+}
+
+// 树的持久化快照，节点按先序保存
+type treeSnapshot[T any] struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	ElementType string                `json:"elementType"`
+	Version     int64                 `json:"version"`
+	Type        string                `json:"type"`
+	Nodes       []ds.SavedTreeNode[T] `json:"nodes"`
+}
+
+// 生成树快照
+func snapshotTree(res treeResource) any {
+	return res.snapshot()
+}
+
+func (tree *Tree[T]) snapshot() any {
+	return treeSnapshot[T]{
+		ID:          tree.ID,
+		Name:        tree.Name,
+		ElementType: tree.ElementType,
+		Version:     tree.Version,
+		Type:        tree.Type,
+		Nodes:       tree.Saved(),
+	}
+}
+
+// 从快照恢复树，按快照中的元素类型分发
+func restoreTree(data []byte) (treeResource, error) {
+	factory, err := snapshotElementType(data)
+	if err != nil {
+		return nil, err
+	}
+	return factory.restoreTree(data)
+}
+
+// 从快照重建元素类型为 T 的树
+func restoreTypedTree[T any](data []byte, kind *ds.ElementType[T]) (*Tree[T], error) {
+	var snapshot treeSnapshot[T]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreTree(snapshot.Type, snapshot.Nodes, kind)
+	if err != nil {
+		return nil, err
+	}
+	tree := wrapTree(snapshot.ID, snapshot.Name, core)
+	tree.Version = snapshot.Version
+	return tree, nil
+}
+
+func (tree *Tree[T]) header() *treeHeader {
+	return &tree.treeHeader
+}
+
+func (tree *Tree[T]) parseValue(s string) (any, error) {
+	return tree.Kind().Parse(s)
+}
+
+func (tree *Tree[T]) decodeValue(raw json.RawMessage) (any, error) {
+	return tree.Kind().Decode(raw)
+}
+
+func (tree *Tree[T]) beginOperation() {
+	tree.Begin()
+}
+
+func (tree *Tree[T]) insertAny(value any) error {
+	return tree.Insert(valueOf[T](value))
+}
+
+func (tree *Tree[T]) deleteAny(value any, replacement string) error {
+	return tree.Delete(valueOf[T](value), replacement)
+}
+
+func (tree *Tree[T]) containsAny(value any) bool {
+	return tree.Contains(valueOf[T](value))
+}
+
+func (tree *Tree[T]) minAny() (any, error) {
+	return tree.Min()
+}
+
+func (tree *Tree[T]) maxAny() (any, error) {
+	return tree.Max()
+}
+
+func (tree *Tree[T]) floorAny(value any) (any, error) {
+	return tree.Floor(valueOf[T](value))
+}
+
+func (tree *Tree[T]) ceilingAny(value any) (any, error) {
+	return tree.Ceiling(valueOf[T](value))
+}
+
+func (tree *Tree[T]) traverse(order string) (any, error) {
+	return tree.Traverse(order)
+}
+
+// 前端显示的节点ID
+func (tree *Tree[T]) nodeID(node *ds.TreeNode[T]) string {
+	if node == nil {
+		return ""
+	}
+	return generateNodeID(tree.ID, node.ID)
+}
+
+// 按层序生成节点视图，并计算树高
+func (tree *Tree[T]) updateVisualizationData() {
+	tree.Nodes = make([]*TreeNodeData[T], 0, tree.Size)
+	tree.RootID = tree.nodeID(tree.Root)
+	tree.Height = 0
+
+	level := []*ds.TreeNode[T]{}
+	if tree.Root != nil {
+		level = append(level, tree.Root)
+	}
+	for len(level) > 0 {
+		tree.Height++
+		next := make([]*ds.TreeNode[T], 0, 2*len(level))
+		for _, node := range level {
+			nodeData := &TreeNodeData[T]{
+				ID:       tree.nodeID(node),
+				Value:    node.Value,
+				LeftID:   tree.nodeID(node.Left),
+				RightID:  tree.nodeID(node.Right),
+				ParentID: tree.nodeID(node.Parent),
+				Color:    node.Color,
+			}
+			if tree.Type == ds.TreeAVL {
+				balance := node.BalanceFactor()
+				nodeData.Height = node.Height
+				nodeData.Balance = &balance
+			}
+			tree.Nodes = append(tree.Nodes, nodeData)
+			if node.Left != nil {
+				next = append(next, node.Left)
+			}
+			if node.Right != nil {
+				next = append(next, node.Right)
+			}
+		}
+		level = next
+	}
+}
+
+// 设置树相关路由
+func setupTreeRoutes(g *echo.Group) {
+	treeGroup := g.Group("/trees")
+
+	// 创建树
+	treeGroup.POST("", createTree)
+
+	// 获取所有树
+	treeGroup.GET("", getAllTrees)
+
+	// 获取指定树
+	treeGroup.GET("/:id", getTree)
+
+	// 删除树
+	treeGroup.DELETE("/:id", deleteTree)
+
+	// 插入节点
+	treeGroup.POST("/:id/insert", insertTreeNode)
+
+	// 按值删除节点（?replacement=successor|predecessor）
+	treeGroup.DELETE("/:id/value/:value", deleteTreeNode)
+
+	// 查找节点
+	treeGroup.GET("/:id/find/:value", findTreeNode)
+
+	// 最小值和最大值
+	treeGroup.GET("/:id/min", getTreeMin)
+	treeGroup.GET("/:id/max", getTreeMax)
+
+	// 不大于和不小于给定值的最接近元素
+	treeGroup.GET("/:id/floor/:value", getTreeFloor)
+	treeGroup.GET("/:id/ceiling/:value", getTreeCeiling)
+
+	// 遍历（inorder、preorder、postorder、levelorder）
+	treeGroup.GET("/:id/traverse/:order", traverseTree)
+}
+
+// 创建树
+func createTree(c echo.Context) error {
+	var req TreeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	factory, elementType, err := elementFactoryFor(req.ElementType)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	req.ElementType = elementType
+
+	res, err := factory.newTree(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	id, err := trees.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, TreeResponse{
+			Success: false,
+			Message: "树ID生成失败",
+		})
+	}
+	tree := res.header()
+	tree.ID = id
+	res.updateVisualizationData()
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	if err := commitTree(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, TreeResponse{
+			Success: false,
+			Message: "树保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, TreeResponse{
+		Success: true,
+		Message: "树创建成功",
+		Tree:    res,
+	})
+}
+
+// 获取所有树
+func getAllTrees(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的树
+	treeList := make([]json.RawMessage, 0)
+	for _, res := range trees.List() {
+		tree := res.header()
+		tree.mu.Lock()
+		data, err := json.Marshal(res)
+		tree.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		treeList = append(treeList, data)
+	}
+
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: "获取树列表成功",
+		Data:    treeList,
+	})
+}
+
+// 获取指定树
+func getTree(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树不存在",
+		})
+	}
+	tree := res.header()
+	defer tree.mu.Unlock()
+
+	setETag(c, tree.Version)
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: "获取树成功",
+		Tree:    res,
+	})
+}
+
+// 删除树
+func deleteTree(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树不存在",
+		})
+	}
+	tree := res.header()
+	defer tree.mu.Unlock()
+
+	if _, err := trees.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, TreeResponse{
+			Success: false,
+			Message: "树删除失败",
+		})
+	}
+	tree.removed = true
+
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: "树删除成功",
+	})
+}
+
+// 插入节点
+func insertTreeNode(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树不存在",
+		})
+	}
+	tree := res.header()
+	defer tree.mu.Unlock()
+
+	if !ifMatchSatisfied(c, tree.Version) {
+		setETag(c, tree.Version)
+		return c.JSON(http.StatusPreconditionFailed, TreeResponse{
+			Success: false,
+			Message: fmt.Sprintf("树已被修改（当前版本%d），请刷新后重试", tree.Version),
+			Tree:    res,
+		})
+	}
+
+	var req TreeValueRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	value, err := res.decodeValue(req.Value)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	res.beginOperation()
+	if err := res.insertAny(value); err != nil {
+		if errors.Is(err, ds.ErrDuplicate) {
+			return c.JSON(http.StatusBadRequest, TreeResponse{
+				Success: false,
+				Message: fmt.Sprintf("值为%v的节点已存在", value),
+				Trace:   tree.recorder.Trace(),
+			})
+		}
+		return err
+	}
+	cost := tree.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitTree(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, TreeResponse{
+			Success: false,
+			Message: "树保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: fmt.Sprintf("%v已插入树", value),
+		Tree:    res,
+		Cost:    &cost,
+		Trace:   tree.recorder.Trace(),
+	})
+}
+
+// 按值删除节点
+func deleteTreeNode(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树不存在",
+		})
+	}
+	tree := res.header()
+	defer tree.mu.Unlock()
+
+	if !ifMatchSatisfied(c, tree.Version) {
+		setETag(c, tree.Version)
+		return c.JSON(http.StatusPreconditionFailed, TreeResponse{
+			Success: false,
+			Message: fmt.Sprintf("树已被修改（当前版本%d），请刷新后重试", tree.Version),
+			Tree:    res,
+		})
+	}
+
+	value, err := res.parseValue(c.Param("value"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: fmt.Sprintf("值格式错误：%v", err),
+		})
+	}
+
+	res.beginOperation()
+	err = res.deleteAny(value, c.QueryParam("replacement"))
+	if errors.Is(err, ds.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到值为%v的节点", value),
+			Trace:   tree.recorder.Trace(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	cost := tree.recorder.Cost()
+	res.updateVisualizationData()
+
+	if err := commitTree(c, res); err != nil {
+		return c.JSON(http.StatusInternalServerError, TreeResponse{
+			Success: false,
+			Message: "树保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: fmt.Sprintf("成功删除值为%v的节点", value),
+		Tree:    res,
+		Data:    value,
+		Cost:    &cost,
+		Trace:   tree.recorder.Trace(),
+	})
+}
+
+// 查找节点
+func findTreeNode(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树不存在",
+		})
+	}
+	tree := res.header()
+	defer tree.mu.Unlock()
+
+	value, err := res.parseValue(c.Param("value"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: fmt.Sprintf("值格式错误：%v", err),
+		})
+	}
+
+	res.beginOperation()
+	found := res.containsAny(value)
+	cost := tree.recorder.Cost()
+	if !found {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: fmt.Sprintf("未找到值为%v的节点", value),
+			Cost:    &cost,
+			Trace:   tree.recorder.Trace(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: fmt.Sprintf("找到值为%v的节点，比较了%d次", value, cost.Comparisons),
+		Tree:    res,
+		Data:    value,
+		Cost:    &cost,
+		Trace:   tree.recorder.Trace(),
+	})
+}
+
+// 最小值
+func getTreeMin(c echo.Context) error {
+	return queryTreeExtreme(c, false)
+}
+
+// 最大值
+func getTreeMax(c echo.Context) error {
+	return queryTreeExtreme(c, true)
+}
+
+// 沿最左或最右路径找到最小值或最大值
+func queryTreeExtreme(c echo.Context, maximum bool) error {
+	id := c.Param("id")
+	res, exists := lockTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树不存在",
+		})
+	}
+	tree := res.header()
+	defer tree.mu.Unlock()
+
+	name, query := "最小值", res.minAny
+	if maximum {
+		name, query = "最大值", res.maxAny
+	}
+
+	res.beginOperation()
+	value, err := query()
+	if errors.Is(err, ds.ErrEmpty) {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树为空",
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: fmt.Sprintf("%s为%v", name, value),
+		Tree:    res,
+		Data:    value,
+		Trace:   tree.recorder.Trace(),
+	})
+}
+
+// 不大于给定值的最大元素
+func getTreeFloor(c echo.Context) error {
+	return queryTreeBound(c, false)
+}
+
+// 不小于给定值的最小元素
+func getTreeCeiling(c echo.Context) error {
+	return queryTreeBound(c, true)
+}
+
+// 沿查找路径记录候选节点，得到 floor 或 ceiling
+func queryTreeBound(c echo.Context, ceiling bool) error {
+	id := c.Param("id")
+	res, exists := lockTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树不存在",
+		})
+	}
+	tree := res.header()
+	defer tree.mu.Unlock()
+
+	value, err := res.parseValue(c.Param("value"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: fmt.Sprintf("值格式错误：%v", err),
+		})
+	}
+
+	relation, query := "不大于", res.floorAny
+	if ceiling {
+		relation, query = "不小于", res.ceilingAny
+	}
+
+	res.beginOperation()
+	bound, err := query(value)
+	cost := tree.recorder.Cost()
+	if errors.Is(err, ds.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: fmt.Sprintf("树中没有%s%v的元素", relation, value),
+			Cost:    &cost,
+			Trace:   tree.recorder.Trace(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: fmt.Sprintf("%s%v的最接近元素为%v", relation, value, bound),
+		Tree:    res,
+		Data:    bound,
+		Cost:    &cost,
+		Trace:   tree.recorder.Trace(),
+	})
+}
+
+// 按指定顺序遍历
+func traverseTree(c echo.Context) error {
+	id := c.Param("id")
+	res, exists := lockTree(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TreeResponse{
+			Success: false,
+			Message: "树不存在",
+		})
+	}
+	tree := res.header()
+	defer tree.mu.Unlock()
+
+	res.beginOperation()
+	values, err := res.traverse(c.Param("order"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TreeResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, TreeResponse{
+		Success: true,
+		Message: fmt.Sprintf("%s遍历完成", c.Param("order")),
+		Tree:    res,
+		Data:    values,
+		Trace:   tree.recorder.Trace(),
+	})
+}
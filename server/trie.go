@@ -0,0 +1,520 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"echo/ds"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TrieNodeData 用于前端显示的字典树节点，Prefix 为从根到该节点的路径
+type TrieNodeData struct {
+	ID       string `json:"id"`
+	Prefix   string `json:"prefix"`
+	Depth    int    `json:"depth"`
+	Terminal bool   `json:"terminal"` // 单词结尾标记
+	ParentID string `json:"parentId,omitempty"`
+}
+
+// TrieEdgeData 父节点指向子节点的边，Char 为边上的字符
+type TrieEdgeData struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Char string `json:"char"`
+}
+
+// Trie 字典树结构体，操作由 ds.Trie 实现；键固定为字符串，不区分元素类型
+type Trie struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version int64  `json:"version"` // 每次修改递增，作为 ETag 用于乐观并发控制
+	*ds.Trie
+
+	RootID string          `json:"rootId"`
+	Nodes  []*TrieNodeData `json:"nodes"` // 按先序排列，兄弟节点按字符升序
+	Edges  []*TrieEdgeData `json:"edges"`
+
+	mu      sync.Mutex // 串行化对同一棵字典树的操作，不同字典树之间互不阻塞
+	removed bool       // 已被删除，仍持有旧指针的请求据此返回不存在
+}
+
+// TrieRequest 创建字典树的请求，Words 为可选的初始单词
+type TrieRequest struct {
+	Name  string   `json:"name"`
+	Words []string `json:"words"`
+}
+
+// TrieWordRequest 插入单词的请求
+type TrieWordRequest struct {
+	Word string `json:"word"`
+}
+
+// TrieResponse 字典树操作响应结构体
+type TrieResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Trie    *Trie             `json:"trie,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Cost    *ds.OperationCost `json:"cost,omitempty"`
+	Trace   []ds.TraceStep    `json:"trace,omitempty"`
+}
+
+// 全局字典树存储，后端由 initStorage 根据配置选择
+var tries Storage[*Trie] = newMemoryStorage[*Trie]("trie")
+
+// 获取字典树并加锁，调用方负责解锁；字典树不存在或已被删除时返回 false
+func lockTrie(id string) (*Trie, bool) {
+	trie, exists := tries.Get(id)
+	if !exists {
+		return nil, false
+	}
+
+	trie.mu.Lock()
+	if trie.removed {
+		trie.mu.Unlock()
+		return nil, false
+	}
+	return trie, true
+}
+
+// 提交一次修改：递增版本号、持久化，并在响应头中返回新的 ETag
+func commitTrie(c echo.Context, trie *Trie) error {
+	trie.Version++
+	if err := tries.Save(trie.ID, trie); err != nil {
+		return err
+	}
+	setETag(c, trie.Version)
+	return nil
+}
+
+// 为 ds.Trie 附加服务端状态
+func wrapTrie(id, name string, core *ds.Trie) *Trie {
+	trie := &Trie{ID: id, Name: name, Trie: core}
+	trie.updateVisualizationData()
+	return trie
+}
+
+// 字典树的持久化快照，只保存单词，恢复时重新插入即可得到相同的结构
+type trieSnapshot struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Version int64    `json:"version"`
+	Words   []string `json:"words"`
+}
+
+// 生成字典树快照
+func snapshotTrie(trie *Trie) any {
+	return trieSnapshot{
+		ID:      trie.ID,
+		Name:    trie.Name,
+		Version: trie.Version,
+		Words:   trie.Words(),
+	}
+}
+
+// 从快照恢复字典树
+func restoreTrie(data []byte) (*Trie, error) {
+	var snapshot trieSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	core, err := ds.RestoreTrie(snapshot.Words)
+	if err != nil {
+		return nil, err
+	}
+	trie := wrapTrie(snapshot.ID, snapshot.Name, core)
+	trie.Version = snapshot.Version
+	return trie, nil
+}
+
+// 按先序生成节点和字符边，节点ID按先序编号
+func (trie *Trie) updateVisualizationData() {
+	trie.Nodes = make([]*TrieNodeData, 0, trie.NodeCount)
+	trie.Edges = make([]*TrieEdgeData, 0, trie.NodeCount)
+
+	var walk func(node *ds.TrieNode, prefix []rune, parentID string)
+	walk = func(node *ds.TrieNode, prefix []rune, parentID string) {
+		nodeData := &TrieNodeData{
+			ID:       generateNodeID(trie.ID, len(trie.Nodes)),
+			Prefix:   string(prefix),
+			Depth:    len(prefix),
+			Terminal: node.Terminal,
+			ParentID: parentID,
+		}
+		trie.Nodes = append(trie.Nodes, nodeData)
+		if parentID != "" {
+			trie.Edges = append(trie.Edges, &TrieEdgeData{From: parentID, To: nodeData.ID, Char: string(node.Char)})
+		}
+		for _, child := range node.Children {
+			walk(child, append(prefix, child.Char), nodeData.ID)
+		}
+	}
+	walk(trie.Root, nil, "")
+	trie.RootID = trie.Nodes[0].ID
+}
+
+// 设置字典树相关路由
+func setupTrieRoutes(g *echo.Group) {
+	trieGroup := g.Group("/tries")
+
+	// 创建字典树
+	trieGroup.POST("", createTrie)
+
+	// 获取所有字典树
+	trieGroup.GET("", getAllTries)
+
+	// 获取指定字典树
+	trieGroup.GET("/:id", getTrie)
+
+	// 删除字典树
+	trieGroup.DELETE("/:id", deleteTrie)
+
+	// 插入和删除单词
+	trieGroup.POST("/:id/words", insertTrieWord)
+	trieGroup.DELETE("/:id/words/:word", deleteTrieWord)
+
+	// 查找单词
+	trieGroup.GET("/:id/search/:word", searchTrieWord)
+
+	// 列出以 ?prefix= 开头的所有单词，前缀为空时列出全部
+	trieGroup.GET("/:id/prefix", listTriePrefix)
+
+	// 最长公共前缀
+	trieGroup.GET("/:id/lcp", trieLongestCommonPrefix)
+
+	// 自动补全（?prefix=&limit=）
+	trieGroup.GET("/:id/autocomplete", autocompleteTrie)
+}
+
+// 创建字典树
+func createTrie(c echo.Context) error {
+	var req TrieRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, TrieResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	core := ds.NewTrie()
+	for _, word := range req.Words {
+		if err := core.Insert(word); err != nil && !errors.Is(err, ds.ErrDuplicate) {
+			return c.JSON(http.StatusBadRequest, TrieResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+	}
+	core.Begin()
+
+	id, err := tries.NextID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, TrieResponse{
+			Success: false,
+			Message: "字典树ID生成失败",
+		})
+	}
+	trie := wrapTrie(id, req.Name, core)
+
+	// 保存后其他请求即可访问，需持锁直到响应序列化完成
+	trie.mu.Lock()
+	defer trie.mu.Unlock()
+
+	if err := commitTrie(c, trie); err != nil {
+		return c.JSON(http.StatusInternalServerError, TrieResponse{
+			Success: false,
+			Message: "字典树保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, TrieResponse{
+		Success: true,
+		Message: "字典树创建成功",
+		Trie:    trie,
+	})
+}
+
+// 获取所有字典树
+func getAllTries(c echo.Context) error {
+	// 逐个加锁序列化，避免读到正在修改的字典树
+	trieList := make([]json.RawMessage, 0)
+	for _, trie := range tries.List() {
+		trie.mu.Lock()
+		data, err := json.Marshal(trie)
+		trie.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		trieList = append(trieList, data)
+	}
+
+	return c.JSON(http.StatusOK, TrieResponse{
+		Success: true,
+		Message: "获取字典树列表成功",
+		Data:    trieList,
+	})
+}
+
+// 获取指定字典树
+func getTrie(c echo.Context) error {
+	trie, exists := lockTrie(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, TrieResponse{
+			Success: false,
+			Message: "字典树不存在",
+		})
+	}
+	defer trie.mu.Unlock()
+
+	setETag(c, trie.Version)
+	return c.JSON(http.StatusOK, TrieResponse{
+		Success: true,
+		Message: "获取字典树成功",
+		Trie:    trie,
+	})
+}
+
+// 删除字典树
+func deleteTrie(c echo.Context) error {
+	id := c.Param("id")
+	trie, exists := lockTrie(id)
+	if !exists {
+		return c.JSON(http.StatusNotFound, TrieResponse{
+			Success: false,
+			Message: "字典树不存在",
+		})
+	}
+	defer trie.mu.Unlock()
+
+	if _, err := tries.Delete(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, TrieResponse{
+			Success: false,
+			Message: "字典树删除失败",
+		})
+	}
+	trie.removed = true
+
+	return c.JSON(http.StatusOK, TrieResponse{
+		Success: true,
+		Message: "字典树删除成功",
+	})
+}
+
+// 获取字典树并检查 If-Match，失败时已写好响应，返回的 handled 为 true
+func lockTrieForUpdate(c echo.Context) (*Trie, bool, error) {
+	trie, exists := lockTrie(c.Param("id"))
+	if !exists {
+		return nil, true, c.JSON(http.StatusNotFound, TrieResponse{
+			Success: false,
+			Message: "字典树不存在",
+		})
+	}
+
+	if !ifMatchSatisfied(c, trie.Version) {
+		defer trie.mu.Unlock()
+		setETag(c, trie.Version)
+		return nil, true, c.JSON(http.StatusPreconditionFailed, TrieResponse{
+			Success: false,
+			Message: fmt.Sprintf("字典树已被修改（当前版本%d），请刷新后重试", trie.Version),
+			Trie:    trie,
+		})
+	}
+	return trie, false, nil
+}
+
+// 提交修改并返回字典树和本次操作的追踪
+func respondTrieUpdate(c echo.Context, trie *Trie, message string) error {
+	cost := trie.Cost()
+	trie.updateVisualizationData()
+
+	if err := commitTrie(c, trie); err != nil {
+		return c.JSON(http.StatusInternalServerError, TrieResponse{
+			Success: false,
+			Message: "字典树保存失败",
+		})
+	}
+
+	return c.JSON(http.StatusOK, TrieResponse{
+		Success: true,
+		Message: message,
+		Trie:    trie,
+		Cost:    &cost,
+		Trace:   trie.Trace(),
+	})
+}
+
+// 返回只读查询的结果和追踪，不修改版本
+func respondTrieQuery(c echo.Context, trie *Trie, message string, data any) error {
+	cost := trie.Cost()
+	setETag(c, trie.Version)
+	return c.JSON(http.StatusOK, TrieResponse{
+		Success: true,
+		Message: message,
+		Trie:    trie,
+		Data:    data,
+		Cost:    &cost,
+		Trace:   trie.Trace(),
+	})
+}
+
+// 插入单词
+func insertTrieWord(c echo.Context) error {
+	trie, handled, err := lockTrieForUpdate(c)
+	if handled {
+		return err
+	}
+	defer trie.mu.Unlock()
+
+	var req TrieWordRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, TrieResponse{
+			Success: false,
+			Message: "请求参数格式错误",
+		})
+	}
+
+	trie.Begin()
+	err = trie.Insert(req.Word)
+	if errors.Is(err, ds.ErrDuplicate) {
+		return c.JSON(http.StatusBadRequest, TrieResponse{
+			Success: false,
+			Message: fmt.Sprintf("单词%q已存在", req.Word),
+			Trace:   trie.Trace(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, TrieResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return respondTrieUpdate(c, trie, fmt.Sprintf("单词%q插入成功", req.Word))
+}
+
+// 删除单词
+func deleteTrieWord(c echo.Context) error {
+	trie, handled, err := lockTrieForUpdate(c)
+	if handled {
+		return err
+	}
+	defer trie.mu.Unlock()
+
+	word := c.Param("word")
+	trie.Begin()
+	if err := trie.Delete(word); err != nil {
+		if errors.Is(err, ds.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, TrieResponse{
+				Success: false,
+				Message: fmt.Sprintf("单词%q不存在", word),
+				Trace:   trie.Trace(),
+			})
+		}
+		return err
+	}
+
+	return respondTrieUpdate(c, trie, fmt.Sprintf("单词%q删除成功", word))
+}
+
+// 查找单词
+func searchTrieWord(c echo.Context) error {
+	trie, exists := lockTrie(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, TrieResponse{
+			Success: false,
+			Message: "字典树不存在",
+		})
+	}
+	defer trie.mu.Unlock()
+
+	word := c.Param("word")
+	trie.Begin()
+	if !trie.Search(word) {
+		cost := trie.Cost()
+		return c.JSON(http.StatusNotFound, TrieResponse{
+			Success: false,
+			Message: fmt.Sprintf("单词%q不存在", word),
+			Cost:    &cost,
+			Trace:   trie.Trace(),
+		})
+	}
+	return respondTrieQuery(c, trie, fmt.Sprintf("找到单词%q", word), word)
+}
+
+// 列出以指定前缀开头的所有单词
+func listTriePrefix(c echo.Context) error {
+	trie, exists := lockTrie(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, TrieResponse{
+			Success: false,
+			Message: "字典树不存在",
+		})
+	}
+	defer trie.mu.Unlock()
+
+	prefix := c.QueryParam("prefix")
+	trie.Begin()
+	words := trie.WithPrefix(prefix)
+	return respondTrieQuery(c, trie, fmt.Sprintf("以%q开头的单词共%d个", prefix, len(words)), map[string]interface{}{
+		"prefix": prefix,
+		"words":  words,
+	})
+}
+
+// 最长公共前缀
+func trieLongestCommonPrefix(c echo.Context) error {
+	trie, exists := lockTrie(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, TrieResponse{
+			Success: false,
+			Message: "字典树不存在",
+		})
+	}
+	defer trie.mu.Unlock()
+
+	trie.Begin()
+	prefix := trie.LongestCommonPrefix()
+	return respondTrieQuery(c, trie, fmt.Sprintf("最长公共前缀为%q", prefix), map[string]interface{}{
+		"prefix": prefix,
+	})
+}
+
+// 自动补全：较短的补全在前，至多返回 limit 个（默认10）
+func autocompleteTrie(c echo.Context) error {
+	trie, exists := lockTrie(c.Param("id"))
+	if !exists {
+		return c.JSON(http.StatusNotFound, TrieResponse{
+			Success: false,
+			Message: "字典树不存在",
+		})
+	}
+	defer trie.mu.Unlock()
+
+	limit := 10
+	if s := c.QueryParam("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return c.JSON(http.StatusBadRequest, TrieResponse{
+				Success: false,
+				Message: "limit必须是正整数",
+			})
+		}
+		limit = n
+	}
+
+	prefix := c.QueryParam("prefix")
+	trie.Begin()
+	words := trie.Autocomplete(prefix, limit)
+	return respondTrieQuery(c, trie, fmt.Sprintf("%q的补全%d个", prefix, len(words)), map[string]interface{}{
+		"prefix": prefix,
+		"limit":  limit,
+		"words":  words,
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 乐观并发控制使用的请求/响应头
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// 版本号对应的强 ETag
+func versionETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// 在响应头中返回当前版本的 ETag
+func setETag(c echo.Context, version int64) {
+	c.Response().Header().Set(HeaderETag, versionETag(version))
+}
+
+// 检查 If-Match 请求头：未携带时不做限制，"*" 匹配任意版本，否则需有一个 ETag 与当前版本一致
+func ifMatchSatisfied(c echo.Context, version int64) bool {
+	header := c.Request().Header.Get(HeaderIfMatch)
+	if header == "" {
+		return true
+	}
+
+	current := versionETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}